@@ -2,16 +2,29 @@ package main
 
 import (
 	cmd "github.com/proencaj/orthanc-cli/internal/commands"
+	"github.com/proencaj/orthanc-cli/internal/commands/api"
+	"github.com/proencaj/orthanc-cli/internal/commands/archive"
+	"github.com/proencaj/orthanc-cli/internal/commands/audit"
+	"github.com/proencaj/orthanc-cli/internal/commands/bulkdelete"
+	"github.com/proencaj/orthanc-cli/internal/commands/capabilities"
 	"github.com/proencaj/orthanc-cli/internal/commands/dicomweb"
+	"github.com/proencaj/orthanc-cli/internal/commands/health"
 	"github.com/proencaj/orthanc-cli/internal/commands/instances"
+	"github.com/proencaj/orthanc-cli/internal/commands/metrics"
 	"github.com/proencaj/orthanc-cli/internal/commands/modalities"
 	"github.com/proencaj/orthanc-cli/internal/commands/patients"
+	"github.com/proencaj/orthanc-cli/internal/commands/plugins"
 	"github.com/proencaj/orthanc-cli/internal/commands/series"
 	"github.com/proencaj/orthanc-cli/internal/commands/servers"
+	"github.com/proencaj/orthanc-cli/internal/commands/stats"
 	"github.com/proencaj/orthanc-cli/internal/commands/studies"
 	"github.com/proencaj/orthanc-cli/internal/commands/system"
 	"github.com/proencaj/orthanc-cli/internal/commands/tools"
+	"github.com/proencaj/orthanc-cli/internal/commands/transfer"
+	"github.com/proencaj/orthanc-cli/internal/commands/trash"
 	"github.com/proencaj/orthanc-cli/internal/commands/version"
+	"github.com/proencaj/orthanc-cli/internal/commands/worklists"
+	"github.com/proencaj/orthanc-cli/internal/fanout"
 )
 
 // Version information (injected at build time via ldflags)
@@ -46,12 +59,51 @@ func main() {
 	// Set up the client getter for system command to avoid import cycle
 	system.SetClientGetter(cmd.GetClient)
 
+	// Set up the client getter for stats command to avoid import cycle
+	stats.SetClientGetter(cmd.GetClient)
+
+	// Set up the client getter for health command to avoid import cycle
+	health.SetClientGetter(cmd.GetClient)
+
+	// Set up the client getter for metrics command to avoid import cycle
+	metrics.SetClientGetter(cmd.GetClient)
+
+	// Set up the client getter for plugins command to avoid import cycle
+	plugins.SetClientGetter(cmd.GetClient)
+
+	// Set up the client getter for worklists command to avoid import cycle
+	worklists.SetClientGetter(cmd.GetClient)
+
 	// Set up the client getter for dicomweb command to avoid import cycle
 	dicomweb.SetClientGetter(cmd.GetClient)
 
 	// Set up the client getter for servers command to avoid import cycle
 	servers.SetClientGetter(cmd.GetClient)
 
+	// Set up the client getter for capabilities command to avoid import cycle
+	capabilities.SetClientGetter(cmd.GetClient)
+
+	// Set up the client getter for api command to avoid import cycle
+	api.SetClientGetter(cmd.GetClient)
+
+	// Set up the client getter for archive command to avoid import cycle
+	archive.SetClientGetter(cmd.GetClient)
+
+	// Set up the client getter for delete command to avoid import cycle
+	bulkdelete.SetClientGetter(cmd.GetClient)
+
+	// Set up the client getter for trash command to avoid import cycle
+	trash.SetClientGetter(cmd.GetClient)
+
+	// Set up the client getters for copy, diff and sync commands to avoid import cycle
+	transfer.SetClientGetter(cmd.GetClient)
+	transfer.SetContextClientGetter(cmd.GetClientForContext)
+	transfer.SetTransferRecorder(cmd.RecordTransfer)
+
+	// Set up the getters for commands querying several contexts to avoid import cycle
+	fanout.SetConfigGetter(cmd.GetConfig)
+	fanout.SetClientGetter(cmd.GetClientForContext)
+
 	// Register commands
 	cmd.AddCommand(studies.NewStudiesCommand())
 	cmd.AddCommand(series.NewSeriesCommand())
@@ -59,8 +111,23 @@ func main() {
 	cmd.AddCommand(instances.NewInstancesCommand())
 	cmd.AddCommand(modalities.NewModalitiesCommand())
 	cmd.AddCommand(servers.NewServersCommand())
+	cmd.AddCommand(worklists.NewWorklistsCommand())
 	cmd.AddCommand(tools.NewToolsCommand())
 	cmd.AddCommand(system.NewSystemCommand())
+	cmd.AddCommand(stats.NewStatsCommand())
+	cmd.AddCommand(health.NewHealthCommand())
+	cmd.AddCommand(metrics.NewMetricsCommand())
+	cmd.AddCommand(capabilities.NewCapabilitiesCommand())
+	cmd.AddCommand(plugins.NewPluginsCommand())
+	cmd.AddCommand(api.NewAPICommand())
+	cmd.AddCommand(archive.NewArchiveCommand())
+	cmd.AddCommand(bulkdelete.NewDeleteCommand())
+	cmd.AddCommand(trash.NewTrashCommand())
+	cmd.AddCommand(trash.NewUndeleteCommand())
+	cmd.AddCommand(audit.NewAuditCommand())
+	cmd.AddCommand(transfer.NewCopyCommand())
+	cmd.AddCommand(transfer.NewDiffCommand())
+	cmd.AddCommand(transfer.NewSyncCommand())
 	cmd.AddCommand(version.NewVersionCommand())
 	cmd.AddCommand(dicomweb.NewDicomwebCommand())
 
@@ -0,0 +1,295 @@
+// Package audit keeps a local, append-only log of the commands that change an
+// Orthanc server, one JSON object per line: who ran what, against which
+// context, on which resources, and how it ended.
+package audit
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"os/user"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/proencaj/orthanc-cli/internal/clierr"
+	"github.com/proencaj/orthanc-cli/internal/config"
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+)
+
+// Outcomes of an audited command
+const (
+	OutcomeSuccess = "success"
+	OutcomeFailure = "failure"
+	OutcomePartial = "partial"
+	// OutcomeNoChange is a successful command that sent no change to the
+	// server, e.g. because it was cancelled at the confirmation prompt
+	OutcomeNoChange = "no-change"
+)
+
+// redacted replaces secret values
+const redacted = "********"
+
+// secretName matches the names of flags and fields holding secrets
+var secretName = regexp.MustCompile(`(?i)pass|secret|token|auth|cookie|credential`)
+
+// Request is a change sent to the server
+type Request struct {
+	Method string `json:"Method"`
+	Path   string `json:"Path"`
+	Status int    `json:"Status"`
+}
+
+// Entry is a line of the audit log
+type Entry struct {
+	Time        time.Time         `json:"Time"`
+	User        string            `json:"User"`
+	Host        string            `json:"Host"`
+	Context     string            `json:"Context,omitempty"`
+	Source      string            `json:"Source,omitempty"`
+	Destination string            `json:"Destination,omitempty"`
+	URL         string            `json:"URL,omitempty"`
+	Command     string            `json:"Command"`
+	Args        []string          `json:"Args,omitempty"`
+	Flags       map[string]string `json:"Flags,omitempty"`
+	Resources   []string          `json:"Resources,omitempty"`
+	Requests    []Request         `json:"Requests,omitempty"`
+	Outcome     string            `json:"Outcome"`
+	Error       string            `json:"Error,omitempty"`
+	ExitCode    int               `json:"ExitCode"`
+	DurationMs  int64             `json:"DurationMs"`
+}
+
+// Recorder collects the changes sent to the server while a command runs
+type Recorder struct {
+	started     time.Time
+	mu          sync.Mutex
+	requests    []Request
+	source      string
+	destination string
+}
+
+// NewRecorder creates a recorder, timing the command from now
+func NewRecorder() *Recorder {
+	return &Recorder{started: time.Now()}
+}
+
+// readOnlyPosts are the POST endpoints that only query the server
+var readOnlyPosts = map[string]bool{
+	"tools/find":   true,
+	"tools/lookup": true,
+}
+
+// Observe records a request if it may change the server; it is meant to be
+// passed to client.SetRequestObserver
+func (r *Recorder) Observe(method, path string, status int) {
+	if method == http.MethodGet || method == http.MethodHead {
+		return
+	}
+	if method == http.MethodPost && readOnlyPosts[strings.Trim(path, "/")] {
+		return
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.requests = append(r.requests, Request{Method: method, Path: path, Status: status})
+}
+
+// SetTransfer records the source and destination contexts of a command that
+// sends resources from one context to another
+func (r *Recorder) SetTransfer(source, destination string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.source, r.destination = source, destination
+}
+
+// Entry builds the audit entry of command, which ended with err
+func (r *Recorder) Entry(command *cobra.Command, cfg *config.Config, err error) *Entry {
+	r.mu.Lock()
+	requests := append([]Request(nil), r.requests...)
+	source, destination := r.source, r.destination
+	r.mu.Unlock()
+
+	entry := &Entry{
+		Time:        r.started.UTC(),
+		User:        currentUser(),
+		Command:     command.CommandPath(),
+		Args:        redactArgs(command.Flags().Args()),
+		Flags:       changedFlags(command),
+		Source:      source,
+		Destination: destination,
+		Resources:   resources(requests),
+		Requests:    requests,
+		ExitCode:    clierr.ExitCode(err),
+		DurationMs:  time.Since(r.started).Milliseconds(),
+	}
+	entry.Host, _ = os.Hostname()
+
+	if cfg != nil {
+		entry.Context = cfg.CurrentContext
+		if orthancCfg, err := cfg.GetCurrentContext(); err == nil {
+			entry.URL = orthancCfg.URL
+		}
+	}
+
+	switch {
+	case err == nil && len(requests) == 0:
+		entry.Outcome = OutcomeNoChange
+	case err == nil:
+		entry.Outcome = OutcomeSuccess
+	case clierr.KindOf(err) == clierr.KindPartial:
+		entry.Outcome = OutcomePartial
+		entry.Error = err.Error()
+	default:
+		entry.Outcome = OutcomeFailure
+		entry.Error = err.Error()
+	}
+
+	return entry
+}
+
+// currentUser returns the name of the OS user running the command
+func currentUser() string {
+	if u, err := user.Current(); err == nil && u.Username != "" {
+		return u.Username
+	}
+	if name := os.Getenv("USER"); name != "" {
+		return name
+	}
+	return os.Getenv("USERNAME")
+}
+
+// changedFlags returns the flags set on the command line, with secrets redacted
+func changedFlags(command *cobra.Command) map[string]string {
+	flags := map[string]string{}
+	command.Flags().Visit(func(flag *pflag.Flag) {
+		if secretName.MatchString(flag.Name) {
+			flags[flag.Name] = redacted
+			return
+		}
+		if values, ok := flag.Value.(pflag.SliceValue); ok {
+			flags[flag.Name] = strings.Join(redactArgs(values.GetSlice()), ",")
+			return
+		}
+		flags[flag.Name] = flag.Value.String()
+	})
+	if len(flags) == 0 {
+		return nil
+	}
+	return flags
+}
+
+// redactArgs hides the values of key=value and "Key: value" arguments whose key names a secret
+func redactArgs(args []string) []string {
+	result := make([]string, len(args))
+	for i, arg := range args {
+		result[i] = arg
+		if index := strings.IndexAny(arg, "=:"); index > 0 && secretName.MatchString(arg[:index]) {
+			result[i] = arg[:index+1] + redacted
+		}
+	}
+	return result
+}
+
+// resources returns the resources targeted by requests, e.g. studies/<id>
+func resources(requests []Request) []string {
+	var result []string
+	seen := map[string]bool{}
+	for _, request := range requests {
+		parts := strings.Split(strings.Trim(request.Path, "/"), "/")
+		if parts[0] == "dicom-web" {
+			parts = parts[1:]
+		}
+		if len(parts) < 2 {
+			continue
+		}
+		switch parts[0] {
+		case "patients", "studies", "series", "instances", "modalities", "servers", "peers", "jobs":
+			resource := parts[0] + "/" + parts[1]
+			if !seen[resource] {
+				seen[resource] = true
+				result = append(result, resource)
+			}
+		}
+	}
+	return result
+}
+
+// Path returns the audit log file: ORTHANC_AUDIT_LOG, or ~/.orthanc-cli/audit.jsonl
+func Path() (string, error) {
+	if path := os.Getenv("ORTHANC_AUDIT_LOG"); path != "" {
+		return path, nil
+	}
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", fmt.Errorf("failed to get home directory: %w", err)
+	}
+	return filepath.Join(home, ".orthanc-cli", "audit.jsonl"), nil
+}
+
+// Append adds an entry at the end of the audit log
+func Append(entry *Entry) error {
+	path, err := Path()
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+		return fmt.Errorf("failed to create audit log directory: %w", err)
+	}
+
+	data, err := json.Marshal(entry)
+	if err != nil {
+		return fmt.Errorf("failed to marshal audit entry: %w", err)
+	}
+
+	// A single write of the whole line keeps concurrent appends from interleaving
+	file, err := os.OpenFile(path, os.O_WRONLY|os.O_APPEND|os.O_CREATE, 0600)
+	if err != nil {
+		return fmt.Errorf("failed to open audit log: %w", err)
+	}
+	if _, err := file.Write(append(data, '\n')); err != nil {
+		file.Close()
+		return fmt.Errorf("failed to write audit log: %w", err)
+	}
+	return file.Close()
+}
+
+// Read returns the entries of the audit log accepted by keep, oldest first.
+// Lines that are not valid entries, such as a truncated last line, are skipped.
+func Read(keep func(*Entry) bool) ([]*Entry, error) {
+	path, err := Path()
+	if err != nil {
+		return nil, err
+	}
+
+	file, err := os.Open(path)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to open audit log: %w", err)
+	}
+	defer file.Close()
+
+	var entries []*Entry
+	scanner := bufio.NewScanner(file)
+	scanner.Buffer(make([]byte, 64*1024), 16*1024*1024)
+	for scanner.Scan() {
+		var entry Entry
+		if err := json.Unmarshal(scanner.Bytes(), &entry); err != nil {
+			continue
+		}
+		if keep == nil || keep(&entry) {
+			entries = append(entries, &entry)
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read audit log: %w", err)
+	}
+	return entries, nil
+}
@@ -0,0 +1,131 @@
+// Package batch applies a per-resource operation to several resource IDs
+// given as arguments or on stdin, with bounded concurrency and a single
+// aggregated outcome.
+package batch
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/proencaj/orthanc-cli/internal/clierr"
+	"github.com/proencaj/orthanc-cli/internal/parallel"
+)
+
+// Stdin is the argument standing for IDs read from stdin
+const Stdin = "-"
+
+// ReadIDs returns the resource IDs given as arguments, replacing "-" (or an
+// empty argument list) with the IDs read from stdin, one per line; blank lines,
+// # comments and duplicates are ignored
+func ReadIDs(args []string) ([]string, error) {
+	if len(args) == 0 {
+		args = []string{Stdin}
+	}
+
+	var ids []string
+	readStdin := false
+	for _, arg := range args {
+		if arg != Stdin {
+			ids = append(ids, arg)
+			continue
+		}
+		if readStdin {
+			return nil, clierr.Validation("%q can only be given once", Stdin)
+		}
+		readStdin = true
+
+		scanner := bufio.NewScanner(os.Stdin)
+		for scanner.Scan() {
+			line := strings.TrimSpace(scanner.Text())
+			if line == "" || strings.HasPrefix(line, "#") {
+				continue
+			}
+			ids = append(ids, line)
+		}
+		if err := scanner.Err(); err != nil {
+			return nil, fmt.Errorf("failed to read resource IDs from stdin: %w", err)
+		}
+	}
+
+	if len(ids) == 0 {
+		return nil, clierr.Validation("no resources given, pass resource IDs as arguments or on stdin")
+	}
+
+	// Processing an ID twice would race on the same resource or output file
+	unique := ids[:0]
+	seen := make(map[string]bool, len(ids))
+	for _, id := range ids {
+		if !seen[id] {
+			seen[id] = true
+			unique = append(unique, id)
+		}
+	}
+	return unique, nil
+}
+
+// Run calls process for every ID with at most workers concurrent calls, and
+// report for each successful ID in the order of ids, as soon as the IDs before
+// it are done. Keeping output in report keeps it ordered and unmixed.
+//
+// With a single ID, process and report run directly and their error is
+// returned unchanged. Otherwise failures are printed to stderr along with a
+// summary, and the result is nil, the first error if every ID failed, or a
+// partial failure error.
+func Run(ids []string, workers int, process func(i int) error, report func(i int) error) error {
+	if workers < 1 {
+		return clierr.Validation("--parallel must be at least 1")
+	}
+
+	if len(ids) == 1 {
+		if err := process(0); err != nil {
+			return err
+		}
+		return report(0)
+	}
+
+	done := make([]chan error, len(ids))
+	for i := range done {
+		done[i] = make(chan error, 1)
+	}
+	go parallel.ForEach(len(ids), workers, func(i int) error {
+		err := process(i)
+		done[i] <- err
+		return err
+	})
+
+	failed := 0
+	var firstErr error
+	for i, id := range ids {
+		err := <-done[i]
+		if err == nil {
+			err = report(i)
+		}
+		if err != nil {
+			failed++
+			if firstErr == nil {
+				firstErr = err
+			}
+			fmt.Fprintf(os.Stderr, "Error: %s: %v\n", id, err)
+		}
+	}
+	fmt.Fprintf(os.Stderr, "%d of %d succeeded, %d failed\n", len(ids)-failed, len(ids), failed)
+
+	switch failed {
+	case 0:
+		return nil
+	case len(ids):
+		return firstErr
+	}
+	return clierr.Partial(failed, len(ids))
+}
+
+// OutputDir turns an --output path into a directory when several resources are
+// written to it, so that each resource gets its own file
+func OutputDir(output string, count int) string {
+	if count > 1 && output != "" && !strings.HasSuffix(output, "/") && !strings.HasSuffix(output, string(os.PathSeparator)) {
+		return output + string(os.PathSeparator)
+	}
+	return output
+}
@@ -0,0 +1,82 @@
+package client
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+)
+
+// ArchiveFormat selects the layout of a multi-resource archive
+type ArchiveFormat string
+
+const (
+	// ArchiveZip is a plain ZIP archive (/tools/create-archive)
+	ArchiveZip ArchiveFormat = "zip"
+	// ArchiveMedia is a ZIP archive with a DICOMDIR (/tools/create-media)
+	ArchiveMedia ArchiveFormat = "media"
+	// ArchiveMediaExtended is a DICOMDIR with extended information (/tools/create-media-extended)
+	ArchiveMediaExtended ArchiveFormat = "media-extended"
+)
+
+// ArchiveRequest is the body of /tools/create-archive and /tools/create-media
+type ArchiveRequest struct {
+	Resources   []string `json:"Resources"`
+	Synchronous bool     `json:"Synchronous"`
+	// Transcode is an optional transfer syntax UID
+	Transcode string `json:"Transcode,omitempty"`
+}
+
+// CreateArchive builds an archive synchronously and returns the streamed response.
+// The caller is responsible for closing the response body.
+func (c *Client) CreateArchive(format ArchiveFormat, request *ArchiveRequest) (*http.Response, error) {
+	if err := c.checkArchiveRequest(request); err != nil {
+		return nil, err
+	}
+
+	body := *request
+	body.Synchronous = true
+	data, err := json.Marshal(body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal request body: %w", err)
+	}
+
+	return c.Stream(http.MethodPost, archiveEndpoint(format), bytes.NewReader(data), "application/json")
+}
+
+// SubmitArchiveJob starts an asynchronous archive job and returns its ID
+func (c *Client) SubmitArchiveJob(format ArchiveFormat, request *ArchiveRequest) (string, error) {
+	if err := c.checkArchiveRequest(request); err != nil {
+		return "", err
+	}
+
+	body := *request
+	body.Synchronous = false
+	return c.SubmitJob(archiveEndpoint(format), body)
+}
+
+// DownloadJobArchive downloads the archive produced by a finished job,
+// resuming at offset when the server supports it (see Download).
+// The caller is responsible for closing the response body.
+func (c *Client) DownloadJobArchive(jobID string, offset int64) (*http.Response, error) {
+	return c.Download("jobs/"+jobID+"/archive", offset)
+}
+
+// checkArchiveRequest fails early when the server cannot honour the request
+func (c *Client) checkArchiveRequest(request *ArchiveRequest) error {
+	if request.Transcode != "" {
+		return c.RequireFeature(FeatureTranscoding)
+	}
+	return nil
+}
+
+// archiveEndpoint returns the endpoint producing an archive format
+func archiveEndpoint(format ArchiveFormat) string {
+	switch format {
+	case ArchiveMedia:
+		return "tools/create-media"
+	case ArchiveMediaExtended:
+		return "tools/create-media-extended"
+	}
+	return "tools/create-archive"
+}
@@ -0,0 +1,247 @@
+package client
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/proencaj/orthanc-cli/internal/clierr"
+)
+
+// capabilitiesTTL is how long a capability probe is cached per context
+const capabilitiesTTL = time.Hour
+
+// Feature names used with Capabilities.Supports and Client.RequireFeature
+const (
+	FeatureLabels        = "labels"
+	FeatureRequestedTags = "requested-tags"
+	FeatureDicomWeb      = "dicomweb"
+	FeatureLogCategories = "log-categories"
+	FeatureTranscoding   = "transcoding"
+	FeatureWorklists     = "worklists"
+)
+
+// Feature describes an optional server feature and what it requires
+type Feature struct {
+	Name        string
+	Description string
+	// MinVersion is the minimum Orthanc version, empty if any version works
+	MinVersion string
+	// Plugin is the ID of the plugin providing the feature, empty if built-in
+	Plugin string
+}
+
+// Features lists the optional server features the CLI knows how to detect
+var Features = []Feature{
+	{Name: FeatureLabels, Description: "Resource labels and label filters in tools find", MinVersion: "1.12.0"},
+	{Name: FeatureRequestedTags, Description: "RequestedTags in tools find", MinVersion: "1.11.0"},
+	{Name: FeatureDicomWeb, Description: "DICOMweb operations and DICOMweb server management", Plugin: "dicom-web"},
+	{Name: FeatureLogCategories, Description: "Per-category log levels", MinVersion: "1.9.0"},
+	{Name: FeatureTranscoding, Description: "Server-side transcoding", MinVersion: "1.7.0"},
+	{Name: FeatureWorklists, Description: "Modality worklist management", MinVersion: "1.12.6", Plugin: "worklists"},
+}
+
+// Capabilities describes what an Orthanc server supports
+type Capabilities struct {
+	Context    string    `json:"Context"`
+	URL        string    `json:"URL"`
+	Name       string    `json:"Name"`
+	Version    string    `json:"Version"`
+	ApiVersion int       `json:"ApiVersion"`
+	Plugins    []string  `json:"Plugins"`
+	DetectedAt time.Time `json:"DetectedAt"`
+}
+
+// Supports reports whether the server provides the named feature.
+// Unknown feature names are reported as unsupported.
+func (c *Capabilities) Supports(name string) bool {
+	feature, ok := lookupFeature(name)
+	if !ok {
+		return false
+	}
+	if feature.MinVersion != "" && !versionAtLeast(c.Version, feature.MinVersion) {
+		return false
+	}
+	if feature.Plugin != "" && !c.HasPlugin(feature.Plugin) {
+		return false
+	}
+	return true
+}
+
+// HasPlugin reports whether the plugin with the given ID is loaded
+func (c *Capabilities) HasPlugin(id string) bool {
+	for _, plugin := range c.Plugins {
+		if plugin == id {
+			return true
+		}
+	}
+	return false
+}
+
+// Capabilities returns the capabilities of the server, probing /system and
+// /plugins if no recent probe is cached for the current context
+func (c *Client) Capabilities() (*Capabilities, error) {
+	if caps, err := c.loadCachedCapabilities(); err == nil {
+		return caps, nil
+	}
+	return c.RefreshCapabilities()
+}
+
+// RefreshCapabilities probes the server and updates the cache
+func (c *Client) RefreshCapabilities() (*Capabilities, error) {
+	system, err := c.GetSystem()
+	if err != nil {
+		return nil, fmt.Errorf("failed to probe server capabilities: %w", err)
+	}
+
+	caps := &Capabilities{
+		Context:    c.ContextName(),
+		URL:        c.URL(),
+		Name:       system.Name,
+		Version:    system.Version,
+		ApiVersion: system.ApiVersion,
+		Plugins:    []string{},
+		DetectedAt: time.Now(),
+	}
+
+	if system.PluginsEnabled {
+		if err := c.GetJSON("plugins", &caps.Plugins); err != nil {
+			return nil, fmt.Errorf("failed to list plugins: %w", err)
+		}
+	}
+
+	// Caching is best effort: a read-only cache directory must not break commands
+	_ = c.saveCachedCapabilities(caps)
+
+	return caps, nil
+}
+
+// RequireFeature returns an unsupported error if the server is known to lack
+// the named feature. If the server cannot be probed, the check is skipped and
+// the request is left to fail (or succeed) on its own.
+func (c *Client) RequireFeature(name string) error {
+	caps, err := c.Capabilities()
+	if err != nil {
+		return nil
+	}
+	if caps.Supports(name) {
+		return nil
+	}
+
+	feature, _ := lookupFeature(name)
+	switch {
+	case feature.Plugin != "" && !caps.HasPlugin(feature.Plugin):
+		return clierr.New(clierr.KindUnsupported, "%s requires the '%s' plugin, which is not enabled on %s (context %q)",
+			feature.Description, feature.Plugin, caps.URL, caps.Context)
+	default:
+		return clierr.New(clierr.KindUnsupported, "%s requires Orthanc %s or later, but %s (context %q) runs %s",
+			feature.Description, feature.MinVersion, caps.URL, caps.Context, caps.Version)
+	}
+}
+
+// loadCachedCapabilities returns the cached probe for the current context if it is still fresh
+func (c *Client) loadCachedCapabilities() (*Capabilities, error) {
+	path, err := capabilitiesCachePath(c.ContextName())
+	if err != nil {
+		return nil, err
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var caps Capabilities
+	if err := json.Unmarshal(data, &caps); err != nil {
+		return nil, err
+	}
+
+	// Discard stale entries and entries for a different server (e.g. ORTHANC_URL override)
+	if time.Since(caps.DetectedAt) > capabilitiesTTL || caps.URL != c.URL() {
+		return nil, fmt.Errorf("cached capabilities are stale")
+	}
+
+	return &caps, nil
+}
+
+// saveCachedCapabilities writes a probe result to the cache
+func (c *Client) saveCachedCapabilities(caps *Capabilities) error {
+	path, err := capabilitiesCachePath(c.ContextName())
+	if err != nil {
+		return err
+	}
+
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return err
+	}
+
+	data, err := json.MarshalIndent(caps, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	return os.WriteFile(path, data, 0644)
+}
+
+// capabilitiesCachePath returns the cache file for a context
+func capabilitiesCachePath(contextName string) (string, error) {
+	cacheDir, err := os.UserCacheDir()
+	if err != nil {
+		return "", err
+	}
+	name := strings.NewReplacer("/", "_", "\\", "_", "..", "_").Replace(contextName)
+	return filepath.Join(cacheDir, "orthanc-cli", "capabilities", name+".json"), nil
+}
+
+// lookupFeature finds a feature by name
+func lookupFeature(name string) (Feature, bool) {
+	for _, feature := range Features {
+		if feature.Name == name {
+			return feature, true
+		}
+	}
+	return Feature{}, false
+}
+
+// versionAtLeast reports whether an Orthanc version is at least min.
+// Development builds ("mainline") are assumed to support everything.
+func versionAtLeast(version, min string) bool {
+	if version == "" || strings.HasPrefix(version, "mainline") {
+		return true
+	}
+
+	have := parseVersion(version)
+	want := parseVersion(min)
+	for i := 0; i < len(want); i++ {
+		var part int
+		if i < len(have) {
+			part = have[i]
+		}
+		if part != want[i] {
+			return part > want[i]
+		}
+	}
+	return true
+}
+
+// parseVersion splits a dotted version into numbers, ignoring non-numeric suffixes
+func parseVersion(version string) []int {
+	var parts []int
+	for _, field := range strings.Split(version, ".") {
+		digits := strings.TrimLeft(field, "v")
+		end := 0
+		for end < len(digits) && digits[end] >= '0' && digits[end] <= '9' {
+			end++
+		}
+		n, err := strconv.Atoi(digits[:end])
+		if err != nil {
+			break
+		}
+		parts = append(parts, n)
+	}
+	return parts
+}
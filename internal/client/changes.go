@@ -0,0 +1,45 @@
+package client
+
+import "fmt"
+
+// Change types reported by Orthanc in /changes
+const (
+	ChangeNewStudy    = "NewStudy"
+	ChangeStableStudy = "StableStudy"
+	ChangeDeleted     = "Deleted"
+)
+
+// Change is an entry of the Orthanc change log
+type Change struct {
+	Seq          int64  `json:"Seq"`
+	ChangeType   string `json:"ChangeType"`
+	ResourceType string `json:"ResourceType"`
+	ID           string `json:"ID"`
+	Path         string `json:"Path"`
+	Date         string `json:"Date"`
+}
+
+// ChangeList is a page of the change log, as returned by /changes
+type ChangeList struct {
+	Changes []Change `json:"Changes"`
+	Done    bool     `json:"Done"`
+	Last    int64    `json:"Last"`
+}
+
+// GetChanges returns at most limit changes following the sequence number since
+func (c *Client) GetChanges(since int64, limit int) (*ChangeList, error) {
+	var changes ChangeList
+	if err := c.GetJSON(fmt.Sprintf("changes?since=%d&limit=%d", since, limit), &changes); err != nil {
+		return nil, err
+	}
+	return &changes, nil
+}
+
+// GetLastChange returns the sequence number of the latest change
+func (c *Client) GetLastChange() (int64, error) {
+	var changes ChangeList
+	if err := c.GetJSON("changes?last", &changes); err != nil {
+		return 0, err
+	}
+	return changes.Last, nil
+}
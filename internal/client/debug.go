@@ -0,0 +1,312 @@
+package client
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+	"unicode/utf8"
+
+	"github.com/proencaj/orthanc-cli/internal/clierr"
+	"github.com/proencaj/orthanc-cli/internal/helpers"
+)
+
+// DebugLevel controls how much of each HTTP exchange is traced
+type DebugLevel int
+
+const (
+	// DebugOff disables HTTP tracing
+	DebugOff DebugLevel = iota
+	// DebugBasic logs method, URL, status, duration, sizes and headers
+	DebugBasic
+	// DebugBody additionally dumps (truncated) request and response bodies
+	DebugBody
+)
+
+// maxDebugBody is the maximum number of body bytes dumped in DebugBody mode
+const maxDebugBody = 4096
+
+// redacted replaces secret values in debug output
+const redacted = "[REDACTED]"
+
+// secretFieldPattern matches JSON string fields that usually hold secrets
+var secretFieldPattern = regexp.MustCompile(`(?i)("(?:password|passwd|secret|token|authorization)"\s*:\s*)"(?:[^"\\]|\\.)*"`)
+
+// secretParamPattern matches query parameter names that usually hold secrets,
+// such as token, access_token or api_key
+var secretParamPattern = regexp.MustCompile(`(?i)(password|passwd|secret|token|api[-_]?key|signature|authorization)`)
+
+// ParseDebugLevel converts a --debug / ORTHANC_DEBUG value into a DebugLevel
+func ParseDebugLevel(value string) (DebugLevel, error) {
+	switch strings.ToLower(strings.TrimSpace(value)) {
+	case "", "0", "false", "off", "no":
+		return DebugOff, nil
+	case "1", "true", "on", "yes":
+		return DebugBasic, nil
+	case "body":
+		return DebugBody, nil
+	default:
+		return DebugOff, clierr.Validation("invalid debug mode '%s', must be one of: true, false, body", value)
+	}
+}
+
+// debugTransport is an http.RoundTripper that traces requests to a writer
+type debugTransport struct {
+	next  http.RoundTripper
+	level DebugLevel
+	curl  bool
+	out   io.Writer
+	mu    sync.Mutex
+}
+
+// newDebugTransport wraps next with request tracing written to stderr
+func newDebugTransport(next http.RoundTripper, level DebugLevel, curl bool) *debugTransport {
+	return &debugTransport{
+		next:  next,
+		level: level,
+		curl:  curl,
+		out:   os.Stderr,
+	}
+}
+
+// RoundTrip implements http.RoundTripper
+func (t *debugTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	// Capture the start of the request body without consuming it
+	var reqBody []byte
+	if req.Body != nil && req.Body != http.NoBody && (t.level >= DebugBody || t.curl) {
+		prefix, body, err := peekBody(req.Body)
+		if err != nil {
+			return nil, err
+		}
+		reqBody = prefix
+		req = req.Clone(req.Context())
+		req.Body = body
+	}
+
+	if t.curl {
+		t.logf("%s\n", curlCommand(req, reqBody))
+	}
+
+	if t.level >= DebugBasic {
+		t.logf("[debug] --> %s %s\n", req.Method, redactURL(req.URL))
+		t.logHeaders("[debug]     ", req.Header)
+		if t.level >= DebugBody && reqBody != nil {
+			t.logBody("[debug]     ", reqBody, requestSize(req))
+		}
+	}
+
+	start := time.Now()
+	resp, err := t.next.RoundTrip(req)
+	elapsed := time.Since(start)
+
+	if t.level < DebugBasic {
+		return resp, err
+	}
+
+	if err != nil {
+		t.logf("[debug] <-- %s %s failed after %s: %v\n", req.Method, redactURL(req.URL), elapsed.Round(time.Millisecond), err)
+		return resp, err
+	}
+
+	t.logf("[debug] <-- %s (%s, request %s, response %s)\n",
+		resp.Status, elapsed.Round(time.Millisecond),
+		formatSize(requestSize(req)), formatSize(resp.ContentLength))
+	t.logHeaders("[debug]     ", resp.Header)
+
+	if t.level >= DebugBody && resp.Body != nil {
+		prefix, body, err := peekBody(resp.Body)
+		if err != nil {
+			resp.Body.Close()
+			return nil, err
+		}
+		resp.Body = body
+		t.logBody("[debug]     ", prefix, resp.ContentLength)
+	}
+
+	return resp, nil
+}
+
+// logf writes a line to the debug output, serializing concurrent requests
+func (t *debugTransport) logf(format string, args ...interface{}) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	fmt.Fprintf(t.out, format, args...)
+}
+
+// logHeaders writes headers in a stable order with secrets redacted
+func (t *debugTransport) logHeaders(indent string, header http.Header) {
+	names := make([]string, 0, len(header))
+	for name := range header {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		for _, value := range header[name] {
+			t.logf("%s%s: %s\n", indent, name, redactHeader(name, value))
+		}
+	}
+}
+
+// logBody writes a truncated, redacted body dump
+func (t *debugTransport) logBody(indent string, prefix []byte, length int64) {
+	if len(prefix) == 0 {
+		return
+	}
+	if !isPrintable(prefix) {
+		t.logf("%s[binary body, %s]\n", indent, formatSize(length))
+		return
+	}
+
+	text := redactBody(string(prefix))
+	if len(prefix) == maxDebugBody {
+		text += "... (truncated)"
+	}
+	t.logf("%sBody: %s\n", indent, text)
+}
+
+// peekBody reads up to maxDebugBody bytes from body and returns them along with
+// a ReadCloser that replays them before the rest of the original body
+func peekBody(body io.ReadCloser) ([]byte, io.ReadCloser, error) {
+	prefix := make([]byte, maxDebugBody)
+	n, err := io.ReadFull(body, prefix)
+	if err != nil && err != io.EOF && err != io.ErrUnexpectedEOF {
+		return nil, nil, fmt.Errorf("failed to read body for debug output: %w", err)
+	}
+	prefix = prefix[:n]
+
+	replay := struct {
+		io.Reader
+		io.Closer
+	}{
+		Reader: io.MultiReader(bytes.NewReader(prefix), body),
+		Closer: body,
+	}
+	return prefix, replay, nil
+}
+
+// requestSize returns the request body size, or -1 when it is not known
+func requestSize(req *http.Request) int64 {
+	if req.ContentLength == 0 && req.Body != nil && req.Body != http.NoBody {
+		return -1
+	}
+	return req.ContentLength
+}
+
+// curlCommand renders the equivalent curl invocation for a request.
+// The password is never printed: curl prompts for it instead.
+func curlCommand(req *http.Request, body []byte) string {
+	var b strings.Builder
+	b.WriteString("curl")
+	if req.Method != http.MethodGet {
+		fmt.Fprintf(&b, " -X %s", req.Method)
+	}
+
+	u := *req.URL
+	u.User = nil
+	fmt.Fprintf(&b, " %s", shellQuote(redactURL(&u)))
+
+	if username, _, ok := req.BasicAuth(); ok {
+		fmt.Fprintf(&b, " -u %s", shellQuote(username))
+	}
+
+	names := make([]string, 0, len(req.Header))
+	for name := range req.Header {
+		if strings.EqualFold(name, "Authorization") {
+			continue
+		}
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		for _, value := range req.Header[name] {
+			fmt.Fprintf(&b, " -H %s", shellQuote(name+": "+value))
+		}
+	}
+
+	if len(body) > 0 {
+		if isPrintable(body) && int64(len(body)) == req.ContentLength {
+			fmt.Fprintf(&b, " --data-binary %s", shellQuote(redactBody(string(body))))
+		} else {
+			fmt.Fprintf(&b, " --data-binary @body.bin  # %s body omitted", formatSize(requestSize(req)))
+		}
+	}
+
+	return b.String()
+}
+
+// redactURL hides any password embedded in a URL and the values of
+// secret-looking query parameters
+func redactURL(u *url.URL) string {
+	clone := *u
+	if u.User != nil {
+		if _, hasPassword := u.User.Password(); hasPassword {
+			clone.User = url.UserPassword(u.User.Username(), redacted)
+		}
+	}
+	clone.RawQuery = redactQuery(u.RawQuery)
+	return clone.String()
+}
+
+// redactQuery hides the values of secret-looking parameters of a raw query,
+// keeping the other parameters as they were sent
+func redactQuery(rawQuery string) string {
+	if rawQuery == "" {
+		return rawQuery
+	}
+	params := strings.Split(rawQuery, "&")
+	for i, param := range params {
+		name, _, hasValue := strings.Cut(param, "=")
+		if decoded, err := url.QueryUnescape(name); err == nil {
+			name = decoded
+		}
+		if hasValue && secretParamPattern.MatchString(name) {
+			params[i] = url.QueryEscape(name) + "=" + redacted
+		}
+	}
+	return strings.Join(params, "&")
+}
+
+// redactHeader hides credentials carried in headers
+func redactHeader(name, value string) string {
+	switch strings.ToLower(name) {
+	case "authorization", "proxy-authorization":
+		if scheme, _, ok := strings.Cut(value, " "); ok {
+			return scheme + " " + redacted
+		}
+		return redacted
+	case "cookie", "set-cookie":
+		return redacted
+	}
+	return value
+}
+
+// redactBody hides secret-looking JSON fields
+func redactBody(body string) string {
+	return secretFieldPattern.ReplaceAllString(body, `$1"`+redacted+`"`)
+}
+
+// isPrintable reports whether data looks like text
+func isPrintable(data []byte) bool {
+	return utf8.Valid(data) && !bytes.ContainsRune(data, 0)
+}
+
+// shellQuote quotes a string for POSIX shells
+func shellQuote(s string) string {
+	return "'" + strings.ReplaceAll(s, "'", `'\''`) + "'"
+}
+
+// formatSize renders a byte count, or "unknown" for negative values
+func formatSize(n int64) string {
+	if n < 0 {
+		return "unknown size"
+	}
+	return helpers.FormatBytes(n)
+}
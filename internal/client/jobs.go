@@ -0,0 +1,106 @@
+package client
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/proencaj/orthanc-cli/internal/clierr"
+)
+
+// Job states reported by Orthanc
+const (
+	JobPending = "Pending"
+	JobRunning = "Running"
+	JobSuccess = "Success"
+	JobFailure = "Failure"
+	JobPaused  = "Paused"
+	JobRetry   = "Retry"
+)
+
+// Job is an Orthanc job as returned by /jobs/{id}
+type Job struct {
+	ID               string                 `json:"ID"`
+	Type             string                 `json:"Type"`
+	State            string                 `json:"State"`
+	Progress         int                    `json:"Progress"`
+	ErrorCode        int                    `json:"ErrorCode"`
+	ErrorDescription string                 `json:"ErrorDescription"`
+	ErrorDetails     string                 `json:"ErrorDetails"`
+	CreationTime     string                 `json:"CreationTime"`
+	CompletionTime   string                 `json:"CompletionTime"`
+	Content          map[string]interface{} `json:"Content"`
+}
+
+// jobSubmission is the answer of endpoints that start an asynchronous job
+type jobSubmission struct {
+	ID   string `json:"ID"`
+	Path string `json:"Path"`
+}
+
+// SubmitJob posts body to an endpoint in asynchronous mode and returns the job ID
+func (c *Client) SubmitJob(path string, body interface{}) (string, error) {
+	var submission jobSubmission
+	if err := c.PostJSON(path, body, &submission); err != nil {
+		return "", err
+	}
+	if submission.ID == "" {
+		return "", fmt.Errorf("server did not return a job ID")
+	}
+	return submission.ID, nil
+}
+
+// GetJob returns the current state of a job
+func (c *Client) GetJob(jobID string) (*Job, error) {
+	var job Job
+	if err := c.GetJSON("jobs/"+jobID, &job); err != nil {
+		return nil, err
+	}
+	return &job, nil
+}
+
+// WaitForJob polls a job until it succeeds, fails or is paused, or until ctx
+// is done. onProgress, if not nil, is called after every poll.
+func (c *Client) WaitForJob(ctx context.Context, jobID string, interval time.Duration, onProgress func(*Job)) (*Job, error) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		job, err := c.GetJob(jobID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get job %s: %w", jobID, err)
+		}
+		if onProgress != nil {
+			onProgress(job)
+		}
+
+		switch job.State {
+		case JobSuccess:
+			return job, nil
+		case JobFailure:
+			reason := job.ErrorDescription
+			if job.ErrorDetails != "" {
+				reason += " (" + job.ErrorDetails + ")"
+			}
+			return job, clierr.New(clierr.KindServer, "job %s failed: %s", jobID, reason)
+		case JobPaused:
+			// A paused job only resumes on request, waiting would never end
+			return job, clierr.New(clierr.KindGeneric, "job %s is paused, resume it with 'orthanc api post jobs/%s/resume'", jobID, jobID)
+		}
+
+		select {
+		case <-ctx.Done():
+			return job, clierr.New(clierr.KindTimeout, "gave up waiting for job %s (%s, %d%%): %v", jobID, job.State, job.Progress, ctx.Err())
+		case <-ticker.C:
+		}
+	}
+}
+
+// ListJobs returns the jobs known to the server, including the recent history
+func (c *Client) ListJobs() ([]Job, error) {
+	var jobs []Job
+	if err := c.GetJSON("jobs?expand", &jobs); err != nil {
+		return nil, err
+	}
+	return jobs, nil
+}
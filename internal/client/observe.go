@@ -0,0 +1,39 @@
+package client
+
+import (
+	"net/http"
+	"strings"
+)
+
+// RequestObserver is notified of each request sent to Orthanc, with the path
+// relative to the server URL and the response status (0 if no response)
+type RequestObserver func(method, path string, status int)
+
+// requestObserver is set with SetRequestObserver
+var requestObserver RequestObserver
+
+// SetRequestObserver sets the observer notified of the requests of clients
+// created afterwards, e.g. to record changes in the audit log
+func SetRequestObserver(observer RequestObserver) {
+	requestObserver = observer
+}
+
+// observingTransport is an http.RoundTripper that reports requests to an observer
+type observingTransport struct {
+	next     http.RoundTripper
+	basePath string
+	observer RequestObserver
+}
+
+// RoundTrip implements http.RoundTripper
+func (t *observingTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	resp, err := t.next.RoundTrip(req)
+
+	status := 0
+	if resp != nil {
+		status = resp.StatusCode
+	}
+	t.observer(req.Method, strings.TrimPrefix(req.URL.Path, t.basePath), status)
+
+	return resp, err
+}
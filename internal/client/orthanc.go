@@ -4,9 +4,12 @@ import (
 	"crypto/tls"
 	"fmt"
 	"net/http"
+	"net/url"
+	"strings"
 	"time"
 
 	"github.com/proencaj/gorthanc"
+	"github.com/proencaj/orthanc-cli/internal/clierr"
 	"github.com/proencaj/orthanc-cli/internal/config"
 )
 
@@ -14,12 +17,22 @@ import (
 type Client struct {
 	*gorthanc.Client
 	config *config.Config
+
+	// Connection settings, kept for requests to endpoints gorthanc does not wrap
+	httpClient *http.Client
+	// streamClient shares the transport of httpClient but has no overall timeout
+	streamClient *http.Client
+	baseURL      *url.URL
+	username     string
+	password     string
+	contextName  string
+	url          string
 }
 
 // NewClient creates a new Orthanc client from the configuration
 func NewClient(cfg *config.Config) (*Client, error) {
 	if cfg == nil {
-		return nil, fmt.Errorf("configuration is required")
+		return nil, clierr.Validation("configuration is required")
 	}
 
 	// Get the current context configuration
@@ -28,9 +41,33 @@ func NewClient(cfg *config.Config) (*Client, error) {
 		return nil, fmt.Errorf("failed to get current context: %w", err)
 	}
 
+	return newClient(cfg, cfg.CurrentContext, orthancCfg)
+}
+
+// NewClientForContext creates a client for a named context rather than the
+// current one. Environment variable overrides such as ORTHANC_URL only apply
+// to the current context and are ignored.
+func NewClientForContext(cfg *config.Config, name string) (*Client, error) {
+	if cfg == nil {
+		return nil, clierr.Validation("configuration is required")
+	}
+
+	orthancCfg, err := cfg.GetContext(name)
+	if err != nil {
+		return nil, err
+	}
+
+	return newClient(cfg, name, orthancCfg)
+}
+
+// RequestTimeout bounds requests other than streams, uploads and downloads
+const RequestTimeout = 30 * time.Second
+
+// newClient creates a client for the Orthanc server of a context
+func newClient(cfg *config.Config, contextName string, orthancCfg *config.OrthancConfig) (*Client, error) {
 	// Validate required configuration
 	if orthancCfg.URL == "" {
-		return nil, fmt.Errorf("orthanc URL is required (use 'orthanc config set-context %s --url <url>')", cfg.CurrentContext)
+		return nil, clierr.Validation("orthanc URL is required (use 'orthanc config set-context %s --url <url>')", contextName)
 	}
 
 	// Create client options
@@ -41,20 +78,44 @@ func NewClient(cfg *config.Config) (*Client, error) {
 		opts = append(opts, gorthanc.WithBasicAuth(orthancCfg.Username, orthancCfg.Password))
 	}
 
-	// Create custom HTTP client if insecure mode is enabled
+	// Build the HTTP transport, skipping TLS verification if insecure mode is enabled
+	transport := http.DefaultTransport.(*http.Transport).Clone()
 	if orthancCfg.Insecure {
-		transport := &http.Transport{
-			TLSClientConfig: &tls.Config{
-				InsecureSkipVerify: true,
-			},
+		transport.TLSClientConfig = &tls.Config{
+			InsecureSkipVerify: true,
 		}
-		httpClient := &http.Client{
-			Transport: transport,
-			Timeout:   30 * time.Second,
+	}
+
+	// Wrap the transport with request tracing if --debug or --curl is enabled
+	debugLevel, err := ParseDebugLevel(cfg.Debug.Mode)
+	if err != nil {
+		return nil, err
+	}
+	var roundTripper http.RoundTripper = transport
+	if debugLevel > DebugOff || cfg.Debug.Curl {
+		roundTripper = newDebugTransport(transport, debugLevel, cfg.Debug.Curl)
+	}
+
+	baseURL, err := parseBaseURL(orthancCfg.URL)
+	if err != nil {
+		return nil, err
+	}
+
+	// Report requests to the observer, if any
+	if requestObserver != nil {
+		roundTripper = &observingTransport{
+			next:     roundTripper,
+			basePath: baseURL.Path,
+			observer: requestObserver,
 		}
-		opts = append(opts, gorthanc.WithHTTPClient(httpClient))
 	}
 
+	httpClient := &http.Client{
+		Transport: roundTripper,
+		Timeout:   RequestTimeout,
+	}
+	opts = append(opts, gorthanc.WithHTTPClient(httpClient))
+
 	// Create the gorthanc client
 	client, err := gorthanc.NewClient(orthancCfg.URL, opts...)
 	if err != nil {
@@ -62,8 +123,17 @@ func NewClient(cfg *config.Config) (*Client, error) {
 	}
 
 	return &Client{
-		Client: client,
-		config: cfg,
+		Client:     client,
+		config:     cfg,
+		httpClient: httpClient,
+		streamClient: &http.Client{
+			Transport: roundTripper,
+		},
+		baseURL:     baseURL,
+		username:    orthancCfg.Username,
+		password:    orthancCfg.Password,
+		contextName: contextName,
+		url:         orthancCfg.URL,
 	}, nil
 }
 
@@ -74,9 +144,22 @@ func (c *Client) GetConfig() *config.Config {
 
 // URL returns the Orthanc server URL
 func (c *Client) URL() string {
-	orthancCfg, err := c.config.GetCurrentContext()
+	return c.url
+}
+
+// ContextName returns the name of the context the client was created for
+func (c *Client) ContextName() string {
+	return c.contextName
+}
+
+// parseBaseURL parses the server URL, ensuring a trailing slash for path joining
+func parseBaseURL(rawURL string) (*url.URL, error) {
+	u, err := url.Parse(rawURL)
 	if err != nil {
-		return ""
+		return nil, clierr.Validation("invalid orthanc URL %q: %v", rawURL, err)
+	}
+	if !strings.HasSuffix(u.Path, "/") {
+		u.Path += "/"
 	}
-	return orthancCfg.URL
+	return u, nil
 }
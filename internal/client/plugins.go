@@ -0,0 +1,53 @@
+package client
+
+import (
+	"fmt"
+	"net/url"
+)
+
+// Plugin describes a plugin loaded by Orthanc, as returned by /plugins/{id}
+type Plugin struct {
+	ID          string `json:"ID"`
+	Version     string `json:"Version"`
+	Description string `json:"Description,omitempty"`
+	ExtendedID  string `json:"ExtendedID,omitempty"`
+	RootUri     string `json:"RootUri,omitempty"`
+}
+
+// ListPlugins returns the IDs of the plugins loaded by the server
+func (c *Client) ListPlugins() ([]string, error) {
+	var ids []string
+	if err := c.GetJSON("plugins", &ids); err != nil {
+		return nil, err
+	}
+	return ids, nil
+}
+
+// GetPlugin returns the details of a plugin
+func (c *Client) GetPlugin(id string) (*Plugin, error) {
+	var plugin Plugin
+	if err := c.GetJSON("plugins/"+url.PathEscape(id), &plugin); err != nil {
+		return nil, err
+	}
+	return &plugin, nil
+}
+
+// PluginURL returns the absolute URL of a plugin's web interface, or an
+// empty string if it has none. Orthanc reports RootUri relative to the
+// built-in explorer in /app/.
+func (c *Client) PluginURL(plugin *Plugin) (string, error) {
+	if plugin.RootUri == "" {
+		return "", nil
+	}
+	return c.WebURL("app/" + plugin.RootUri)
+}
+
+// WebURL returns the absolute URL of a path of the server, without credentials
+func (c *Client) WebURL(path string) (string, error) {
+	u, err := c.baseURL.Parse(path)
+	if err != nil {
+		return "", fmt.Errorf("failed to parse path %q: %w", path, err)
+	}
+	u.User = nil
+	return u.String(), nil
+}
@@ -0,0 +1,198 @@
+package client
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+
+	"github.com/proencaj/gorthanc"
+)
+
+// NewRequest builds a request for a path relative to the Orthanc server URL,
+// with the context's credentials applied
+func (c *Client) NewRequest(method, path string, body io.Reader) (*http.Request, error) {
+	endpoint, err := c.baseURL.Parse(strings.TrimPrefix(path, "/"))
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse endpoint path: %w", err)
+	}
+
+	req, err := http.NewRequest(method, endpoint.String(), body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+
+	req.Header.Set("Accept", "application/json")
+	if c.username != "" && c.password != "" {
+		req.SetBasicAuth(c.username, c.password)
+	}
+
+	return req, nil
+}
+
+// Send performs a request without checking the response status.
+// The caller is responsible for closing the response body.
+func (c *Client) Send(req *http.Request) (*http.Response, error) {
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("request failed: %w", err)
+	}
+	return resp, nil
+}
+
+// SendStream is like Send, but without an overall request timeout, for
+// bodies of unknown size in either direction.
+// The caller is responsible for closing the response body.
+func (c *Client) SendStream(req *http.Request) (*http.Response, error) {
+	resp, err := c.streamClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("request failed: %w", err)
+	}
+	return resp, nil
+}
+
+// Do performs a request against an endpoint not wrapped by gorthanc.
+// Non-2xx responses are returned as *gorthanc.HTTPError, like gorthanc does.
+// The caller is responsible for closing the response body.
+func (c *Client) Do(method, path string, body io.Reader, contentType string) (*http.Response, error) {
+	return c.do(c.httpClient, method, path, body, contentType)
+}
+
+// Stream is like Do, but without an overall request timeout, for responses
+// such as archives that may take longer to produce and transfer.
+// The caller is responsible for closing the response body.
+func (c *Client) Stream(method, path string, body io.Reader, contentType string) (*http.Response, error) {
+	return c.do(c.streamClient, method, path, body, contentType)
+}
+
+// Upload streams a request body of known size without an overall timeout.
+// Setting the size sends a Content-Length instead of a chunked body, which
+// not every server accepts; a negative size means unknown.
+// The caller is responsible for closing the response body.
+func (c *Client) Upload(method, path string, body io.Reader, size int64, contentType string) (*http.Response, error) {
+	req, err := c.NewRequest(method, path, body)
+	if err != nil {
+		return nil, err
+	}
+	if size >= 0 {
+		req.ContentLength = size
+	}
+	if contentType != "" {
+		req.Header.Set("Content-Type", contentType)
+	}
+
+	resp, err := c.streamClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("request failed: %w", err)
+	}
+
+	if err := checkStatus(resp); err != nil {
+		return nil, err
+	}
+	return resp, nil
+}
+
+// Download performs a streaming GET for a file or archive. When offset is
+// positive, a Range header asks the server to resume from that byte; servers
+// that do not support ranges answer with the full content (200 instead of 206).
+// The caller is responsible for closing the response body.
+func (c *Client) Download(path string, offset int64) (*http.Response, error) {
+	req, err := c.NewRequest(http.MethodGet, path, nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Accept", "*/*")
+	if offset > 0 {
+		req.Header.Set("Range", fmt.Sprintf("bytes=%d-", offset))
+	}
+
+	resp, err := c.streamClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("request failed: %w", err)
+	}
+
+	if err := checkStatus(resp); err != nil {
+		return nil, err
+	}
+	return resp, nil
+}
+
+// do performs a request with the given HTTP client and checks the response status
+func (c *Client) do(httpClient *http.Client, method, path string, body io.Reader, contentType string) (*http.Response, error) {
+	req, err := c.NewRequest(method, path, body)
+	if err != nil {
+		return nil, err
+	}
+	if body != nil && contentType != "" {
+		req.Header.Set("Content-Type", contentType)
+	}
+
+	resp, err := httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("request failed: %w", err)
+	}
+
+	if err := checkStatus(resp); err != nil {
+		return nil, err
+	}
+	return resp, nil
+}
+
+// GetJSON performs a GET request and decodes the JSON response into result
+func (c *Client) GetJSON(path string, result interface{}) error {
+	resp, err := c.Do(http.MethodGet, path, nil, "")
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	return decodeJSON(resp, result)
+}
+
+// PostJSON performs a POST request with a JSON body and decodes the JSON response into result
+func (c *Client) PostJSON(path string, body interface{}, result interface{}) error {
+	var bodyReader io.Reader
+	if body != nil {
+		data, err := json.Marshal(body)
+		if err != nil {
+			return fmt.Errorf("failed to marshal request body: %w", err)
+		}
+		bodyReader = bytes.NewReader(data)
+	}
+
+	resp, err := c.Do(http.MethodPost, path, bodyReader, "application/json")
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	return decodeJSON(resp, result)
+}
+
+// checkStatus converts non-2xx responses into *gorthanc.HTTPError, closing the body
+func checkStatus(resp *http.Response) error {
+	if resp.StatusCode >= 200 && resp.StatusCode < 300 {
+		return nil
+	}
+	defer resp.Body.Close()
+
+	bodyBytes, _ := io.ReadAll(resp.Body)
+	return &gorthanc.HTTPError{
+		StatusCode: resp.StatusCode,
+		Status:     resp.Status,
+		Body:       string(bodyBytes),
+	}
+}
+
+// decodeJSON decodes a JSON response body into result, if result is not nil
+func decodeJSON(resp *http.Response, result interface{}) error {
+	if result == nil {
+		return nil
+	}
+	if err := json.NewDecoder(resp.Body).Decode(result); err != nil {
+		return fmt.Errorf("failed to decode response: %w", err)
+	}
+	return nil
+}
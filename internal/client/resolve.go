@@ -0,0 +1,186 @@
+package client
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"regexp"
+	"strings"
+
+	"github.com/proencaj/gorthanc/types"
+	"github.com/proencaj/orthanc-cli/internal/clierr"
+)
+
+// Prefixes that select how an identifier is resolved
+const (
+	PrefixUID       = "uid:"
+	PrefixAccession = "acc:"
+	PrefixPatientID = "pid:"
+)
+
+// orthancIDPattern matches Orthanc identifiers (SHA-1 in five dash-separated groups)
+var orthancIDPattern = regexp.MustCompile(`^[0-9a-f]{8}(-[0-9a-f]{8}){4}$`)
+
+// dicomUIDPattern matches dotted numeric DICOM UIDs
+var dicomUIDPattern = regexp.MustCompile(`^[0-9]+(\.[0-9]+)+$`)
+
+// LookupResult is a resource matched by /tools/lookup
+type LookupResult struct {
+	ID   string `json:"ID"`
+	Path string `json:"Path,omitempty"`
+	Type string `json:"Type"`
+}
+
+// IsOrthancID reports whether value looks like an Orthanc identifier
+func IsOrthancID(value string) bool {
+	return orthancIDPattern.MatchString(value)
+}
+
+// ResolveID turns a user-supplied identifier into the Orthanc ID of a
+// resource at the given level. Accepted forms are:
+//
+//	<orthanc-id>     used as is
+//	<dotted UID>     StudyInstanceUID, SeriesInstanceUID or SOPInstanceUID
+//	uid:<value>      any DICOM identifier known to /tools/lookup
+//	acc:<value>      AccessionNumber
+//	pid:<value>      PatientID
+//
+// Anything else is returned unchanged and left for the server to reject.
+// An empty level accepts a resource at any level.
+func (c *Client) ResolveID(level types.ResourceLevel, value string) (string, error) {
+	value = strings.TrimSpace(value)
+
+	switch {
+	case IsOrthancID(value):
+		return value, nil
+	case strings.HasPrefix(value, PrefixUID):
+		return c.resolveByLookup(level, strings.TrimPrefix(value, PrefixUID), value)
+	case strings.HasPrefix(value, PrefixAccession):
+		return c.resolveByTag(level, "AccessionNumber", strings.TrimPrefix(value, PrefixAccession), value)
+	case strings.HasPrefix(value, PrefixPatientID):
+		return c.resolveByTag(level, "PatientID", strings.TrimPrefix(value, PrefixPatientID), value)
+	case dicomUIDPattern.MatchString(value):
+		return c.resolveByLookup(level, value, value)
+	}
+
+	return value, nil
+}
+
+// Lookup finds the resources whose DICOM identifier (PatientID,
+// StudyInstanceUID, SeriesInstanceUID or SOPInstanceUID) equals value
+func (c *Client) Lookup(value string) ([]LookupResult, error) {
+	resp, err := c.Do(http.MethodPost, "tools/lookup", strings.NewReader(value), "text/plain")
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read response body: %w", err)
+	}
+
+	results := []LookupResult{}
+	if err := json.Unmarshal(body, &results); err != nil {
+		return nil, fmt.Errorf("failed to decode response: %w", err)
+	}
+	return results, nil
+}
+
+// resolveByLookup resolves a DICOM identifier with /tools/lookup
+func (c *Client) resolveByLookup(level types.ResourceLevel, value, original string) (string, error) {
+	if value == "" {
+		return "", clierr.Validation("empty identifier '%s'", original)
+	}
+
+	results, err := c.Lookup(value)
+	if err != nil {
+		return "", fmt.Errorf("failed to look up '%s': %w", original, err)
+	}
+
+	var ids []string
+	var otherLevels []string
+	for _, result := range results {
+		if level == "" || result.Type == string(level) {
+			ids = append(ids, result.ID)
+		} else {
+			otherLevels = append(otherLevels, strings.ToLower(result.Type))
+		}
+	}
+
+	if len(ids) == 0 && len(otherLevels) > 0 {
+		return "", clierr.NotFound("'%s' identifies a %s, not a %s", original, strings.Join(otherLevels, "/"), levelName(level))
+	}
+	return pickResolved(level, original, ids)
+}
+
+// resolveByTag resolves a main DICOM tag value with /tools/find
+func (c *Client) resolveByTag(level types.ResourceLevel, tag, value, original string) (string, error) {
+	if value == "" {
+		return "", clierr.Validation("empty identifier '%s'", original)
+	}
+
+	// Accession numbers live on studies, patient IDs on patients
+	findLevel := level
+	if findLevel == "" {
+		findLevel = types.ResourceLevelStudy
+		if tag == "PatientID" {
+			findLevel = types.ResourceLevelPatient
+		}
+	}
+	if findLevel == types.ResourceLevelPatient && tag == "AccessionNumber" {
+		return "", clierr.Validation("'%s' cannot identify a patient, use pid:<PatientID>", original)
+	}
+
+	ids, err := c.Find(&types.ToolsFindRequest{
+		Level: findLevel,
+		Query: map[string]string{tag: value},
+	})
+	if err != nil {
+		return "", fmt.Errorf("failed to resolve '%s': %w", original, err)
+	}
+
+	return pickResolved(findLevel, original, ids)
+}
+
+// pickResolved returns the single resolved ID, or an error when there are none or several
+func pickResolved(level types.ResourceLevel, original string, ids []string) (string, error) {
+	switch len(ids) {
+	case 0:
+		return "", clierr.NotFound("no %s matches '%s'", levelName(level), original)
+	case 1:
+		return ids[0], nil
+	}
+
+	const shown = 5
+	candidates := ids
+	more := ""
+	if len(candidates) > shown {
+		more = fmt.Sprintf(" and %d more", len(candidates)-shown)
+		candidates = candidates[:shown]
+	}
+	return "", clierr.Validation("'%s' is ambiguous, it matches %d %s: %s%s; use an Orthanc ID instead",
+		original, len(ids), pluralLevelName(level), strings.Join(candidates, ", "), more)
+}
+
+// levelName returns a lower-case name for a resource level
+func levelName(level types.ResourceLevel) string {
+	if level == "" {
+		return "resource"
+	}
+	return strings.ToLower(string(level))
+}
+
+// pluralLevelName returns the lower-case plural of a resource level
+func pluralLevelName(level types.ResourceLevel) string {
+	switch level {
+	case types.ResourceLevelStudy:
+		return "studies"
+	case types.ResourceLevelSeries:
+		return "series"
+	case "":
+		return "resources"
+	}
+	return levelName(level) + "s"
+}
@@ -0,0 +1,35 @@
+package client
+
+import (
+	"net/http"
+
+	"github.com/proencaj/gorthanc/types"
+)
+
+// ResourcePath returns the REST path of a resource, e.g. studies/<id>
+func ResourcePath(level types.ResourceLevel, id string) string {
+	return pluralLevelName(level) + "/" + id
+}
+
+// PluralLevelName returns the lower-case plural of a resource level, e.g. "studies"
+func PluralLevelName(level types.ResourceLevel) string {
+	return pluralLevelName(level)
+}
+
+// GetResourceStatistics returns the statistics of a patient, study, series or instance
+func (c *Client) GetResourceStatistics(level types.ResourceLevel, id string) (*types.Statistics, error) {
+	var statistics types.Statistics
+	if err := c.GetJSON(ResourcePath(level, id)+"/statistics", &statistics); err != nil {
+		return nil, err
+	}
+	return &statistics, nil
+}
+
+// DeleteResource deletes a patient, study, series or instance
+func (c *Client) DeleteResource(level types.ResourceLevel, id string) error {
+	resp, err := c.Do(http.MethodDelete, ResourcePath(level, id), nil, "")
+	if err != nil {
+		return err
+	}
+	return resp.Body.Close()
+}
@@ -0,0 +1,23 @@
+package client
+
+// ServerStatistics is the response of /statistics: resource counts and
+// storage usage of the whole server. Sizes are strings in the Orthanc API.
+type ServerStatistics struct {
+	CountPatients           int    `json:"CountPatients"`
+	CountStudies            int    `json:"CountStudies"`
+	CountSeries             int    `json:"CountSeries"`
+	CountInstances          int    `json:"CountInstances"`
+	TotalDiskSize           string `json:"TotalDiskSize"`
+	TotalDiskSizeMB         int    `json:"TotalDiskSizeMB"`
+	TotalUncompressedSize   string `json:"TotalUncompressedSize"`
+	TotalUncompressedSizeMB int    `json:"TotalUncompressedSizeMB"`
+}
+
+// GetStatistics returns the statistics of the whole server
+func (c *Client) GetStatistics() (*ServerStatistics, error) {
+	var statistics ServerStatistics
+	if err := c.GetJSON("statistics", &statistics); err != nil {
+		return nil, err
+	}
+	return &statistics, nil
+}
@@ -0,0 +1,89 @@
+package client
+
+import (
+	"fmt"
+	"net/url"
+	"regexp"
+	"strings"
+
+	"github.com/proencaj/gorthanc/types"
+	"github.com/proencaj/orthanc-cli/internal/clierr"
+)
+
+// TransferSyntax is a named DICOM transfer syntax
+type TransferSyntax struct {
+	Alias string
+	UID   string
+}
+
+// TransferSyntaxes lists the aliases accepted by --transcode
+var TransferSyntaxes = []TransferSyntax{
+	{Alias: "implicit-little", UID: "1.2.840.10008.1.2"},
+	{Alias: "explicit-little", UID: "1.2.840.10008.1.2.1"},
+	{Alias: "deflated", UID: "1.2.840.10008.1.2.1.99"},
+	{Alias: "jpeg-baseline", UID: "1.2.840.10008.1.2.4.50"},
+	{Alias: "jpeg-extended", UID: "1.2.840.10008.1.2.4.51"},
+	{Alias: "jpeg-lossless", UID: "1.2.840.10008.1.2.4.70"},
+	{Alias: "jpeg-ls-lossless", UID: "1.2.840.10008.1.2.4.80"},
+	{Alias: "jpeg-ls", UID: "1.2.840.10008.1.2.4.81"},
+	{Alias: "jpeg2000-lossless", UID: "1.2.840.10008.1.2.4.90"},
+	{Alias: "jpeg2000", UID: "1.2.840.10008.1.2.4.91"},
+	{Alias: "rle", UID: "1.2.840.10008.1.2.5"},
+}
+
+// transferSyntaxUIDPattern matches transfer syntax UIDs
+var transferSyntaxUIDPattern = regexp.MustCompile(`^1\.2\.840\.10008\.1\.2(\.[0-9]+)*$`)
+
+// ResolveTransferSyntax converts a transfer syntax alias or UID to a UID
+func (c *Client) ResolveTransferSyntax(value string) (string, error) {
+	value = strings.TrimSpace(value)
+	for _, syntax := range TransferSyntaxes {
+		if strings.EqualFold(syntax.Alias, value) {
+			return syntax.UID, nil
+		}
+	}
+	if transferSyntaxUIDPattern.MatchString(value) {
+		return value, nil
+	}
+
+	aliases := make([]string, 0, len(TransferSyntaxes))
+	for _, syntax := range TransferSyntaxes {
+		aliases = append(aliases, syntax.Alias)
+	}
+	return "", clierr.Validation("unknown transfer syntax '%s', use a transfer syntax UID or one of: %s",
+		value, strings.Join(aliases, ", "))
+}
+
+// TranscodedPath returns path with the transcode parameter for the given
+// transfer syntax UID, or path unchanged when transferSyntax is empty
+func (c *Client) TranscodedPath(path, transferSyntax string) (string, error) {
+	if transferSyntax == "" {
+		return path, nil
+	}
+	if err := c.RequireFeature(FeatureTranscoding); err != nil {
+		return "", err
+	}
+	return path + "?transcode=" + url.QueryEscape(transferSyntax), nil
+}
+
+// StoreToModalityTranscoded performs a C-STORE, transcoding the instances to the
+// given transfer syntax UID before they are sent
+func (c *Client) StoreToModalityTranscoded(modalityName string, request *types.ModalityStoreRequest, transferSyntax string) (*types.ModalityStoreResult, error) {
+	if err := c.RequireFeature(FeatureTranscoding); err != nil {
+		return nil, err
+	}
+
+	body := struct {
+		*types.ModalityStoreRequest
+		Transcode string `json:"Transcode"`
+	}{
+		ModalityStoreRequest: request,
+		Transcode:            transferSyntax,
+	}
+
+	var result types.ModalityStoreResult
+	if err := c.PostJSON(fmt.Sprintf("modalities/%s/store", url.PathEscape(modalityName)), body, &result); err != nil {
+		return nil, err
+	}
+	return &result, nil
+}
@@ -0,0 +1,119 @@
+package client
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+)
+
+// WorklistStepSequence is the sequence holding the scheduled procedure steps
+// of a worklist
+const WorklistStepSequence = "ScheduledProcedureStepSequence"
+
+// WorklistStepTags are the attributes of a scheduled procedure step, which
+// belong in the WorklistStepSequence of a worklist
+var WorklistStepTags = map[string]bool{
+	"Modality":                          true,
+	"ScheduledStationAETitle":           true,
+	"ScheduledStationName":              true,
+	"ScheduledProcedureStepLocation":    true,
+	"ScheduledProcedureStepStartDate":   true,
+	"ScheduledProcedureStepStartTime":   true,
+	"ScheduledPerformingPhysicianName":  true,
+	"ScheduledProcedureStepDescription": true,
+	"ScheduledProcedureStepID":          true,
+	"ScheduledProcedureStepStatus":      true,
+}
+
+// Worklist is a modality worklist entry of the worklists plugin, with its
+// DICOM tags in the simplified format
+type Worklist struct {
+	ID   string                 `json:"ID"`
+	Tags map[string]interface{} `json:"Tags"`
+}
+
+// WorklistCreated is the answer of /worklists/create
+type WorklistCreated struct {
+	ID   string `json:"ID"`
+	Path string `json:"Path"`
+}
+
+// ListWorklists returns the worklists of the worklists plugin. Versions of
+// the plugin that only list IDs get the tags of each worklist fetched.
+func (c *Client) ListWorklists() ([]Worklist, error) {
+	var entries []json.RawMessage
+	if err := c.GetJSON("worklists", &entries); err != nil {
+		return nil, err
+	}
+
+	worklists := make([]Worklist, 0, len(entries))
+	for _, entry := range entries {
+		var id string
+		if err := json.Unmarshal(entry, &id); err == nil {
+			worklist, err := c.GetWorklist(id)
+			if err != nil {
+				return nil, fmt.Errorf("failed to fetch worklist %s: %w", id, err)
+			}
+			worklists = append(worklists, *worklist)
+			continue
+		}
+
+		var worklist Worklist
+		if err := json.Unmarshal(entry, &worklist); err != nil {
+			return nil, fmt.Errorf("failed to decode response: %w", err)
+		}
+		worklists = append(worklists, worklist)
+	}
+	return worklists, nil
+}
+
+// GetWorklist returns a worklist. The plugin answers either the worklist
+// with its ID or only its tags.
+func (c *Client) GetWorklist(id string) (*Worklist, error) {
+	var tags map[string]interface{}
+	if err := c.GetJSON("worklists/"+url.PathEscape(id), &tags); err != nil {
+		return nil, err
+	}
+	if nested, ok := tags["Tags"].(map[string]interface{}); ok {
+		tags = nested
+	}
+	return &Worklist{ID: id, Tags: tags}, nil
+}
+
+// CreateWorklist creates a worklist from its DICOM tags
+func (c *Client) CreateWorklist(tags map[string]interface{}) (*WorklistCreated, error) {
+	request := struct {
+		Tags map[string]interface{} `json:"Tags"`
+	}{tags}
+
+	var created WorklistCreated
+	if err := c.PostJSON("worklists/create", request, &created); err != nil {
+		return nil, err
+	}
+	return &created, nil
+}
+
+// DeleteWorklist deletes a worklist
+func (c *Client) DeleteWorklist(id string) error {
+	resp, err := c.Do(http.MethodDelete, "worklists/"+url.PathEscape(id), nil, "")
+	if err != nil {
+		return err
+	}
+	return resp.Body.Close()
+}
+
+// FindWorklistRequest is the body of /modalities/{id}/find-worklist
+type FindWorklistRequest struct {
+	Query    map[string]interface{} `json:"Query"`
+	Simplify bool                   `json:"Simplify,omitempty"`
+}
+
+// FindWorklist issues a worklist C-FIND to a modality and returns the answers
+func (c *Client) FindWorklist(modality string, request *FindWorklistRequest) ([]map[string]interface{}, error) {
+	var answers []map[string]interface{}
+	if err := c.PostJSON("modalities/"+url.PathEscape(modality)+"/find-worklist", request, &answers); err != nil {
+		return nil, err
+	}
+	return answers, nil
+}
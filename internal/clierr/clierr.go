@@ -0,0 +1,237 @@
+// Package clierr defines the error taxonomy of the CLI and the exit code
+// associated with each kind of error.
+//
+// Exit codes:
+//
+//	0   success
+//	1   unclassified error
+//	2   validation error (invalid arguments, flags, input files or configuration)
+//	3   resource not found (HTTP 404)
+//	4   unauthorized (HTTP 401)
+//	5   forbidden (HTTP 403)
+//	6   conflict (HTTP 409)
+//	7   network error (server unreachable, connection refused, DNS, TLS)
+//	8   timeout (client timeout, HTTP 408 or 504)
+//	9   server error (HTTP 5xx)
+//	10  partial failure (some items of a batch operation failed)
+//	11  unsupported (the server lacks a required version or plugin)
+//
+// The health command instead exits with the monitoring plugin codes 0 to 3,
+// through a Status error.
+package clierr
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+
+	"github.com/proencaj/gorthanc"
+)
+
+// Kind identifies a category of error
+type Kind string
+
+const (
+	KindGeneric      Kind = "error"
+	KindValidation   Kind = "validation"
+	KindNotFound     Kind = "not_found"
+	KindUnauthorized Kind = "unauthorized"
+	KindForbidden    Kind = "forbidden"
+	KindConflict     Kind = "conflict"
+	KindNetwork      Kind = "network"
+	KindTimeout      Kind = "timeout"
+	KindServer       Kind = "server_error"
+	KindPartial      Kind = "partial_failure"
+	KindUnsupported  Kind = "unsupported"
+)
+
+// exitCodes maps each kind of error to its process exit code
+var exitCodes = map[Kind]int{
+	KindGeneric:      1,
+	KindValidation:   2,
+	KindNotFound:     3,
+	KindUnauthorized: 4,
+	KindForbidden:    5,
+	KindConflict:     6,
+	KindNetwork:      7,
+	KindTimeout:      8,
+	KindServer:       9,
+	KindPartial:      10,
+	KindUnsupported:  11,
+}
+
+// Error is an error tagged with a Kind
+type Error struct {
+	Kind    Kind
+	Message string
+	Status  int
+	Err     error
+}
+
+// Error implements the error interface
+func (e *Error) Error() string {
+	switch {
+	case e.Message != "" && e.Err != nil:
+		return fmt.Sprintf("%s: %v", e.Message, e.Err)
+	case e.Message != "":
+		return e.Message
+	case e.Err != nil:
+		return e.Err.Error()
+	}
+	return string(e.Kind)
+}
+
+// Unwrap returns the underlying error
+func (e *Error) Unwrap() error {
+	return e.Err
+}
+
+// New creates an error of the given kind with a formatted message
+func New(kind Kind, format string, args ...interface{}) error {
+	return &Error{Kind: kind, Message: fmt.Sprintf(format, args...)}
+}
+
+// Wrap tags err with the given kind
+func Wrap(kind Kind, err error) error {
+	if err == nil {
+		return nil
+	}
+	return &Error{Kind: kind, Err: err}
+}
+
+// Validation creates a validation error with a formatted message
+func Validation(format string, args ...interface{}) error {
+	return New(KindValidation, format, args...)
+}
+
+// NotFound creates a not found error with a formatted message
+func NotFound(format string, args ...interface{}) error {
+	return New(KindNotFound, format, args...)
+}
+
+// HTTPStatus creates an error for an unexpected HTTP status code
+func HTTPStatus(status int, format string, args ...interface{}) error {
+	return &Error{
+		Kind:    kindForStatus(status),
+		Message: fmt.Sprintf("%s: HTTP %d", fmt.Sprintf(format, args...), status),
+		Status:  status,
+	}
+}
+
+// Partial creates a partial failure error for a batch operation
+func Partial(failed, total int) error {
+	return New(KindPartial, "%d of %d operation(s) failed", failed, total)
+}
+
+// Status is an error carrying an explicit exit code, for commands such as
+// health whose exit codes follow another convention. The command has already
+// reported its outcome, so the error itself is not printed.
+type Status struct {
+	Code int
+}
+
+// Error implements the error interface
+func (s *Status) Error() string {
+	return fmt.Sprintf("exit status %d", s.Code)
+}
+
+// ExitStatus creates a Status error with the given exit code, or nil for 0
+func ExitStatus(code int) error {
+	if code == 0 {
+		return nil
+	}
+	return &Status{Code: code}
+}
+
+// KindOf classifies err, inspecting tagged errors, gorthanc HTTP errors and
+// network errors anywhere in the wrapped chain
+func KindOf(err error) Kind {
+	if err == nil {
+		return ""
+	}
+
+	var tagged *Error
+	if errors.As(err, &tagged) {
+		return tagged.Kind
+	}
+
+	var httpErr *gorthanc.HTTPError
+	if errors.As(err, &httpErr) {
+		return kindForStatus(httpErr.StatusCode)
+	}
+
+	if errors.Is(err, context.DeadlineExceeded) {
+		return KindTimeout
+	}
+
+	var netErr net.Error
+	if errors.As(err, &netErr) && netErr.Timeout() {
+		return KindTimeout
+	}
+
+	var urlErr *url.Error
+	var opErr *net.OpError
+	var dnsErr *net.DNSError
+	if errors.As(err, &urlErr) || errors.As(err, &opErr) || errors.As(err, &dnsErr) {
+		return KindNetwork
+	}
+
+	return KindGeneric
+}
+
+// StatusOf returns the HTTP status code carried by err, or 0
+func StatusOf(err error) int {
+	var tagged *Error
+	if errors.As(err, &tagged) && tagged.Status != 0 {
+		return tagged.Status
+	}
+
+	var httpErr *gorthanc.HTTPError
+	if errors.As(err, &httpErr) {
+		return httpErr.StatusCode
+	}
+
+	return 0
+}
+
+// ExitCode returns the process exit code for err
+func ExitCode(err error) int {
+	if err == nil {
+		return 0
+	}
+
+	var status *Status
+	if errors.As(err, &status) {
+		return status.Code
+	}
+
+	if code, ok := exitCodes[KindOf(err)]; ok {
+		return code
+	}
+	return 1
+}
+
+// kindForStatus maps an HTTP status code to a Kind
+func kindForStatus(status int) Kind {
+	switch {
+	case status == http.StatusBadRequest, status == http.StatusUnprocessableEntity,
+		status == http.StatusUnsupportedMediaType:
+		return KindValidation
+	case status == http.StatusUnauthorized:
+		return KindUnauthorized
+	case status == http.StatusForbidden:
+		return KindForbidden
+	case status == http.StatusNotFound:
+		return KindNotFound
+	case status == http.StatusConflict:
+		return KindConflict
+	case status == http.StatusRequestTimeout, status == http.StatusGatewayTimeout:
+		return KindTimeout
+	case status >= 500:
+		return KindServer
+	}
+	return KindGeneric
+}
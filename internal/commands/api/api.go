@@ -0,0 +1,552 @@
+package api
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"net/url"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/proencaj/orthanc-cli/internal/client"
+	"github.com/proencaj/orthanc-cli/internal/clierr"
+	"github.com/proencaj/orthanc-cli/internal/config"
+	"github.com/proencaj/orthanc-cli/internal/download"
+	"github.com/proencaj/orthanc-cli/internal/guard"
+	"github.com/spf13/cobra"
+)
+
+// clientGetter is a function type that returns an Orthanc client
+var clientGetter func() (*client.Client, error)
+
+// SetClientGetter sets the function to get the Orthanc client
+func SetClientGetter(getter func() (*client.Client, error)) {
+	clientGetter = getter
+}
+
+// getClient returns the Orthanc client using the configured getter
+func getClient() (*client.Client, error) {
+	if clientGetter != nil {
+		return clientGetter()
+	}
+	// Fallback: try to load config from default location
+	cfg, err := config.LoadConfig("")
+	if err != nil {
+		return nil, err
+	}
+	return client.NewClient(cfg)
+}
+
+// validMethods lists the HTTP methods accepted by the Orthanc REST API
+var validMethods = map[string]bool{
+	http.MethodGet:    true,
+	http.MethodPost:   true,
+	http.MethodPut:    true,
+	http.MethodDelete: true,
+	http.MethodHead:   true,
+}
+
+// APIFlags holds the flags for the api command
+type APIFlags struct {
+	fields      []string
+	typedFields []string
+	headers     []string
+	input       string
+	contentType string
+	output      string
+	include     bool
+	paginate    bool
+	pageSize    int
+}
+
+// NewAPICommand creates the api command
+func NewAPICommand() *cobra.Command {
+	flags := &APIFlags{}
+
+	command := &cobra.Command{
+		Use:   "api <method> <path>",
+		Short: "Make an authenticated request to the Orthanc REST API",
+		Long: `Make a raw request to any endpoint of the Orthanc REST API, reusing the URL,
+credentials and TLS settings of the current context.
+
+Fields given with -f (string values) or -F (typed values: true, false, null,
+numbers, or @file to read the value from a file) are sent as query parameters
+for GET, HEAD and DELETE requests, and as a JSON object body otherwise.
+Use --input to send a request body from a file (or - for stdin) instead. Its
+content type is detected from the first bytes unless given with --content-type.
+
+JSON responses are pretty-printed. Binary responses can be streamed to a file
+with --output. Requests answering JSON or text time out after 30 seconds;
+uploads, binary responses and responses saved with --output do not.
+
+With --paginate, list endpoints supporting since/limit are fetched page by page
+and the results are merged into a single JSON array.`,
+		Example: `  # Get system information
+  orthanc api GET /system
+
+  # List studies with query parameters
+  orthanc api GET /studies -f expand=true -f limit=5
+
+  # Fetch all instances, page by page
+  orthanc api GET /instances --paginate
+
+  # Look up a DICOM UID
+  orthanc api POST /tools/lookup --input - <<< "1.2.840.113619.2.55.3"
+
+  # Send a JSON body built from fields
+  orthanc api POST /tools/find -f Level=Study -F Expand=true -F 'Query={"PatientID":"12345"}'
+
+  # Send a JSON body from a file
+  orthanc api POST /tools/find --input query.json
+
+  # Change a configuration value
+  orthanc api PUT /tools/log-level --input - <<< "verbose"
+
+  # Upload a DICOM file
+  orthanc api POST /instances --input image.dcm --content-type application/dicom
+
+  # Download a study archive
+  orthanc api GET /studies/abc123/archive -o study.zip`,
+		Annotations: map[string]string{guard.Annotation: guard.ByMethod},
+		Args:        cobra.ExactArgs(2),
+		RunE: func(c *cobra.Command, args []string) error {
+			return runAPI(strings.ToUpper(args[0]), args[1], flags)
+		},
+	}
+
+	// Add flags
+	command.Flags().StringArrayVarP(&flags.fields, "field", "f", nil, "Add a string parameter in key=value format (can be specified multiple times)")
+	command.Flags().StringArrayVarP(&flags.typedFields, "typed-field", "F", nil, "Add a typed parameter in key=value format (can be specified multiple times)")
+	command.Flags().StringArrayVarP(&flags.headers, "header", "H", nil, "Add an HTTP request header in key:value format (can be specified multiple times)")
+	command.Flags().StringVar(&flags.input, "input", "", "File to use as the request body (use - for stdin)")
+	command.Flags().StringVar(&flags.contentType, "content-type", "", "Content type of the --input body (detected by default)")
+	command.Flags().StringVarP(&flags.output, "output", "o", "", "Write the response body to a file instead of stdout")
+	command.Flags().BoolVarP(&flags.include, "include", "i", false, "Print the HTTP status line and response headers")
+	command.Flags().BoolVar(&flags.paginate, "paginate", false, "Fetch all pages of a list endpoint using since/limit")
+	command.Flags().IntVar(&flags.pageSize, "page-size", 100, "Number of items per page with --paginate")
+
+	return command
+}
+
+func runAPI(method, path string, flags *APIFlags) error {
+	if !validMethods[method] {
+		return clierr.Validation("invalid method '%s', must be one of: GET, POST, PUT, DELETE, HEAD", method)
+	}
+	if flags.input != "" && (len(flags.fields) > 0 || len(flags.typedFields) > 0) && !sendsQuery(method) {
+		return clierr.Validation("--input cannot be combined with fields for %s requests", method)
+	}
+	if flags.contentType != "" && flags.input == "" {
+		return clierr.Validation("--content-type requires --input")
+	}
+	if flags.paginate && method != http.MethodGet {
+		return clierr.Validation("--paginate is only supported for GET requests")
+	}
+	if flags.paginate && flags.pageSize <= 0 {
+		return clierr.Validation("--page-size must be greater than 0")
+	}
+
+	// Get the Orthanc client
+	orthanc, err := getClient()
+	if err != nil {
+		return fmt.Errorf("failed to create client: %w", err)
+	}
+
+	// Collect the parameters
+	params, err := parseFields(flags.fields, flags.typedFields)
+	if err != nil {
+		return err
+	}
+
+	headers, err := parseHeaders(flags.headers)
+	if err != nil {
+		return err
+	}
+
+	if sendsQuery(method) {
+		path, err = addQueryParams(path, params)
+		if err != nil {
+			return err
+		}
+		params = nil
+	}
+
+	if flags.paginate {
+		return runPaginated(orthanc, path, headers, flags)
+	}
+
+	// Build the request body
+	body, contentType, err := buildBody(flags.input, flags.contentType, params)
+	if err != nil {
+		return err
+	}
+	if closer, ok := body.(io.Closer); ok {
+		defer closer.Close()
+	}
+
+	// Uploads and binary responses such as archives may take longer than the
+	// client's timeout: send without it, and only bound the requests expected
+	// to answer JSON or text
+	ctx, cancel := context.WithCancelCause(context.Background())
+	defer cancel(nil)
+	timedOut := clierr.New(clierr.KindTimeout, "%s %s did not complete within %s", method, path, client.RequestTimeout)
+	timer := time.AfterFunc(client.RequestTimeout, func() { cancel(timedOut) })
+	defer timer.Stop()
+	if flags.input != "" || flags.output != "" {
+		timer.Stop()
+	}
+
+	req, err := orthanc.NewRequest(method, path, body)
+	if err != nil {
+		return err
+	}
+	req = req.WithContext(ctx)
+	if contentType != "" {
+		req.Header.Set("Content-Type", contentType)
+	}
+	for name, value := range headers {
+		req.Header.Set(name, value)
+	}
+
+	resp, err := orthanc.SendStream(req)
+	if err != nil {
+		return timeoutCause(ctx, err)
+	}
+	defer resp.Body.Close()
+	if isBinary(resp) {
+		timer.Stop()
+	}
+
+	if flags.include {
+		printResponseHeaders(resp)
+	}
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		// Show Orthanc's error details before failing with the mapped exit code
+		if err := writeResponse(resp, os.Stderr, ""); err != nil {
+			return timeoutCause(ctx, err)
+		}
+		return clierr.HTTPStatus(resp.StatusCode, "%s %s failed", method, path)
+	}
+
+	if err := writeResponse(resp, os.Stdout, flags.output); err != nil {
+		return timeoutCause(ctx, err)
+	}
+	return nil
+}
+
+// timeoutCause returns the timeout error if ctx was cancelled by it, else err
+func timeoutCause(ctx context.Context, err error) error {
+	if cause := context.Cause(ctx); cause != nil {
+		return cause
+	}
+	return err
+}
+
+// runPaginated fetches a list endpoint page by page and prints the merged array
+func runPaginated(orthanc *client.Client, path string, headers map[string]string, flags *APIFlags) error {
+	merged := []json.RawMessage{}
+	var previousFirst json.RawMessage
+
+	// Pages saved to a file may add up to more than the client's timeout
+	send := orthanc.Send
+	if flags.output != "" {
+		send = orthanc.SendStream
+	}
+
+	for since := 0; ; since += flags.pageSize {
+		pagePath, err := addQueryParams(path, map[string]interface{}{
+			"since": since,
+			"limit": flags.pageSize,
+		})
+		if err != nil {
+			return err
+		}
+
+		req, err := orthanc.NewRequest(http.MethodGet, pagePath, nil)
+		if err != nil {
+			return err
+		}
+		for name, value := range headers {
+			req.Header.Set(name, value)
+		}
+
+		resp, err := send(req)
+		if err != nil {
+			return err
+		}
+
+		data, err := io.ReadAll(resp.Body)
+		resp.Body.Close()
+		if err != nil {
+			return fmt.Errorf("failed to read response: %w", err)
+		}
+		if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+			fmt.Fprintln(os.Stderr, string(data))
+			return clierr.HTTPStatus(resp.StatusCode, "GET %s failed", pagePath)
+		}
+
+		var page []json.RawMessage
+		if err := json.Unmarshal(data, &page); err != nil {
+			return clierr.Validation("--paginate requires an endpoint returning a JSON array: %v", err)
+		}
+
+		// An endpoint ignoring since/limit would otherwise be fetched forever:
+		// it answers more than limit items, or the same page again
+		if len(page) > flags.pageSize || (len(page) > 0 && bytes.Equal(page[0], previousFirst)) {
+			return clierr.Validation("endpoint %s does not support since/limit pagination", path)
+		}
+		if len(page) > 0 {
+			previousFirst = page[0]
+		}
+
+		merged = append(merged, page...)
+		if len(page) < flags.pageSize {
+			break
+		}
+	}
+
+	data, err := json.MarshalIndent(merged, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal JSON: %w", err)
+	}
+
+	if flags.output != "" {
+		if err := os.WriteFile(flags.output, append(data, '\n'), 0644); err != nil {
+			return fmt.Errorf("failed to write output file: %w", err)
+		}
+		fmt.Fprintf(os.Stderr, "Saved %d item(s) to: %s\n", len(merged), flags.output)
+		return nil
+	}
+
+	fmt.Println(string(data))
+	return nil
+}
+
+// sendsQuery reports whether fields are sent as query parameters for a method
+func sendsQuery(method string) bool {
+	return method == http.MethodGet || method == http.MethodHead || method == http.MethodDelete
+}
+
+// parseFields parses -f (string) and -F (typed) fields into a parameter map
+func parseFields(fields, typedFields []string) (map[string]interface{}, error) {
+	params := make(map[string]interface{})
+
+	for _, field := range fields {
+		key, value, ok := strings.Cut(field, "=")
+		if !ok || key == "" {
+			return nil, clierr.Validation("invalid field '%s', expected key=value", field)
+		}
+		params[key] = value
+	}
+
+	for _, field := range typedFields {
+		key, value, ok := strings.Cut(field, "=")
+		if !ok || key == "" {
+			return nil, clierr.Validation("invalid field '%s', expected key=value", field)
+		}
+		typed, err := parseTypedValue(value)
+		if err != nil {
+			return nil, err
+		}
+		params[key] = typed
+	}
+
+	return params, nil
+}
+
+// parseTypedValue converts a -F value into a JSON-compatible value
+func parseTypedValue(value string) (interface{}, error) {
+	switch value {
+	case "true":
+		return true, nil
+	case "false":
+		return false, nil
+	case "null":
+		return nil, nil
+	}
+
+	if strings.HasPrefix(value, "@") {
+		data, err := os.ReadFile(strings.TrimPrefix(value, "@"))
+		if err != nil {
+			return nil, clierr.Validation("failed to read field value: %v", err)
+		}
+		return strings.TrimRight(string(data), "\r\n"), nil
+	}
+
+	if n, err := strconv.ParseInt(value, 10, 64); err == nil {
+		return n, nil
+	}
+	if f, err := strconv.ParseFloat(value, 64); err == nil {
+		return f, nil
+	}
+
+	// JSON arrays and objects are passed through as-is
+	if strings.HasPrefix(value, "[") || strings.HasPrefix(value, "{") {
+		var raw json.RawMessage
+		if err := json.Unmarshal([]byte(value), &raw); err == nil {
+			return raw, nil
+		}
+	}
+
+	return value, nil
+}
+
+// parseHeaders parses -H values in key:value format
+func parseHeaders(headers []string) (map[string]string, error) {
+	result := make(map[string]string, len(headers))
+	for _, header := range headers {
+		name, value, ok := strings.Cut(header, ":")
+		if !ok || strings.TrimSpace(name) == "" {
+			return nil, clierr.Validation("invalid header '%s', expected key:value", header)
+		}
+		result[strings.TrimSpace(name)] = strings.TrimSpace(value)
+	}
+	return result, nil
+}
+
+// addQueryParams appends parameters to the query string of a path
+func addQueryParams(path string, params map[string]interface{}) (string, error) {
+	if len(params) == 0 {
+		return path, nil
+	}
+
+	u, err := url.Parse(path)
+	if err != nil {
+		return "", clierr.Validation("invalid path '%s': %v", path, err)
+	}
+
+	query := u.Query()
+	for key, value := range params {
+		if value == nil {
+			query.Set(key, "")
+			continue
+		}
+		query.Set(key, fmt.Sprint(value))
+	}
+	u.RawQuery = query.Encode()
+
+	return u.String(), nil
+}
+
+// inputBody reads a buffered --input body and closes the underlying file
+type inputBody struct {
+	*bufio.Reader
+	io.Closer
+}
+
+// buildBody returns the request body and its content type
+func buildBody(input, contentType string, params map[string]interface{}) (io.Reader, string, error) {
+	switch {
+	case input != "":
+		file := os.Stdin
+		if input != "-" {
+			var err error
+			file, err = os.Open(input)
+			if err != nil {
+				return nil, "", clierr.Validation("failed to open input file: %v", err)
+			}
+		}
+		body := inputBody{Reader: bufio.NewReader(file), Closer: file}
+		if contentType == "" {
+			contentType = detectContentType(body.Reader)
+		}
+		return body, contentType, nil
+	case len(params) > 0:
+		data, err := json.Marshal(params)
+		if err != nil {
+			return nil, "", fmt.Errorf("failed to marshal request body: %w", err)
+		}
+		return bytes.NewReader(data), "application/json", nil
+	}
+	return nil, "", nil
+}
+
+// detectContentType guesses the content type of a body from its first bytes
+func detectContentType(body *bufio.Reader) string {
+	head, _ := body.Peek(512)
+	trimmed := bytes.TrimLeft(head, " \t\r\n")
+	if len(trimmed) > 0 && (trimmed[0] == '{' || trimmed[0] == '[') {
+		return "application/json"
+	}
+	// DICOM files start with a 128-byte preamble followed by "DICM"
+	if len(head) >= 132 && string(head[128:132]) == "DICM" {
+		return "application/dicom"
+	}
+	return http.DetectContentType(head)
+}
+
+// printResponseHeaders prints the status line and headers to stdout
+func printResponseHeaders(resp *http.Response) {
+	fmt.Printf("%s %s\n", resp.Proto, resp.Status)
+
+	names := make([]string, 0, len(resp.Header))
+	for name := range resp.Header {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		for _, value := range resp.Header[name] {
+			fmt.Printf("%s: %s\n", name, value)
+		}
+	}
+	fmt.Println()
+}
+
+// writeResponse writes the response body to a file, or to w with JSON pretty-printed
+func writeResponse(resp *http.Response, w io.Writer, output string) error {
+	if output != "" {
+		outFile, err := os.Create(output)
+		if err != nil {
+			return fmt.Errorf("failed to create output file: %w", err)
+		}
+		defer outFile.Close()
+
+		written, err := io.Copy(outFile, resp.Body)
+		if err != nil {
+			return fmt.Errorf("failed to write response to file: %w", err)
+		}
+		fmt.Fprintf(os.Stderr, "Saved response to: %s (%.2f MB)\n", output, float64(written)/(1024*1024))
+		return nil
+	}
+
+	mediaType, _, _ := mime.ParseMediaType(resp.Header.Get("Content-Type"))
+	if mediaType != "application/json" && !strings.HasSuffix(mediaType, "+json") {
+		if file, ok := w.(*os.File); ok && download.IsTerminal(file) && isBinary(resp) {
+			return clierr.Validation("refusing to write a %s response to the terminal, use --output", mediaType)
+		}
+		_, err := io.Copy(w, resp.Body)
+		return err
+	}
+
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return fmt.Errorf("failed to read response: %w", err)
+	}
+
+	var pretty bytes.Buffer
+	if err := json.Indent(&pretty, data, "", "  "); err != nil {
+		// Not valid JSON after all: print it unchanged
+		_, err := w.Write(data)
+		return err
+	}
+	fmt.Fprintln(w, pretty.String())
+	return nil
+}
+
+// isBinary reports whether a response has a content type other than JSON or text
+func isBinary(resp *http.Response) bool {
+	mediaType, _, _ := mime.ParseMediaType(resp.Header.Get("Content-Type"))
+	switch {
+	case mediaType == "", mediaType == "application/json", strings.HasSuffix(mediaType, "+json"):
+		return false
+	}
+	return !strings.HasPrefix(mediaType, "text/")
+}
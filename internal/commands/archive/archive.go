@@ -0,0 +1,51 @@
+package archive
+
+import (
+	"github.com/proencaj/orthanc-cli/internal/client"
+	"github.com/proencaj/orthanc-cli/internal/config"
+	"github.com/spf13/cobra"
+)
+
+// clientGetter is a function type that returns an Orthanc client
+var clientGetter func() (*client.Client, error)
+
+// SetClientGetter sets the function to get the Orthanc client
+func SetClientGetter(getter func() (*client.Client, error)) {
+	clientGetter = getter
+}
+
+// getClient returns the Orthanc client using the configured getter
+func getClient() (*client.Client, error) {
+	if clientGetter != nil {
+		return clientGetter()
+	}
+	// Fallback: try to load config from default location
+	cfg, err := config.LoadConfig("")
+	if err != nil {
+		return nil, err
+	}
+	return client.NewClient(cfg)
+}
+
+// shouldUseJSON checks if JSON output is enabled in config
+func shouldUseJSON() bool {
+	cfg, err := config.LoadConfig("")
+	if err != nil {
+		return false
+	}
+	return cfg.Output.JSON
+}
+
+// NewArchiveCommand creates the archive command with all subcommands
+func NewArchiveCommand() *cobra.Command {
+	archiveCmd := &cobra.Command{
+		Use:   "archive",
+		Short: "Create archives and DICOMDIR media from several resources",
+		Long:  `Bundle patients, studies, series and instances into a single ZIP archive or DICOMDIR media.`,
+	}
+
+	// Add subcommands
+	archiveCmd.AddCommand(NewCreateCommand())
+
+	return archiveCmd
+}
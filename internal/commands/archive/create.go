@@ -0,0 +1,221 @@
+package archive
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/proencaj/orthanc-cli/internal/batch"
+	"github.com/proencaj/orthanc-cli/internal/client"
+	"github.com/proencaj/orthanc-cli/internal/clierr"
+	"github.com/proencaj/orthanc-cli/internal/download"
+	"github.com/spf13/cobra"
+)
+
+// CreateFlags holds the flags for the archive create command
+type CreateFlags struct {
+	output       string
+	media        bool
+	extended     bool
+	async        bool
+	pollInterval time.Duration
+	jobTimeout   time.Duration
+	transcode    string
+	retries      int
+	jsonOutput   bool
+}
+
+// createResult summarizes a created archive
+type createResult struct {
+	Output    string   `json:"Output"`
+	Size      int64    `json:"Size"`
+	Resources []string `json:"Resources"`
+	Media     bool     `json:"Media"`
+	JobID     string   `json:"JobID,omitempty"`
+}
+
+// NewCreateCommand creates the archive create command
+func NewCreateCommand() *cobra.Command {
+	flags := &CreateFlags{}
+
+	command := &cobra.Command{
+		Use:   "create [resource-id...]",
+		Short: "Create a ZIP archive or DICOMDIR media from several resources",
+		Long: `Create a single ZIP archive containing several patients, studies, series or
+instances. Resources are given as arguments, or read from standard input (one
+per line) when no argument or "-" is given. DICOM UIDs and uid:, acc: and pid:
+identifiers are resolved like in the per-resource commands.
+
+With --media the archive has a DICOMDIR layout suitable for burning CDs, and
+--extended adds extended DICOMDIR information (implies --media).
+
+Large exports should use --async: the archive is then built by an Orthanc job,
+whose progress is reported until it finishes and the result is downloaded.`,
+		Example: `  # Archive two studies into one ZIP file
+  orthanc archive create abc123 def456 -o export.zip
+
+  # Create DICOMDIR media for a patient CD
+  orthanc archive create pid:12345 --media -o patient-cd.zip
+
+  # Archive every study, reading IDs from stdin, as an asynchronous job
+  orthanc studies list | orthanc archive create --async -o all-studies.zip
+
+  # Transcode to Explicit VR Little Endian while archiving
+  orthanc archive create abc123 --media --transcode explicit-little`,
+		RunE: func(c *cobra.Command, args []string) error {
+			return runCreate(args, flags)
+		},
+	}
+
+	// Add flags
+	command.Flags().StringVarP(&flags.output, "output", "o", "", "Output file (defaults to archive-<timestamp>.zip in the current directory)")
+	command.Flags().BoolVar(&flags.media, "media", false, "Create DICOMDIR media instead of a plain archive")
+	command.Flags().BoolVar(&flags.extended, "extended", false, "Include extended DICOMDIR information (implies --media)")
+	command.Flags().BoolVar(&flags.async, "async", false, "Build the archive in an Orthanc job and download it when finished")
+	command.Flags().DurationVar(&flags.pollInterval, "poll-interval", 2*time.Second, "Interval between job status checks with --async")
+	command.Flags().DurationVar(&flags.jobTimeout, "job-timeout", time.Hour, "Time to wait for the archive job with --async (0 for no limit)")
+	command.Flags().IntVar(&flags.retries, "retries", download.DefaultRetries, "Number of retries if the download is interrupted")
+	command.Flags().StringVar(&flags.transcode, "transcode", "", "Transcode to a transfer syntax UID or alias (e.g. explicit-little, jpeg2000-lossless, jpeg-baseline)")
+	command.Flags().BoolVar(&flags.jsonOutput, "json", false, "Output a JSON summary")
+
+	return command
+}
+
+func runCreate(args []string, flags *CreateFlags) error {
+	resources, err := batch.ReadIDs(args)
+	if err != nil {
+		return err
+	}
+	if flags.pollInterval <= 0 {
+		return clierr.Validation("--poll-interval must be positive")
+	}
+	if flags.jobTimeout < 0 {
+		return clierr.Validation("--job-timeout must not be negative")
+	}
+
+	format := client.ArchiveZip
+	switch {
+	case flags.extended:
+		format = client.ArchiveMediaExtended
+	case flags.media:
+		format = client.ArchiveMedia
+	}
+	media := format != client.ArchiveZip
+	request := &client.ArchiveRequest{}
+
+	// Get the Orthanc client
+	client, err := getClient()
+	if err != nil {
+		return fmt.Errorf("failed to create client: %w", err)
+	}
+
+	jsonOutput := flags.jsonOutput || shouldUseJSON()
+
+	// Resolve the transfer syntax alias to its UID
+	if flags.transcode != "" {
+		request.Transcode, err = client.ResolveTransferSyntax(flags.transcode)
+		if err != nil {
+			return err
+		}
+	}
+
+	// Resolve DICOM UIDs and prefixed identifiers to Orthanc IDs
+	for _, resource := range resources {
+		id, err := client.ResolveID("", resource)
+		if err != nil {
+			return err
+		}
+		request.Resources = append(request.Resources, id)
+	}
+
+	outputPath := flags.output
+	if outputPath == "" {
+		outputPath = fmt.Sprintf("archive-%s.zip", time.Now().Format("20060102-150405"))
+	}
+	if dir := filepath.Dir(outputPath); dir != "." {
+		if err := os.MkdirAll(dir, 0755); err != nil {
+			return fmt.Errorf("failed to create parent directory: %w", err)
+		}
+	}
+
+	// Progress goes to stderr so that --json output stays clean
+	status := os.Stdout
+	if jsonOutput {
+		status = os.Stderr
+	}
+
+	result := &createResult{
+		Output:    outputPath,
+		Resources: request.Resources,
+		Media:     media,
+	}
+
+	var fetch download.Fetcher
+	if flags.async {
+		result.JobID, err = client.SubmitArchiveJob(format, request)
+		if err != nil {
+			return fmt.Errorf("failed to start archive job: %w", err)
+		}
+		fmt.Fprintf(status, "Started archive job: %s\n", result.JobID)
+
+		ctx := context.Background()
+		if flags.jobTimeout > 0 {
+			var cancel context.CancelFunc
+			ctx, cancel = context.WithTimeout(ctx, flags.jobTimeout)
+			defer cancel()
+		}
+		if _, err := client.WaitForJob(ctx, result.JobID, flags.pollInterval, printJobProgress(status)); err != nil {
+			return err
+		}
+
+		fetch = func(offset int64) (*http.Response, error) {
+			return client.DownloadJobArchive(result.JobID, offset)
+		}
+	} else {
+		fmt.Fprintf(status, "Creating archive of %d resource(s)...\n", len(request.Resources))
+
+		// The archive is generated on the fly, so a retry starts over
+		fetch = func(offset int64) (*http.Response, error) {
+			return client.CreateArchive(format, request)
+		}
+	}
+
+	downloaded, err := download.ToFile(outputPath, fetch, download.Options{
+		Retries: flags.retries,
+		Verify:  download.VerifyZip(0),
+	})
+	if err != nil {
+		return fmt.Errorf("failed to download archive: %w", err)
+	}
+	result.Size = downloaded.Size
+
+	if jsonOutput {
+		data, err := json.MarshalIndent(result, "", "  ")
+		if err != nil {
+			return fmt.Errorf("failed to marshal JSON: %w", err)
+		}
+		fmt.Println(string(data))
+		return nil
+	}
+
+	fmt.Printf("Successfully downloaded archive to: %s\n", outputPath)
+	fmt.Printf("Size: %.2f MB\n", float64(result.Size)/(1024*1024))
+
+	return nil
+}
+
+// printJobProgress returns a job callback that prints progress changes to out
+func printJobProgress(out io.Writer) func(*client.Job) {
+	lastProgress := -1
+	return func(job *client.Job) {
+		if job.Progress != lastProgress {
+			fmt.Fprintf(out, "  %s: %d%%\n", job.State, job.Progress)
+			lastProgress = job.Progress
+		}
+	}
+}
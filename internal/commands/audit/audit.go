@@ -0,0 +1,37 @@
+package audit
+
+import (
+	"github.com/proencaj/orthanc-cli/internal/config"
+	"github.com/spf13/cobra"
+)
+
+// shouldUseJSON checks if JSON output is enabled in config
+func shouldUseJSON() bool {
+	cfg, err := config.LoadConfig("")
+	if err != nil {
+		return false
+	}
+	return cfg.Output.JSON
+}
+
+// NewAuditCommand creates the audit command with all subcommands
+func NewAuditCommand() *cobra.Command {
+	auditCmd := &cobra.Command{
+		Use:   "audit",
+		Short: "Show the local audit log of changes",
+		Long: `Every command that changes an Orthanc server (remove, anonymize, upload,
+modalities store/move, modalities and servers create/update/remove, tools
+reset/shutdown, delete, api requests other than GET...) is recorded in an
+append-only audit log, ~/.orthanc-cli/audit.jsonl (or $ORTHANC_AUDIT_LOG).
+
+Each line is a JSON object with the time, OS user and host, context and server
+URL, command, arguments and flags (secrets redacted), the resources changed,
+the requests sent and the outcome: success, failure, partial, or no-change when
+nothing was sent to the server (e.g. cancelled at the confirmation prompt).`,
+	}
+
+	// Add subcommands
+	auditCmd.AddCommand(NewShowCommand())
+
+	return auditCmd
+}
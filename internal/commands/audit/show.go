@@ -0,0 +1,151 @@
+package audit
+
+import (
+	"encoding/json"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	internalAudit "github.com/proencaj/orthanc-cli/internal/audit"
+	"github.com/proencaj/orthanc-cli/internal/clierr"
+	"github.com/proencaj/orthanc-cli/internal/helpers"
+	"github.com/spf13/cobra"
+)
+
+// outcomes are the valid values of --outcome
+var outcomes = []string{
+	internalAudit.OutcomeSuccess,
+	internalAudit.OutcomeFailure,
+	internalAudit.OutcomePartial,
+	internalAudit.OutcomeNoChange,
+}
+
+// ShowFlags holds the flags for the show command
+type ShowFlags struct {
+	since      string
+	user       string
+	context    string
+	command    string
+	resource   string
+	outcome    string
+	limit      int
+	jsonOutput bool
+}
+
+// NewShowCommand creates the audit show command
+func NewShowCommand() *cobra.Command {
+	flags := &ShowFlags{}
+
+	command := &cobra.Command{
+		Use:   "show",
+		Short: "Show entries of the audit log",
+		Long:  `Show the entries of the audit log, oldest first, optionally filtered.`,
+		Example: `  # Show the changes of the last 24 hours
+  orthanc audit show --since 24h
+
+  # Show who removed a study
+  orthanc audit show --command remove --resource 1a2b3c4d
+
+  # Show the failed changes on production this week, as JSON
+  orthanc audit show --context production --outcome failure --since 7d --json`,
+		Args: cobra.NoArgs,
+		RunE: func(c *cobra.Command, args []string) error {
+			return runShow(flags)
+		},
+	}
+
+	// Add flags
+	command.Flags().StringVar(&flags.since, "since", "", "Only show entries newer than this age (e.g. 24h, 7d)")
+	command.Flags().StringVar(&flags.user, "user", "", "Only show entries of this OS user")
+	command.Flags().StringVar(&flags.context, "context", "", "Only show entries of this context, including as source or destination")
+	command.Flags().StringVar(&flags.command, "command", "", "Only show commands containing this text (e.g. remove, 'studies anonymize')")
+	command.Flags().StringVar(&flags.resource, "resource", "", "Only show entries whose resources or arguments contain this ID")
+	command.Flags().StringVar(&flags.outcome, "outcome", "", "Only show entries with this outcome: success, failure, partial, no-change")
+	command.Flags().IntVar(&flags.limit, "limit", 0, "Only show the last N matching entries (0 for all)")
+	command.Flags().BoolVar(&flags.jsonOutput, "json", false, "Output in JSON format")
+
+	return command
+}
+
+func runShow(flags *ShowFlags) error {
+	if flags.outcome != "" && !slices.Contains(outcomes, flags.outcome) {
+		return clierr.Validation("invalid outcome '%s', must be one of: %s", flags.outcome, strings.Join(outcomes, ", "))
+	}
+	if flags.limit < 0 {
+		return clierr.Validation("--limit must not be negative")
+	}
+
+	var cutoff time.Time
+	if flags.since != "" {
+		age, err := helpers.ParseAge(flags.since)
+		if err != nil {
+			return err
+		}
+		cutoff = time.Now().Add(-age)
+	}
+
+	// Check if JSON output should be used (flag or config)
+	jsonOutput := flags.jsonOutput || shouldUseJSON()
+
+	entries, err := internalAudit.Read(func(entry *internalAudit.Entry) bool {
+		return entry.Time.After(cutoff) &&
+			(flags.user == "" || entry.User == flags.user) &&
+			(flags.context == "" || entry.Context == flags.context || entry.Source == flags.context || entry.Destination == flags.context) &&
+			(flags.command == "" || strings.Contains(entry.Command, flags.command)) &&
+			(flags.outcome == "" || entry.Outcome == flags.outcome) &&
+			(flags.resource == "" || mentions(entry, flags.resource))
+	})
+	if err != nil {
+		return err
+	}
+	if flags.limit > 0 && len(entries) > flags.limit {
+		entries = entries[len(entries)-flags.limit:]
+	}
+
+	if jsonOutput {
+		if entries == nil {
+			entries = []*internalAudit.Entry{}
+		}
+		data, err := json.MarshalIndent(entries, "", "  ")
+		if err != nil {
+			return fmt.Errorf("failed to marshal JSON: %w", err)
+		}
+		fmt.Println(string(data))
+		return nil
+	}
+
+	if len(entries) == 0 {
+		fmt.Println("No audit entries found.")
+		return nil
+	}
+
+	fmt.Printf("%-19s  %-12s  %-12s  %-9s  %s\n", "TIME", "USER", "CONTEXT", "OUTCOME", "COMMAND")
+	for _, entry := range entries {
+		line := strings.Join(append([]string{strings.TrimPrefix(entry.Command, "orthanc ")}, entry.Args...), " ")
+		context := entry.Context
+		if entry.Source != "" {
+			context = entry.Source + "->" + entry.Destination
+		}
+		fmt.Printf("%-19s  %-12s  %-12s  %-9s  %s\n", entry.Time.Local().Format("2006-01-02 15:04:05"),
+			entry.User, context, entry.Outcome, line)
+		if len(entry.Resources) > 0 {
+			fmt.Printf("%-19s  resources: %s\n", "", strings.Join(entry.Resources, " "))
+		}
+		if entry.Error != "" {
+			fmt.Printf("%-19s  error: %s\n", "", entry.Error)
+		}
+	}
+
+	return nil
+}
+
+// mentions reports whether an entry targets a resource, by ID or argument
+func mentions(entry *internalAudit.Entry, id string) bool {
+	for _, value := range slices.Concat(entry.Resources, entry.Args) {
+		if strings.Contains(value, id) {
+			return true
+		}
+	}
+	return false
+}
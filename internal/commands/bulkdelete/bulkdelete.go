@@ -0,0 +1,441 @@
+package bulkdelete
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/proencaj/gorthanc/types"
+	"github.com/proencaj/orthanc-cli/internal/client"
+	"github.com/proencaj/orthanc-cli/internal/clierr"
+	"github.com/proencaj/orthanc-cli/internal/config"
+	"github.com/proencaj/orthanc-cli/internal/guard"
+	"github.com/proencaj/orthanc-cli/internal/helpers"
+	"github.com/proencaj/orthanc-cli/internal/parallel"
+	"github.com/proencaj/orthanc-cli/internal/trash"
+	"github.com/spf13/cobra"
+)
+
+// clientGetter is a function type that returns an Orthanc client
+var clientGetter func() (*client.Client, error)
+
+// SetClientGetter sets the function to get the Orthanc client
+func SetClientGetter(getter func() (*client.Client, error)) {
+	clientGetter = getter
+}
+
+// getClient returns the Orthanc client using the configured getter
+func getClient() (*client.Client, error) {
+	if clientGetter != nil {
+		return clientGetter()
+	}
+	// Fallback: try to load config from default location
+	cfg, err := config.LoadConfig("")
+	if err != nil {
+		return nil, err
+	}
+	return client.NewClient(cfg)
+}
+
+// shouldUseJSON checks if JSON output is enabled in config
+func shouldUseJSON() bool {
+	cfg, err := config.LoadConfig("")
+	if err != nil {
+		return false
+	}
+	return cfg.Output.JSON
+}
+
+// DefaultMax is the default maximum number of resources deleted at once
+const DefaultMax = 100
+
+// summaryTags are the main DICOM tags shown for each matching resource
+var summaryTags = map[types.ResourceLevel][]string{
+	types.ResourceLevelPatient:  {"PatientID", "PatientName"},
+	types.ResourceLevelStudy:    {"PatientID", "StudyDate", "AccessionNumber", "StudyDescription"},
+	types.ResourceLevelSeries:   {"Modality", "SeriesNumber", "SeriesDescription"},
+	types.ResourceLevelInstance: {"InstanceNumber", "SOPInstanceUID"},
+}
+
+// DeleteFlags holds the flags for the delete command
+type DeleteFlags struct {
+	level            string
+	tags             map[string]string
+	labels           []string
+	labelsConstraint string
+	dryRun           bool
+	max              int
+	parallel         int
+	confirm          string
+	jsonOutput       bool
+}
+
+// matchedResource is a resource selected for deletion
+type matchedResource struct {
+	ID        string            `json:"ID"`
+	Tags      map[string]string `json:"MainDicomTags"`
+	Instances int               `json:"CountInstances"`
+	DiskSize  int64             `json:"DiskSize"`
+}
+
+// deleteFailure records a resource that could not be deleted
+type deleteFailure struct {
+	ID    string `json:"ID"`
+	Error string `json:"Error"`
+}
+
+// deleteReport is the JSON output of the delete command
+type deleteReport struct {
+	Context   string            `json:"Context"`
+	Level     string            `json:"Level"`
+	DryRun    bool              `json:"DryRun"`
+	Matched   int               `json:"Matched"`
+	Instances int               `json:"CountInstances"`
+	DiskSize  int64             `json:"DiskSize"`
+	Deleted   int               `json:"Deleted"`
+	Failed    []deleteFailure   `json:"Failed,omitempty"`
+	Trashed   []string          `json:"Trashed,omitempty"`
+	Resources []matchedResource `json:"Resources,omitempty"`
+}
+
+// NewDeleteCommand creates the delete command
+func NewDeleteCommand() *cobra.Command {
+	flags := &DeleteFlags{
+		tags: make(map[string]string),
+	}
+
+	command := &cobra.Command{
+		Use:   "delete",
+		Short: "Delete all resources matching a query",
+		Long: `Find resources with the same query as 'tools find' and delete them all.
+
+A summary with the number of matching resources, their instances and their
+disk size is shown first. Use --dry-run to list the matching resources without
+deleting anything.
+
+Deleting requires typing the name of the current context, or passing it with
+--confirm for non-interactive use. Queries matching more than --max resources
+are refused, and at least one --tag or --label filter is required.
+
+Resources are deleted in parallel; if some deletions fail, the others still
+proceed and the command exits with the partial failure code. When the trash
+setting is enabled for the context, each resource is saved to the local trash
+before it is deleted.`,
+		Example: `  # Show what would be deleted
+  orthanc delete --level Study --tag StudyDate=-20150101 --label obsolete --dry-run
+
+  # Delete old studies labelled as obsolete, confirming interactively
+  orthanc delete --level Study --tag StudyDate=-20150101 --label obsolete
+
+  # Delete without a prompt, allowing up to 1000 studies
+  orthanc delete --level Study --tag StudyDate=-20150101 --max 1000 --confirm production`,
+		Annotations: map[string]string{
+			guard.Annotation:  guard.Destructive,
+			guard.ConfirmFlag: "confirm",
+		},
+		Args: cobra.NoArgs,
+		RunE: func(c *cobra.Command, args []string) error {
+			return runDelete(flags)
+		},
+	}
+
+	// Add flags
+	command.Flags().StringVar(&flags.level, "level", "Study", "Query level (Patient, Study, Series, Instance)")
+	command.Flags().StringToStringVar(&flags.tags, "tag", nil, "DICOM tag and value for query (can be specified multiple times)")
+	command.Flags().StringSliceVar(&flags.labels, "label", nil, "Filter resources by labels (Orthanc 1.12.0+)")
+	command.Flags().StringVar(&flags.labelsConstraint, "labels-constraint", "", "How to apply label filters: All, Any, None (Orthanc 1.12.0+)")
+	command.Flags().BoolVar(&flags.dryRun, "dry-run", false, "List the matching resources without deleting them")
+	command.Flags().IntVar(&flags.max, "max", DefaultMax, "Refuse to delete more than this number of resources")
+	command.Flags().IntVar(&flags.parallel, "parallel", parallel.DefaultWorkers, "Number of parallel deletions")
+	command.Flags().StringVar(&flags.confirm, "confirm", "", "Name of the current context, to delete without a prompt")
+	command.Flags().BoolVar(&flags.jsonOutput, "json", false, "Output in JSON format")
+
+	return command
+}
+
+func runDelete(flags *DeleteFlags) error {
+	// Validate level
+	level := types.ResourceLevel(flags.level)
+	if _, ok := summaryTags[level]; !ok {
+		return clierr.Validation("invalid level '%s', must be one of: Patient, Study, Series, Instance", flags.level)
+	}
+	if len(flags.tags) == 0 && len(flags.labels) == 0 {
+		return clierr.Validation("at least one --tag or --label filter is required")
+	}
+	if flags.max < 1 {
+		return clierr.Validation("--max must be at least 1")
+	}
+	if flags.parallel < 1 {
+		return clierr.Validation("--parallel must be at least 1")
+	}
+	if !flags.dryRun && flags.confirm == "" && !guard.Confirmed() && (flags.jsonOutput || shouldUseJSON()) {
+		return clierr.Validation("--confirm is required with JSON output")
+	}
+	singular, plural := strings.ToLower(flags.level), client.PluralLevelName(level)
+
+	// Get the Orthanc client
+	client, err := getClient()
+	if err != nil {
+		return fmt.Errorf("failed to create client: %w", err)
+	}
+
+	// Check if JSON output should be used (flag or config)
+	jsonOutput := flags.jsonOutput || shouldUseJSON()
+
+	if flags.confirm != "" && flags.confirm != client.ContextName() {
+		return clierr.Validation("--confirm %q does not match the current context %q", flags.confirm, client.ContextName())
+	}
+
+	// Find the matching resources
+	request := &types.ToolsFindRequest{
+		Level:            level,
+		Query:            flags.tags,
+		Expand:           helpers.BoolPtr(true),
+		Labels:           flags.labels,
+		LabelsConstraint: flags.labelsConstraint,
+	}
+	if request.Query == nil {
+		request.Query = make(map[string]string)
+	}
+	resources, err := findResources(client, request)
+	if err != nil {
+		return err
+	}
+
+	report := &deleteReport{
+		Context: client.ContextName(),
+		Level:   flags.level,
+		DryRun:  flags.dryRun,
+		Matched: len(resources),
+	}
+
+	if len(resources) == 0 {
+		if jsonOutput {
+			return printJSON(report)
+		}
+		fmt.Printf("No %s match the query.\n", plural)
+		return nil
+	}
+	if len(resources) > flags.max {
+		return clierr.Validation("the query matches %s, more than --max %d; narrow the query or raise --max",
+			countNoun(len(resources), singular, plural), flags.max)
+	}
+
+	// Collect the instance count and disk size of every resource
+	errs := parallel.ForEach(len(resources), flags.parallel, func(i int) error {
+		statistics, err := client.GetResourceStatistics(level, resources[i].ID)
+		if err != nil {
+			return fmt.Errorf("failed to get statistics of %s: %w", resources[i].ID, err)
+		}
+		resources[i].Instances = statistics.CountInstances
+		resources[i].DiskSize, _ = strconv.ParseInt(statistics.DiskSize, 10, 64)
+		return nil
+	})
+	for _, err := range errs {
+		if err != nil {
+			return err
+		}
+	}
+	for _, resource := range resources {
+		report.Instances += resource.Instances
+		report.DiskSize += resource.DiskSize
+	}
+
+	if flags.dryRun {
+		report.Resources = resources
+		if jsonOutput {
+			return printJSON(report)
+		}
+		printSummary(report, flags, plural)
+		fmt.Println()
+		printResources(resources, summaryTags[level])
+		fmt.Println("\nDry run: nothing was deleted.")
+		return nil
+	}
+
+	if !jsonOutput {
+		printSummary(report, flags, plural)
+	}
+
+	// Keep a local copy of each resource before deleting it, if enabled for the context
+	useTrash := client.GetConfig().TrashEnabled()
+
+	// Require the context name as confirmation, unless already typed for a protected context
+	if flags.confirm == "" && !guard.Confirmed() {
+		confirmed, err := confirmDeletion(report.Context, countNoun(len(resources), singular, plural), useTrash)
+		if err != nil {
+			return fmt.Errorf("failed to get confirmation: %w", err)
+		}
+		if !confirmed {
+			fmt.Println("Operation cancelled")
+			return nil
+		}
+	}
+
+	// Delete the resources
+	trashed := make([]*trash.Entry, len(resources))
+	errs = parallel.ForEach(len(resources), flags.parallel, func(i int) error {
+		var err error
+		trashed[i], err = deleteResource(client, level, resources[i].ID, useTrash)
+		return err
+	})
+	for i, err := range errs {
+		if err != nil {
+			report.Failed = append(report.Failed, deleteFailure{ID: resources[i].ID, Error: err.Error()})
+		} else if trashed[i] != nil {
+			report.Trashed = append(report.Trashed, trashed[i].ID)
+		}
+	}
+	report.Deleted = len(resources) - len(report.Failed)
+
+	if jsonOutput {
+		if err := printJSON(report); err != nil {
+			return err
+		}
+	} else {
+		for _, failure := range report.Failed {
+			fmt.Fprintf(os.Stderr, "Failed to delete %s: %s\n", failure.ID, failure.Error)
+		}
+		fmt.Printf("\nDeleted %d of %s\n", report.Deleted, countNoun(len(resources), singular, plural))
+		if len(report.Trashed) > 0 {
+			fmt.Printf("Kept %d in the local trash (see 'orthanc trash list')\n", len(report.Trashed))
+		}
+	}
+
+	if len(report.Failed) == len(resources) {
+		return fmt.Errorf("failed to delete %s: %w", plural, errs[0])
+	}
+	if len(report.Failed) > 0 {
+		return clierr.Partial(len(report.Failed), len(resources))
+	}
+
+	return nil
+}
+
+// deleteResource deletes a resource, first saving it to the trash if useTrash is set
+func deleteResource(c *client.Client, level types.ResourceLevel, id string, useTrash bool) (*trash.Entry, error) {
+	var entry *trash.Entry
+	if useTrash {
+		var err error
+		entry, err = trash.Save(c, level, id)
+		if err != nil {
+			return nil, fmt.Errorf("failed to keep a copy in the trash, not deleted: %w", err)
+		}
+	}
+
+	if err := c.DeleteResource(level, id); err != nil {
+		// Do not keep a copy of a resource that still exists
+		if entry != nil {
+			trash.Remove(entry)
+		}
+		return nil, err
+	}
+	return entry, nil
+}
+
+// findResources runs the query and returns the matching resources sorted by ID
+func findResources(c *client.Client, request *types.ToolsFindRequest) ([]matchedResource, error) {
+	if len(request.Labels) > 0 {
+		if err := c.RequireFeature(client.FeatureLabels); err != nil {
+			return nil, err
+		}
+	}
+
+	results, err := c.FindExpanded(request)
+	if err != nil {
+		return nil, fmt.Errorf("search failed: %w", err)
+	}
+
+	resources := make([]matchedResource, 0, len(results))
+	for _, result := range results {
+		tags := map[string]string{}
+		for _, source := range []map[string]interface{}{result.PatientMainDicomTags, result.MainDicomTags} {
+			for name, value := range source {
+				tags[name] = fmt.Sprint(value)
+			}
+		}
+		resources = append(resources, matchedResource{ID: result.ID, Tags: tags})
+	}
+	sort.Slice(resources, func(i, j int) bool {
+		return resources[i].ID < resources[j].ID
+	})
+
+	return resources, nil
+}
+
+// printSummary prints the query and the totals of the matching resources
+func printSummary(report *deleteReport, flags *DeleteFlags, plural string) {
+	fmt.Printf("Context:    %s\n", report.Context)
+	fmt.Printf("Level:      %s\n", report.Level)
+	if len(flags.tags) > 0 {
+		query := make([]string, 0, len(flags.tags))
+		for name, value := range flags.tags {
+			query = append(query, name+"="+value)
+		}
+		sort.Strings(query)
+		fmt.Printf("Query:      %s\n", strings.Join(query, ", "))
+	}
+	if len(flags.labels) > 0 {
+		fmt.Printf("Labels:     %s\n", strings.Join(flags.labels, ", "))
+	}
+	fmt.Println()
+	fmt.Printf("%-12s %d\n", strings.ToUpper(plural[:1])+plural[1:]+":", report.Matched)
+	fmt.Printf("%-12s %d\n", "Instances:", report.Instances)
+	fmt.Printf("%-12s %.2f MB\n", "Disk size:", float64(report.DiskSize)/(1024*1024))
+}
+
+// printResources prints one line per resource with its main tags
+func printResources(resources []matchedResource, tagNames []string) {
+	fmt.Printf("%-44s  %9s  %10s  %s\n", "ID", "INSTANCES", "SIZE (MB)", "TAGS")
+	for _, resource := range resources {
+		tags := []string{}
+		for _, name := range tagNames {
+			if value := resource.Tags[name]; value != "" {
+				tags = append(tags, name+"="+value)
+			}
+		}
+		fmt.Printf("%-44s  %9d  %10.2f  %s\n", resource.ID, resource.Instances,
+			float64(resource.DiskSize)/(1024*1024), strings.Join(tags, " "))
+	}
+}
+
+// confirmDeletion asks the user to type the context name
+func confirmDeletion(contextName, resources string, useTrash bool) (bool, error) {
+	fmt.Printf("\n⚠️  WARNING: You are about to delete %s from context '%s'\n", resources, contextName)
+	if useTrash {
+		fmt.Println("A copy will be kept in the local trash and can be restored with 'orthanc trash restore'.")
+	} else {
+		fmt.Println("This operation is NOT reversible and will permanently remove all associated data.")
+	}
+	fmt.Printf("\nType the context name (%s) to confirm: ", contextName)
+
+	reader := bufio.NewReader(os.Stdin)
+	response, err := reader.ReadString('\n')
+	if err != nil {
+		return false, err
+	}
+
+	return strings.TrimSpace(response) == contextName, nil
+}
+
+// countNoun formats a count with the singular or plural noun
+func countNoun(count int, singular, plural string) string {
+	if count == 1 {
+		return "1 " + singular
+	}
+	return fmt.Sprintf("%d %s", count, plural)
+}
+
+// printJSON prints a value as indented JSON
+func printJSON(value interface{}) error {
+	data, err := json.MarshalIndent(value, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal JSON: %w", err)
+	}
+	fmt.Println(string(data))
+	return nil
+}
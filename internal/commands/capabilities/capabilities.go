@@ -0,0 +1,169 @@
+package capabilities
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/proencaj/orthanc-cli/internal/client"
+	"github.com/proencaj/orthanc-cli/internal/config"
+	"github.com/spf13/cobra"
+)
+
+// clientGetter is a function type that returns an Orthanc client
+var clientGetter func() (*client.Client, error)
+
+// SetClientGetter sets the function to get the Orthanc client
+func SetClientGetter(getter func() (*client.Client, error)) {
+	clientGetter = getter
+}
+
+// getClient returns the Orthanc client using the configured getter
+func getClient() (*client.Client, error) {
+	if clientGetter != nil {
+		return clientGetter()
+	}
+	// Fallback: try to load config from default location
+	cfg, err := config.LoadConfig("")
+	if err != nil {
+		return nil, err
+	}
+	return client.NewClient(cfg)
+}
+
+// shouldUseJSON checks if JSON output is enabled in config
+func shouldUseJSON() bool {
+	cfg, err := config.LoadConfig("")
+	if err != nil {
+		return false
+	}
+	return cfg.Output.JSON
+}
+
+// CapabilitiesFlags holds the flags for the capabilities command
+type CapabilitiesFlags struct {
+	refresh    bool
+	jsonOutput bool
+}
+
+// capabilitiesReport is the JSON output of the capabilities command
+type capabilitiesReport struct {
+	*client.Capabilities
+	Features map[string]bool `json:"Features"`
+}
+
+// NewCapabilitiesCommand creates the capabilities command
+func NewCapabilitiesCommand() *cobra.Command {
+	flags := &CapabilitiesFlags{}
+
+	command := &cobra.Command{
+		Use:   "capabilities",
+		Short: "Show which optional features the Orthanc server supports",
+		Long: `Probe the Orthanc server of the current context (version, API version and plugins)
+and show which optional CLI features it supports.
+
+The probe result is cached per context for one hour; commands that need newer
+server features use it to fail early with a clear message or to degrade gracefully.`,
+		Example: `  # Show the capabilities of the current server
+  orthanc capabilities
+
+  # Ignore the cache and probe the server again
+  orthanc capabilities --refresh
+
+  # Output in JSON format
+  orthanc capabilities --json`,
+		Args: cobra.NoArgs,
+		RunE: func(c *cobra.Command, args []string) error {
+			return runCapabilities(flags)
+		},
+	}
+
+	// Add flags
+	command.Flags().BoolVar(&flags.refresh, "refresh", false, "Probe the server again instead of using the cached result")
+	command.Flags().BoolVar(&flags.jsonOutput, "json", false, "Output in JSON format")
+
+	return command
+}
+
+func runCapabilities(flags *CapabilitiesFlags) error {
+	// Get the Orthanc client
+	orthanc, err := getClient()
+	if err != nil {
+		return fmt.Errorf("failed to create client: %w", err)
+	}
+
+	// Check if JSON output should be used (flag or config)
+	jsonOutput := flags.jsonOutput || shouldUseJSON()
+
+	// Probe the server (or use the cached probe)
+	var caps *client.Capabilities
+	if flags.refresh {
+		caps, err = orthanc.RefreshCapabilities()
+	} else {
+		caps, err = orthanc.Capabilities()
+	}
+	if err != nil {
+		return err
+	}
+
+	return displayCapabilities(caps, jsonOutput)
+}
+
+func displayCapabilities(caps *client.Capabilities, jsonOutput bool) error {
+	if jsonOutput {
+		report := capabilitiesReport{
+			Capabilities: caps,
+			Features:     make(map[string]bool, len(client.Features)),
+		}
+		for _, feature := range client.Features {
+			report.Features[feature.Name] = caps.Supports(feature.Name)
+		}
+
+		data, err := json.MarshalIndent(report, "", "  ")
+		if err != nil {
+			return fmt.Errorf("failed to marshal JSON: %w", err)
+		}
+		fmt.Println(string(data))
+		return nil
+	}
+
+	fmt.Println("Orthanc Server Capabilities")
+	fmt.Println("===========================")
+	fmt.Println()
+
+	fmt.Printf("Context:             %s\n", caps.Context)
+	fmt.Printf("URL:                 %s\n", caps.URL)
+	fmt.Printf("Server Name:         %s\n", caps.Name)
+	fmt.Printf("Version:             %s\n", caps.Version)
+	fmt.Printf("API Version:         %d\n", caps.ApiVersion)
+	if len(caps.Plugins) > 0 {
+		fmt.Printf("Plugins:             %s\n", strings.Join(caps.Plugins, ", "))
+	} else {
+		fmt.Printf("Plugins:             (none)\n")
+	}
+	fmt.Printf("Detected At:         %s\n", caps.DetectedAt.Format("2006-01-02 15:04:05"))
+	fmt.Println()
+
+	fmt.Println("Features:")
+	for _, feature := range client.Features {
+		mark := "✗"
+		if caps.Supports(feature.Name) {
+			mark = "✓"
+		}
+
+		requirement := "built-in"
+		if feature.MinVersion != "" {
+			requirement = fmt.Sprintf("Orthanc %s+", feature.MinVersion)
+		}
+		if feature.Plugin != "" {
+			requirement = fmt.Sprintf("plugin '%s'", feature.Plugin)
+			if feature.MinVersion != "" {
+				requirement += fmt.Sprintf(", Orthanc %s+", feature.MinVersion)
+			}
+		}
+
+		fmt.Printf("  %s %-16s %s (%s)\n", mark, feature.Name, feature.Description, requirement)
+	}
+
+	return nil
+}
@@ -3,6 +3,7 @@ package config
 import (
 	"fmt"
 
+	"github.com/proencaj/orthanc-cli/internal/clierr"
 	internalConfig "github.com/proencaj/orthanc-cli/internal/config"
 	"github.com/spf13/cobra"
 	"github.com/spf13/viper"
@@ -20,6 +21,9 @@ Available keys:
   orthanc.username  - Orthanc username
   orthanc.password  - Orthanc password
   orthanc.insecure  - Skip TLS verification
+  trash             - Keep a local copy of removed resources
+  read-only         - Reject commands that change the server
+  protected         - Confirm destructive commands with the context name
   output.json       - Default JSON output
 
 Examples:
@@ -36,11 +40,14 @@ Examples:
 				"orthanc.username": true,
 				"orthanc.password": true,
 				"orthanc.insecure": true,
+				"trash":            true,
+				"read-only":        true,
+				"protected":        true,
 				"output.json":      true,
 			}
 
 			if !validKeys[key] {
-				return fmt.Errorf("invalid configuration key: %s\nValid keys: orthanc.url, orthanc.username, orthanc.password, orthanc.insecure, output.json", key)
+				return clierr.Validation("invalid configuration key: %s\nValid keys: orthanc.url, orthanc.username, orthanc.password, orthanc.insecure, trash, read-only, protected, output.json", key)
 			}
 
 			// For context-specific keys, get from current context
@@ -71,6 +78,12 @@ Examples:
 					return nil
 				case "orthanc.insecure":
 					value = orthancCfg.Insecure
+				case "trash":
+					value = cfg.TrashEnabled()
+				case "read-only":
+					value = cfg.Contexts[cfg.CurrentContext].ReadOnly
+				case "protected":
+					value = cfg.Contexts[cfg.CurrentContext].Protected
 				}
 
 				if value == "" || value == nil {
@@ -75,6 +75,9 @@ func NewListCommand() *cobra.Command {
 			}
 
 			fmt.Printf("  Insecure: %v\n", orthancCfg.Insecure)
+			fmt.Printf("  Trash:    %v\n", cfg.TrashEnabled())
+			fmt.Printf("  Read-only: %v\n", cfg.Contexts[cfg.CurrentContext].ReadOnly)
+			fmt.Printf("  Protected: %v\n", cfg.Contexts[cfg.CurrentContext].Protected)
 
 			fmt.Println()
 			fmt.Println("Output Configuration:")
@@ -6,6 +6,7 @@ import (
 	"path/filepath"
 	"strconv"
 
+	"github.com/proencaj/orthanc-cli/internal/clierr"
 	internalConfig "github.com/proencaj/orthanc-cli/internal/config"
 	"github.com/spf13/cobra"
 	"github.com/spf13/viper"
@@ -23,6 +24,9 @@ Available keys:
   orthanc.username  - Orthanc username
   orthanc.password  - Orthanc password
   orthanc.insecure  - Skip TLS verification (true/false)
+  trash             - Keep a local copy of removed resources (true/false)
+  read-only         - Reject every command that changes the server (true/false)
+  protected         - Require typing the context name before destructive commands (true/false)
   output.json       - Output in JSON format by default (true/false)
 
 Examples:
@@ -30,6 +34,8 @@ Examples:
   orthanc config set orthanc.username myuser
   orthanc config set orthanc.password mypassword
   orthanc config set orthanc.insecure false
+  orthanc config set trash true
+  orthanc config set protected true
   orthanc config set output.json true`,
 		Args: cobra.ExactArgs(2),
 		RunE: func(cmd *cobra.Command, args []string) error {
@@ -42,11 +48,14 @@ Examples:
 				"orthanc.username": true,
 				"orthanc.password": true,
 				"orthanc.insecure": true,
+				"trash":            true,
+				"read-only":        true,
+				"protected":        true,
 				"output.json":      true,
 			}
 
 			if !validKeys[key] {
-				return fmt.Errorf("invalid configuration key: %s\nValid keys: orthanc.url, orthanc.username, orthanc.password, orthanc.insecure, output.json", key)
+				return clierr.Validation("invalid configuration key: %s\nValid keys: orthanc.url, orthanc.username, orthanc.password, orthanc.insecure, trash, read-only, protected, output.json", key)
 			}
 
 			// Load the config
@@ -59,12 +68,12 @@ Examples:
 			// For context-specific keys, update current context
 			if key != "output.json" {
 				if cfg.CurrentContext == "" {
-					return fmt.Errorf("no current context set (use 'orthanc config set-context <name>' to create one)")
+					return clierr.Validation("no current context set (use 'orthanc config set-context <name>' to create one)")
 				}
 
 				ctx, exists := cfg.Contexts[cfg.CurrentContext]
 				if !exists {
-					return fmt.Errorf("current context %q not found", cfg.CurrentContext)
+					return clierr.Validation("current context %q not found", cfg.CurrentContext)
 				}
 
 				switch key {
@@ -77,15 +86,33 @@ Examples:
 				case "orthanc.insecure":
 					boolValue, err := strconv.ParseBool(value)
 					if err != nil {
-						return fmt.Errorf("invalid boolean value for %s: %s (use true or false)", key, value)
+						return clierr.Validation("invalid boolean value for %s: %s (use true or false)", key, value)
 					}
 					ctx.Orthanc.Insecure = boolValue
+				case "trash":
+					boolValue, err := strconv.ParseBool(value)
+					if err != nil {
+						return clierr.Validation("invalid boolean value for %s: %s (use true or false)", key, value)
+					}
+					ctx.Trash = boolValue
+				case "read-only":
+					boolValue, err := strconv.ParseBool(value)
+					if err != nil {
+						return clierr.Validation("invalid boolean value for %s: %s (use true or false)", key, value)
+					}
+					ctx.ReadOnly = boolValue
+				case "protected":
+					boolValue, err := strconv.ParseBool(value)
+					if err != nil {
+						return clierr.Validation("invalid boolean value for %s: %s (use true or false)", key, value)
+					}
+					ctx.Protected = boolValue
 				}
 			} else {
 				// output.json is global
 				boolValue, err := strconv.ParseBool(value)
 				if err != nil {
-					return fmt.Errorf("invalid boolean value for %s: %s (use true or false)", key, value)
+					return clierr.Validation("invalid boolean value for %s: %s (use true or false)", key, value)
 				}
 				cfg.Output.JSON = boolValue
 			}
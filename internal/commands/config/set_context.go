@@ -13,11 +13,13 @@ import (
 // NewSetContextCommand creates the config set-context command
 func NewSetContextCommand() *cobra.Command {
 	var (
-		url      string
-		username string
-		password string
-		insecure bool
-		current  bool
+		url       string
+		username  string
+		password  string
+		insecure  bool
+		readOnly  bool
+		protected bool
+		current   bool
 	)
 
 	cmd := &cobra.Command{
@@ -28,7 +30,8 @@ func NewSetContextCommand() *cobra.Command {
 Examples:
   orthanc config set-context local --url http://localhost:8042 --username orthanc --password orthanc
   orthanc config set-context prod --url https://orthanc.prod.com --username admin --password secret
-  orthanc config set-context dev --url http://dev:8042 --insecure --current`,
+  orthanc config set-context dev --url http://dev:8042 --insecure --current
+  orthanc config set-context prod --protected`,
 		Args: cobra.ExactArgs(1),
 		RunE: func(cmd *cobra.Command, args []string) error {
 			contextName := args[0]
@@ -65,6 +68,12 @@ Examples:
 			if cmd.Flags().Changed("insecure") {
 				ctx.Orthanc.Insecure = insecure
 			}
+			if cmd.Flags().Changed("read-only") {
+				ctx.ReadOnly = readOnly
+			}
+			if cmd.Flags().Changed("protected") {
+				ctx.Protected = protected
+			}
 
 			// Set as current context if requested or if it's the only context
 			if current || len(cfg.Contexts) == 1 {
@@ -104,6 +113,8 @@ Examples:
 	cmd.Flags().StringVar(&username, "username", "", "Orthanc username")
 	cmd.Flags().StringVar(&password, "password", "", "Orthanc password")
 	cmd.Flags().BoolVar(&insecure, "insecure", false, "Skip TLS verification")
+	cmd.Flags().BoolVar(&readOnly, "read-only", false, "Reject every command that changes the server")
+	cmd.Flags().BoolVar(&protected, "protected", false, "Require typing the context name before destructive commands")
 	cmd.Flags().BoolVar(&current, "current", false, "Set as current context")
 
 	return cmd
@@ -14,8 +14,21 @@ func SetClientGetter(getter func() (*client.Client, error)) {
 	clientGetter = getter
 }
 
-// getClient returns the Orthanc client using the configured getter
+// getClient returns the Orthanc client, failing early if the server
+// does not have the DICOMweb plugin enabled
 func getClient() (*client.Client, error) {
+	orthanc, err := loadClient()
+	if err != nil {
+		return nil, err
+	}
+	if err := orthanc.RequireFeature(client.FeatureDicomWeb); err != nil {
+		return nil, err
+	}
+	return orthanc, nil
+}
+
+// loadClient returns the Orthanc client using the configured getter
+func loadClient() (*client.Client, error) {
 	if clientGetter != nil {
 		return clientGetter()
 	}
@@ -5,6 +5,9 @@ import (
 	"fmt"
 
 	"github.com/proencaj/gorthanc/types"
+	"github.com/proencaj/orthanc-cli/internal/client"
+	"github.com/proencaj/orthanc-cli/internal/clierr"
+	"github.com/proencaj/orthanc-cli/internal/fanout"
 	"github.com/spf13/cobra"
 )
 
@@ -37,6 +40,7 @@ type QidoFlags struct {
 	sopClassUID string
 
 	// Output
+	fanout     fanout.Flags
 	jsonOutput bool
 }
 
@@ -68,7 +72,10 @@ QIDO-RS allows searching for studies, series, and instances based on various DIC
   orthanc dicomweb qido --level instances --study-uid 1.2.3 --series-uid 1.2.3.4
 
   # Paginated results
-  orthanc dicomweb qido --level studies --limit 10 --offset 20`,
+  orthanc dicomweb qido --level studies --limit 10 --offset 20
+
+  # Find which regional servers hold the studies of a patient
+  orthanc dicomweb qido --level studies --patient-id 12345 --contexts north,south,east`,
 		Args: cobra.NoArgs,
 		RunE: func(c *cobra.Command, args []string) error {
 			return runQido(flags)
@@ -102,30 +109,29 @@ QIDO-RS allows searching for studies, series, and instances based on various DIC
 	command.Flags().StringVar(&flags.sopClassUID, "sop-class-uid", "", "SOP Class UID")
 
 	// Output
+	fanout.AddFlags(command, &flags.fanout)
 	command.Flags().BoolVar(&flags.jsonOutput, "json", false, "Output in JSON format")
 
 	return command
 }
 
 func runQido(flags *QidoFlags) error {
-	client, err := getClient()
-	if err != nil {
-		return fmt.Errorf("failed to create client: %w", err)
+	switch flags.level {
+	case "studies", "series", "instances":
+	default:
+		return clierr.Validation("invalid query level: %s (must be studies, series, or instances)", flags.level)
 	}
 
-	var results []map[string]interface{}
+	if flags.fanout.Enabled() {
+		return runQidoContexts(flags)
+	}
 
-	switch flags.level {
-	case "studies":
-		results, err = runQidoStudies(client, flags)
-	case "series":
-		results, err = runQidoSeries(client, flags)
-	case "instances":
-		results, err = runQidoInstances(client, flags)
-	default:
-		return fmt.Errorf("invalid query level: %s (must be studies, series, or instances)", flags.level)
+	client, err := getClient()
+	if err != nil {
+		return fmt.Errorf("failed to create client: %w", err)
 	}
 
+	results, err := searchQido(client, flags)
 	if err != nil {
 		return err
 	}
@@ -135,6 +141,45 @@ func runQido(flags *QidoFlags) error {
 	return displayQidoResults(results, jsonOutput)
 }
 
+// runQidoContexts runs the query against several contexts concurrently and
+// adds the context of each result as a Context attribute
+func runQidoContexts(flags *QidoFlags) error {
+	results, err := fanout.Run(&flags.fanout, func(c *client.Client) ([]map[string]interface{}, error) {
+		if err := c.RequireFeature(client.FeatureDicomWeb); err != nil {
+			return nil, err
+		}
+		return searchQido(c, flags)
+	})
+	if err != nil {
+		return err
+	}
+
+	merged := []map[string]interface{}{}
+	for _, result := range results {
+		for _, item := range result.Value {
+			item["Context"] = result.Context
+			merged = append(merged, item)
+		}
+	}
+
+	jsonOutput := flags.jsonOutput || shouldUseJSON()
+	if err := displayQidoResults(merged, jsonOutput); err != nil {
+		return err
+	}
+	return fanout.Report(results)
+}
+
+// searchQido runs the query of the level given by flags
+func searchQido(c *client.Client, flags *QidoFlags) ([]map[string]interface{}, error) {
+	switch flags.level {
+	case "series":
+		return runQidoSeries(c, flags)
+	case "instances":
+		return runQidoInstances(c, flags)
+	}
+	return runQidoStudies(c, flags)
+}
+
 func runQidoStudies(client interface {
 	QidoSearchStudies(params *types.QidoStudyQueryParams) ([]map[string]interface{}, error)
 }, flags *QidoFlags) ([]map[string]interface{}, error) {
@@ -13,6 +13,7 @@ import (
 	"strings"
 
 	"github.com/proencaj/gorthanc/types"
+	"github.com/proencaj/orthanc-cli/internal/clierr"
 	"github.com/spf13/cobra"
 )
 
@@ -132,7 +133,7 @@ func runWadoRsMetadata(client wadoRsMetadataClient, flags *WadoRsFlags) error {
 	switch {
 	case flags.instanceUID != "":
 		if flags.seriesUID == "" {
-			return fmt.Errorf("series-uid is required when instance-uid is specified")
+			return clierr.Validation("series-uid is required when instance-uid is specified")
 		}
 		metadata, err = client.WadoRsRetrieveInstanceMetadata(flags.studyUID, flags.seriesUID, flags.instanceUID)
 	case flags.seriesUID != "":
@@ -173,7 +174,7 @@ type wadoRsRenderedClient interface {
 
 func runWadoRsRendered(client wadoRsRenderedClient, flags *WadoRsFlags) error {
 	if flags.instanceUID == "" || flags.seriesUID == "" {
-		return fmt.Errorf("series-uid and instance-uid are required for rendered retrieval")
+		return clierr.Validation("series-uid and instance-uid are required for rendered retrieval")
 	}
 
 	params := &types.WadoRsRenderedParams{}
@@ -220,12 +221,12 @@ func runWadoRsBulkData(client wadoRsBulkDataClient, flags *WadoRsFlags) error {
 	switch {
 	case flags.frames != "":
 		if flags.instanceUID == "" || flags.seriesUID == "" {
-			return fmt.Errorf("series-uid and instance-uid are required when frames is specified")
+			return clierr.Validation("series-uid and instance-uid are required when frames is specified")
 		}
 		resp, err = client.WadoRsRetrieveFrames(flags.studyUID, flags.seriesUID, flags.instanceUID, flags.frames)
 	case flags.instanceUID != "":
 		if flags.seriesUID == "" {
-			return fmt.Errorf("series-uid is required when instance-uid is specified")
+			return clierr.Validation("series-uid is required when instance-uid is specified")
 		}
 		resp, err = client.WadoRsRetrieveInstance(flags.studyUID, flags.seriesUID, flags.instanceUID)
 	case flags.seriesUID != "":
@@ -0,0 +1,255 @@
+package health
+
+import (
+	"fmt"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/proencaj/orthanc-cli/internal/client"
+	"github.com/proencaj/orthanc-cli/internal/clierr"
+	"github.com/proencaj/orthanc-cli/internal/parallel"
+)
+
+// checkNames are the available checks, in the order they are reported
+var checkNames = []string{"system", "database", "modalities", "dicomweb", "jobs"}
+
+// checks maps each check name to its implementation
+var checks = map[string]func(c *client.Client, flags *HealthFlags) *checkResult{
+	"system":     checkSystem,
+	"database":   checkDatabase,
+	"modalities": checkModalities,
+	"dicomweb":   checkDicomWeb,
+	"jobs":       checkJobs,
+}
+
+// systemInfo holds the fields of /system used by the checks, including the
+// storage limits of recent Orthanc versions
+type systemInfo struct {
+	Name                string `json:"Name"`
+	Version             string `json:"Version"`
+	ApiVersion          int    `json:"ApiVersion"`
+	MaximumStorageSize  int64  `json:"MaximumStorageSize"`
+	MaximumPatientCount int64  `json:"MaximumPatientCount"`
+}
+
+// checkSystem checks connectivity, credentials and the response time of /system
+func checkSystem(c *client.Client, flags *HealthFlags) *checkResult {
+	start := time.Now()
+	var info systemInfo
+	err := c.GetJSON("system", &info)
+	elapsed := time.Since(start).Round(time.Millisecond)
+	if err != nil {
+		result := &checkResult{state: StateCritical, unreachable: true}
+		switch clierr.KindOf(err) {
+		case clierr.KindUnauthorized, clierr.KindForbidden:
+			result.Message = fmt.Sprintf("authentication failed: %v", err)
+		default:
+			result.Message = fmt.Sprintf("server not reachable: %v", err)
+		}
+		return result
+	}
+
+	result := &checkResult{
+		state:   thresholdState(elapsed.Seconds(), flags.warningTime.Seconds(), flags.criticalTime.Seconds()),
+		Message: fmt.Sprintf("Orthanc %s (%s), API %d, answered in %s", info.Version, info.Name, info.ApiVersion, elapsed),
+		Metrics: map[string]float64{"time": elapsed.Seconds()},
+	}
+	result.perfdata = []string{perf("time", elapsed.Seconds(), "s", flags.warningTime.Seconds(), flags.criticalTime.Seconds())}
+	return result
+}
+
+// checkDatabase reads /statistics, which requires a working database, and
+// compares storage usage with the limits the server enforces, if any
+func checkDatabase(c *client.Client, flags *HealthFlags) *checkResult {
+	statistics, err := c.GetStatistics()
+	if err != nil {
+		return &checkResult{state: StateCritical, Message: fmt.Sprintf("failed to read statistics: %v", err)}
+	}
+	diskSize, _ := strconv.ParseInt(statistics.TotalDiskSize, 10, 64)
+
+	result := &checkResult{
+		state: StateOK,
+		Message: fmt.Sprintf("%d patients, %d studies, %d instances, %d MB on disk",
+			statistics.CountPatients, statistics.CountStudies, statistics.CountInstances, statistics.TotalDiskSizeMB),
+		Metrics: map[string]float64{
+			"patients":  float64(statistics.CountPatients),
+			"studies":   float64(statistics.CountStudies),
+			"instances": float64(statistics.CountInstances),
+			"disk_size": float64(diskSize),
+		},
+	}
+	result.perfdata = []string{
+		perf("instances", float64(statistics.CountInstances), "", 0, 0),
+		perf("disk_size", float64(diskSize), "B", 0, 0),
+	}
+
+	var info systemInfo
+	if err := c.GetJSON("system", &info); err != nil {
+		result.state = StateUnknown
+		result.Message += fmt.Sprintf(", failed to read storage limits: %v", err)
+		return result
+	}
+
+	// Orthanc reports its limits in MB and patients, 0 meaning unlimited
+	var usage []float64
+	if info.MaximumStorageSize > 0 {
+		usage = append(usage, 100*float64(diskSize)/float64(info.MaximumStorageSize*1024*1024))
+		result.Message += fmt.Sprintf(", %.1f%% of the %d MB storage limit", usage[len(usage)-1], info.MaximumStorageSize)
+	}
+	if info.MaximumPatientCount > 0 {
+		usage = append(usage, 100*float64(statistics.CountPatients)/float64(info.MaximumPatientCount))
+		result.Message += fmt.Sprintf(", %.1f%% of the %d patients limit", usage[len(usage)-1], info.MaximumPatientCount)
+	}
+	if len(usage) > 0 {
+		used := usage[0]
+		for _, u := range usage[1:] {
+			used = max(used, u)
+		}
+		result.state = thresholdState(used, flags.warningStorage, flags.criticalStorage)
+		result.Metrics["storage_used"] = used
+		result.perfdata = append(result.perfdata, perf("storage_used", used, "%", flags.warningStorage, flags.criticalStorage))
+	}
+	return result
+}
+
+// checkModalities sends a C-ECHO to each configured modality
+func checkModalities(c *client.Client, flags *HealthFlags) *checkResult {
+	names, err := c.GetModalities()
+	if err != nil {
+		return &checkResult{state: StateUnknown, Message: fmt.Sprintf("failed to list modalities: %v", err)}
+	}
+	if len(names) == 0 {
+		return &checkResult{state: StateOK, Message: "no modalities configured"}
+	}
+
+	failed := probeAll(names, func(name string) error {
+		return c.EchoModality(name)
+	})
+	return nodesResult("modalities", "C-ECHO", names, failed)
+}
+
+// checkDicomWeb runs a QIDO-RS query through each configured DICOMweb server
+func checkDicomWeb(c *client.Client, flags *HealthFlags) *checkResult {
+	if caps, err := c.Capabilities(); err == nil && !caps.Supports(client.FeatureDicomWeb) {
+		return &checkResult{state: StateOK, Message: "DICOMweb plugin not installed"}
+	}
+
+	names, err := c.GetDicomWebServers()
+	if err != nil {
+		return &checkResult{state: StateUnknown, Message: fmt.Sprintf("failed to list DICOMweb servers: %v", err)}
+	}
+	if len(names) == 0 {
+		return &checkResult{state: StateOK, Message: "no DICOMweb servers configured"}
+	}
+
+	failed := probeAll(names, func(name string) error {
+		return c.PostJSON("dicom-web/servers/"+url.PathEscape(name)+"/get", map[string]string{"Uri": "/studies?limit=1"}, nil)
+	})
+	return nodesResult("dicomweb", "QIDO-RS", names, failed)
+}
+
+// checkJobs counts the jobs waiting to run
+func checkJobs(c *client.Client, flags *HealthFlags) *checkResult {
+	jobs, err := c.ListJobs()
+	if err != nil {
+		return &checkResult{state: StateUnknown, Message: fmt.Sprintf("failed to list jobs: %v", err)}
+	}
+
+	var queued, running, failed int
+	for _, job := range jobs {
+		switch job.State {
+		case client.JobPending, client.JobRetry:
+			queued++
+		case client.JobRunning:
+			running++
+		case client.JobFailure:
+			failed++
+		}
+	}
+
+	result := &checkResult{
+		state:   thresholdState(float64(queued), float64(flags.warningJobs), float64(flags.criticalJobs)),
+		Message: fmt.Sprintf("%d queued, %d running, %d failed recently", queued, running, failed),
+		Metrics: map[string]float64{
+			"jobs_queued":  float64(queued),
+			"jobs_running": float64(running),
+			"jobs_failed":  float64(failed),
+		},
+	}
+	result.perfdata = []string{
+		perf("jobs_queued", float64(queued), "", float64(flags.warningJobs), float64(flags.criticalJobs)),
+		perf("jobs_running", float64(running), "", 0, 0),
+		perf("jobs_failed", float64(failed), "", 0, 0),
+	}
+	return result
+}
+
+// probeAll runs probe against every node concurrently and returns the
+// failures by node name
+func probeAll(names []string, probe func(name string) error) map[string]error {
+	var mu sync.Mutex
+	failed := map[string]error{}
+	parallel.ForEach(len(names), parallel.DefaultWorkers, func(i int) error {
+		err := probe(names[i])
+		if err != nil {
+			mu.Lock()
+			failed[names[i]] = err
+			mu.Unlock()
+		}
+		return err
+	})
+	return failed
+}
+
+// nodesResult reports remote nodes: WARNING when some fail, CRITICAL when all do
+func nodesResult(metric, probe string, names []string, failed map[string]error) *checkResult {
+	result := &checkResult{
+		state:   StateOK,
+		Message: fmt.Sprintf("%d/%d answered %s", len(names)-len(failed), len(names), probe),
+		Metrics: map[string]float64{metric + "_failed": float64(len(failed))},
+	}
+	result.perfdata = []string{perf(metric+"_failed", float64(len(failed)), "", 0, 0)}
+
+	if len(failed) == 0 {
+		return result
+	}
+	result.state = StateWarning
+	if len(failed) == len(names) {
+		result.state = StateCritical
+	}
+
+	failedNames := make([]string, 0, len(failed))
+	for name := range failed {
+		failedNames = append(failedNames, name)
+	}
+	sort.Strings(failedNames)
+	for i, name := range failedNames {
+		failedNames[i] = fmt.Sprintf("%s (%v)", name, failed[name])
+	}
+	result.Message += ", failed: " + strings.Join(failedNames, ", ")
+	return result
+}
+
+// thresholdState returns the state of a value against warning and critical thresholds
+func thresholdState(value, warning, critical float64) int {
+	switch {
+	case value > critical:
+		return StateCritical
+	case value > warning:
+		return StateWarning
+	}
+	return StateOK
+}
+
+// perf formats a performance data value, with thresholds if they are not zero
+func perf(label string, value float64, unit string, warning, critical float64) string {
+	data := label + "=" + strconv.FormatFloat(value, 'f', -1, 64) + unit
+	if warning != 0 || critical != 0 {
+		data += ";" + strconv.FormatFloat(warning, 'f', -1, 64) + ";" + strconv.FormatFloat(critical, 'f', -1, 64)
+	}
+	return data
+}
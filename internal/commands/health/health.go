@@ -0,0 +1,365 @@
+package health
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/proencaj/orthanc-cli/internal/client"
+	"github.com/proencaj/orthanc-cli/internal/clierr"
+	"github.com/proencaj/orthanc-cli/internal/config"
+	"github.com/spf13/cobra"
+)
+
+// clientGetter is a function type that returns an Orthanc client
+var clientGetter func() (*client.Client, error)
+
+// SetClientGetter sets the function to get the Orthanc client
+func SetClientGetter(getter func() (*client.Client, error)) {
+	clientGetter = getter
+}
+
+// getClient returns the Orthanc client using the configured getter
+func getClient() (*client.Client, error) {
+	if clientGetter != nil {
+		return clientGetter()
+	}
+	// Fallback: try to load config from default location
+	cfg, err := config.LoadConfig("")
+	if err != nil {
+		return nil, err
+	}
+	return client.NewClient(cfg)
+}
+
+// shouldUseJSON checks if JSON output is enabled in config
+func shouldUseJSON() bool {
+	cfg, err := config.LoadConfig("")
+	if err != nil {
+		return false
+	}
+	return cfg.Output.JSON
+}
+
+// Monitoring plugin states, which are also the exit codes of the command
+const (
+	StateOK       = 0
+	StateWarning  = 1
+	StateCritical = 2
+	StateUnknown  = 3
+)
+
+// stateNames are the names of the states in the output
+var stateNames = map[int]string{
+	StateOK:       "OK",
+	StateWarning:  "WARNING",
+	StateCritical: "CRITICAL",
+	StateUnknown:  "UNKNOWN",
+}
+
+// severity orders states from best to worst: a failed check is worse than
+// one that could not be evaluated, which is worse than a warning
+var severity = map[int]int{
+	StateOK:       0,
+	StateWarning:  1,
+	StateUnknown:  2,
+	StateCritical: 3,
+}
+
+// HealthFlags holds the flags for the health command
+type HealthFlags struct {
+	checks          []string
+	warningTime     time.Duration
+	criticalTime    time.Duration
+	warningStorage  float64
+	criticalStorage float64
+	warningJobs     int
+	criticalJobs    int
+	timeout         time.Duration
+	jsonOutput      bool
+}
+
+// checkResult is the outcome of one check
+type checkResult struct {
+	Name        string             `json:"Name"`
+	Status      string             `json:"Status"`
+	Message     string             `json:"Message"`
+	Duration    float64            `json:"DurationSeconds"`
+	Metrics     map[string]float64 `json:"Metrics,omitempty"`
+	state       int
+	perfdata    []string
+	unreachable bool
+}
+
+// healthReport is the JSON output of the health command
+type healthReport struct {
+	Status   string         `json:"Status"`
+	ExitCode int            `json:"ExitCode"`
+	Context  string         `json:"Context"`
+	URL      string         `json:"URL"`
+	Checks   []*checkResult `json:"Checks"`
+}
+
+// NewHealthCommand creates the health command
+func NewHealthCommand() *cobra.Command {
+	flags := &HealthFlags{}
+
+	command := &cobra.Command{
+		Use:   "health",
+		Short: "Check the health of the Orthanc server for monitoring",
+		Long: `Check the health of the Orthanc server and the remote nodes it talks to:
+
+  system      connectivity, credentials and response time of /system
+  database    /statistics, and storage usage against the server's limits
+  modalities  C-ECHO to each configured DICOM modality
+  dicomweb    a QIDO-RS query through each configured DICOMweb server
+  jobs        depth of the job queue
+
+The output follows the Nagios/Icinga plugin conventions: a status line with
+performance data, then one line per check, and the exit code is the overall
+state: 0 OK, 1 WARNING, 2 CRITICAL, 3 UNKNOWN. Checks still running when
+--timeout expires are CRITICAL.
+
+When some modalities or DICOMweb servers fail, the check is WARNING; when all
+of them fail, it is CRITICAL.`,
+		Example: `  # Run all checks
+  orthanc health
+
+  # Only check the server and its job queue, with custom thresholds
+  orthanc health --checks system,jobs --warning-jobs 20 --critical-jobs 100
+
+  # Use as an Icinga/Nagios probe against a given context
+  orthanc --config /etc/orthanc-cli.yaml health --timeout 10s
+
+  # Output in JSON format
+  orthanc health --json`,
+		// Invalid usage and configuration errors are UNKNOWN for monitoring
+		// systems, not a failed check
+		Args: func(c *cobra.Command, args []string) error {
+			if err := cobra.NoArgs(c, args); err != nil {
+				return reportUnknown(err, flags.jsonOutput || shouldUseJSON())
+			}
+			return nil
+		},
+		PersistentPreRunE: func(c *cobra.Command, args []string) error {
+			if err := c.Root().PersistentPreRunE(c, args); err != nil {
+				return reportUnknown(err, flags.jsonOutput || shouldUseJSON())
+			}
+			return nil
+		},
+		RunE: func(c *cobra.Command, args []string) error {
+			return runHealth(flags)
+		},
+	}
+	command.SetFlagErrorFunc(func(c *cobra.Command, err error) error {
+		return reportUnknown(err, flags.jsonOutput || shouldUseJSON())
+	})
+
+	// Add flags
+	command.Flags().StringSliceVar(&flags.checks, "checks", checkNames, "Checks to run (comma-separated): "+strings.Join(checkNames, ", "))
+	command.Flags().DurationVar(&flags.warningTime, "warning-time", time.Second, "Response time of /system above which the state is WARNING")
+	command.Flags().DurationVar(&flags.criticalTime, "critical-time", 5*time.Second, "Response time of /system above which the state is CRITICAL")
+	command.Flags().Float64Var(&flags.warningStorage, "warning-storage", 80, "Percentage of the server's storage limit above which the state is WARNING")
+	command.Flags().Float64Var(&flags.criticalStorage, "critical-storage", 90, "Percentage of the server's storage limit above which the state is CRITICAL")
+	command.Flags().IntVar(&flags.warningJobs, "warning-jobs", 10, "Number of queued jobs above which the state is WARNING")
+	command.Flags().IntVar(&flags.criticalJobs, "critical-jobs", 50, "Number of queued jobs above which the state is CRITICAL")
+	command.Flags().DurationVar(&flags.timeout, "timeout", 30*time.Second, "Time allowed for all checks")
+	command.Flags().BoolVar(&flags.jsonOutput, "json", false, "Output in JSON format")
+
+	return command
+}
+
+func runHealth(flags *HealthFlags) error {
+	// Check if JSON output should be used (flag or config)
+	jsonOutput := flags.jsonOutput || shouldUseJSON()
+
+	// Invalid usage is UNKNOWN for monitoring systems, not a failed check
+	if err := validateFlags(flags); err != nil {
+		return reportUnknown(err, jsonOutput)
+	}
+
+	// Get the Orthanc client
+	client, err := getClient()
+	if err != nil {
+		return reportUnknown(fmt.Errorf("failed to create client: %w", err), jsonOutput)
+	}
+
+	results := runChecks(client, flags)
+
+	state := StateOK
+	for _, result := range results {
+		result.Status = stateNames[result.state]
+		if severity[result.state] > severity[state] {
+			state = result.state
+		}
+	}
+
+	if jsonOutput {
+		report := &healthReport{
+			Status:   stateNames[state],
+			ExitCode: state,
+			Context:  client.ContextName(),
+			URL:      client.URL(),
+			Checks:   results,
+		}
+		data, err := json.MarshalIndent(report, "", "  ")
+		if err != nil {
+			return fmt.Errorf("failed to marshal JSON: %w", err)
+		}
+		fmt.Println(string(data))
+		return clierr.ExitStatus(state)
+	}
+
+	printReport(state, results)
+	return clierr.ExitStatus(state)
+}
+
+// validateFlags checks the selected checks and the consistency of thresholds
+func validateFlags(flags *HealthFlags) error {
+	if len(flags.checks) == 0 {
+		return clierr.Validation("--checks requires at least one check")
+	}
+	for _, name := range flags.checks {
+		if _, ok := checks[name]; !ok {
+			return clierr.Validation("invalid check '%s', must be one of: %s", name, strings.Join(checkNames, ", "))
+		}
+	}
+	if flags.warningTime <= 0 || flags.criticalTime < flags.warningTime {
+		return clierr.Validation("--warning-time must be positive and not above --critical-time")
+	}
+	if flags.warningStorage <= 0 || flags.criticalStorage < flags.warningStorage || flags.criticalStorage > 100 {
+		return clierr.Validation("--warning-storage and --critical-storage must be percentages, warning not above critical")
+	}
+	if flags.warningJobs < 0 || flags.criticalJobs < flags.warningJobs {
+		return clierr.Validation("--warning-jobs must not be negative nor above --critical-jobs")
+	}
+	if flags.timeout <= 0 {
+		return clierr.Validation("--timeout must be positive")
+	}
+	return nil
+}
+
+// runChecks runs the system check first, since the others need the server to
+// answer, then the remaining checks concurrently until the timeout expires
+func runChecks(c *client.Client, flags *HealthFlags) []*checkResult {
+	deadline := time.Now().Add(flags.timeout)
+
+	var names []string
+	selected := map[string]bool{}
+	for _, name := range flags.checks {
+		selected[name] = true
+	}
+	for _, name := range checkNames {
+		if selected[name] && name != "system" {
+			names = append(names, name)
+		}
+	}
+
+	var results []*checkResult
+	if selected["system"] {
+		system := runWithDeadline(c, flags, []string{"system"}, deadline)[0]
+		results = append(results, system)
+		if system.unreachable {
+			for _, name := range names {
+				results = append(results, &checkResult{Name: name, state: StateUnknown, Message: "not run, the server did not answer"})
+			}
+			return results
+		}
+	}
+
+	return append(results, runWithDeadline(c, flags, names, deadline)...)
+}
+
+// runWithDeadline runs checks concurrently, reporting those still running at
+// the deadline as CRITICAL
+func runWithDeadline(c *client.Client, flags *HealthFlags, names []string, deadline time.Time) []*checkResult {
+	type finished struct {
+		index  int
+		result *checkResult
+	}
+
+	done := make(chan finished, len(names))
+	for i, name := range names {
+		go func(i int, name string) {
+			start := time.Now()
+			result := checks[name](c, flags)
+			result.Name = name
+			result.Duration = time.Since(start).Seconds()
+			done <- finished{index: i, result: result}
+		}(i, name)
+	}
+
+	results := make([]*checkResult, len(names))
+	timer := time.NewTimer(time.Until(deadline))
+	defer timer.Stop()
+	for remaining := len(names); remaining > 0; remaining-- {
+		select {
+		case f := <-done:
+			results[f.index] = f.result
+		case <-timer.C:
+			for i, name := range names {
+				if results[i] == nil {
+					results[i] = &checkResult{Name: name, state: StateCritical, Message: fmt.Sprintf("timed out after %s", flags.timeout), unreachable: true}
+				}
+			}
+			return results
+		}
+	}
+	return results
+}
+
+// printReport prints the plugin output: the status line with performance
+// data, then one line per check
+func printReport(state int, results []*checkResult) {
+	// The status line names the checks responsible for the overall state
+	var problems, perfdata []string
+	passed := 0
+	for _, result := range results {
+		if result.state == StateOK {
+			passed++
+		} else if result.state == state {
+			problems = append(problems, result.Name+": "+result.Message)
+		}
+		perfdata = append(perfdata, result.perfdata...)
+	}
+
+	summary := fmt.Sprintf("%d of %d checks passed", passed, len(results))
+	if len(problems) > 0 {
+		summary = strings.Join(problems, "; ")
+	}
+
+	line := fmt.Sprintf("ORTHANC %s - %s", stateNames[state], pluginText(summary))
+	if len(perfdata) > 0 {
+		line += " | " + strings.Join(perfdata, " ")
+	}
+	fmt.Println(line)
+
+	for _, result := range results {
+		fmt.Printf("[%s] %s: %s\n", result.Status, result.Name, pluginText(result.Message))
+	}
+}
+
+// pluginText keeps a message on one line and out of the performance data,
+// which monitoring systems split from the text at the first '|'
+func pluginText(message string) string {
+	return strings.NewReplacer("|", "/", "\n", " ", "\r", "").Replace(message)
+}
+
+// reportUnknown prints an UNKNOWN result for errors preventing the checks from running
+func reportUnknown(err error, jsonOutput bool) error {
+	if jsonOutput {
+		data, marshalErr := json.MarshalIndent(map[string]interface{}{
+			"Status":   stateNames[StateUnknown],
+			"ExitCode": StateUnknown,
+			"Message":  err.Error(),
+		}, "", "  ")
+		if marshalErr != nil {
+			return fmt.Errorf("failed to marshal JSON: %w", marshalErr)
+		}
+		fmt.Println(string(data))
+	} else {
+		fmt.Printf("ORTHANC %s - %s\n", stateNames[StateUnknown], pluginText(err.Error()))
+	}
+	return clierr.ExitStatus(StateUnknown)
+}
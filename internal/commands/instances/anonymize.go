@@ -7,7 +7,12 @@ import (
 	"path/filepath"
 
 	"github.com/proencaj/gorthanc/types"
+	"github.com/proencaj/orthanc-cli/internal/batch"
+	"github.com/proencaj/orthanc-cli/internal/client"
+	"github.com/proencaj/orthanc-cli/internal/clierr"
+	"github.com/proencaj/orthanc-cli/internal/guard"
 	"github.com/proencaj/orthanc-cli/internal/helpers"
+	"github.com/proencaj/orthanc-cli/internal/parallel"
 	"github.com/spf13/cobra"
 )
 
@@ -17,6 +22,7 @@ type AnonymizeFlags struct {
 	keepSource bool
 	permissive bool
 	output     string
+	parallel   int
 }
 
 // NewAnonymizeCommand creates the instances anonymize command
@@ -24,7 +30,7 @@ func NewAnonymizeCommand() *cobra.Command {
 	flags := &AnonymizeFlags{}
 
 	command := &cobra.Command{
-		Use:   "anonymize <instance-id>",
+		Use:   "anonymize <instance-id>...",
 		Short: "Anonymize an instance and download the anonymized DICOM file",
 		Long:  `Anonymize an instance and download the resulting anonymized DICOM file to disk.`,
 		Example: `  # Anonymize an instance and save to current directory
@@ -40,10 +46,14 @@ func NewAnonymizeCommand() *cobra.Command {
   orthanc instances anonymize abc123 --force
 
   # Anonymize with permissive mode (ignore individual step errors)
-  orthanc instances anonymize abc123 --permissive`,
-		Args: cobra.ExactArgs(1),
+  orthanc instances anonymize abc123 --permissive
+
+  # Anonymize several instances into a directory
+  orthanc instances anonymize abc123 def456 --output anonymized/`,
+		Annotations: map[string]string{guard.Annotation: guard.Mutating},
+		Args:        cobra.MinimumNArgs(1),
 		RunE: func(c *cobra.Command, args []string) error {
-			return runAnonymize(args[0], flags)
+			return runAnonymize(args, flags)
 		},
 	}
 
@@ -51,12 +61,19 @@ func NewAnonymizeCommand() *cobra.Command {
 	command.Flags().BoolVar(&flags.force, "force", false, "Force operation even if it would create an invalid DICOM file")
 	command.Flags().BoolVar(&flags.keepSource, "keep-source", true, "Keep the source instance after anonymization")
 	command.Flags().BoolVar(&flags.permissive, "permissive", false, "Ignore errors during individual steps of the job")
-	command.Flags().StringVarP(&flags.output, "output", "o", "", "Output path (file or directory, defaults to current directory)")
+	command.Flags().StringVarP(&flags.output, "output", "o", "", "Output path (file or directory, defaults to current directory; a directory for several instances)")
+	command.Flags().IntVar(&flags.parallel, "parallel", parallel.DefaultWorkers, "Number of instances anonymized in parallel")
 
 	return command
 }
 
-func runAnonymize(instanceID string, flags *AnonymizeFlags) error {
+func runAnonymize(args []string, flags *AnonymizeFlags) error {
+	instanceIDs, err := batch.ReadIDs(args)
+	if err != nil {
+		return err
+	}
+	output := batch.OutputDir(flags.output, len(instanceIDs))
+
 	// Get the Orthanc client
 	client, err := getClient()
 	if err != nil {
@@ -66,43 +83,64 @@ func runAnonymize(instanceID string, flags *AnonymizeFlags) error {
 	// Prepare the anonymize request
 	request := buildAnonymizeRequest(flags)
 
+	// Anonymize in parallel and report the saved files in order
+	outputPaths := make([]string, len(instanceIDs))
+	sizes := make([]int64, len(instanceIDs))
+	return batch.Run(instanceIDs, flags.parallel, func(i int) error {
+		// Resolve DICOM UIDs and prefixed identifiers to the Orthanc ID
+		instanceID, err := client.ResolveID(types.ResourceLevelInstance, instanceIDs[i])
+		if err != nil {
+			return err
+		}
+
+		if len(instanceIDs) == 1 {
+			fmt.Printf("Anonymizing instance: %s\n", instanceID)
+		}
+		outputPaths[i], sizes[i], err = anonymizeInstance(client, instanceID, request, output)
+		return err
+	}, func(i int) error {
+		fmt.Println("Instance anonymized successfully!")
+		fmt.Printf("Anonymized DICOM file saved to: %s\n", outputPaths[i])
+		fmt.Printf("Size: %.2f MB\n", float64(sizes[i])/(1024*1024))
+		return nil
+	})
+}
+
+// anonymizeInstance anonymizes an instance and saves the anonymized file,
+// returning its path and size
+func anonymizeInstance(c *client.Client, instanceID string, request *types.InstancesAnonymizeRequest, output string) (string, int64, error) {
 	// Call the anonymize method
-	fmt.Printf("Anonymizing instance: %s\n", instanceID)
-	resp, err := client.AnonymizeInstance(instanceID, request)
+	resp, err := c.AnonymizeInstance(instanceID, request)
 	if err != nil {
-		return fmt.Errorf("failed to anonymize instance: %w", err)
+		return "", 0, fmt.Errorf("failed to anonymize instance: %w", err)
 	}
 	defer resp.Body.Close()
 
 	// Check response status
 	if resp.StatusCode != 200 {
-		return fmt.Errorf("failed to anonymize instance: HTTP %d", resp.StatusCode)
+		return "", 0, clierr.HTTPStatus(resp.StatusCode, "failed to anonymize instance")
 	}
 
 	// Determine the output path
-	outputPath, err := determineAnonymizeOutputPath(flags.output, instanceID)
+	outputPath, err := determineAnonymizeOutputPath(output, instanceID)
 	if err != nil {
-		return fmt.Errorf("failed to determine output path: %w", err)
+		return "", 0, fmt.Errorf("failed to determine output path: %w", err)
 	}
 
 	// Create the output file
 	outFile, err := os.Create(outputPath)
 	if err != nil {
-		return fmt.Errorf("failed to create output file: %w", err)
+		return "", 0, fmt.Errorf("failed to create output file: %w", err)
 	}
 	defer outFile.Close()
 
 	// Copy the response body to the file
 	written, err := io.Copy(outFile, resp.Body)
 	if err != nil {
-		return fmt.Errorf("failed to write anonymized DICOM file: %w", err)
+		return "", 0, fmt.Errorf("failed to write anonymized DICOM file: %w", err)
 	}
 
-	fmt.Println("Instance anonymized successfully!")
-	fmt.Printf("Anonymized DICOM file saved to: %s\n", outputPath)
-	fmt.Printf("Size: %.2f MB\n", float64(written)/(1024*1024))
-
-	return nil
+	return outputPath, written, nil
 }
 
 // buildAnonymizeRequest creates a properly formatted anonymize request
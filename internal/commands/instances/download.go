@@ -3,15 +3,23 @@ package instances
 import (
 	"fmt"
 	"io"
+	"net/http"
 	"os"
 	"path/filepath"
 
+	"github.com/proencaj/gorthanc/types"
+	"github.com/proencaj/orthanc-cli/internal/batch"
+	"github.com/proencaj/orthanc-cli/internal/download"
+	"github.com/proencaj/orthanc-cli/internal/parallel"
 	"github.com/spf13/cobra"
 )
 
 // DownloadFlags holds the flags for the download command
 type DownloadFlags struct {
-	output string
+	output    string
+	transcode string
+	retries   int
+	parallel  int
 }
 
 // NewDownloadCommand creates the instances download command
@@ -19,7 +27,7 @@ func NewDownloadCommand() *cobra.Command {
 	flags := &DownloadFlags{}
 
 	command := &cobra.Command{
-		Use:   "download <instance-id>",
+		Use:   "download <instance-id>...",
 		Short: "Download a DICOM instance file from the Orthanc server",
 		Long:  `Download a DICOM instance file from the Orthanc server and save it to disk.`,
 		Example: `  # Download an instance to current directory
@@ -29,62 +37,95 @@ func NewDownloadCommand() *cobra.Command {
   orthanc instances download abc123 --output /path/to/instance.dcm
 
   # Download an instance to a specific directory
-  orthanc instances download abc123 --output /path/to/directory/`,
-		Args: cobra.ExactArgs(1),
+  orthanc instances download abc123 --output /path/to/directory/
+
+  # Transcode to Explicit VR Little Endian for viewers without JPEG2000 support
+  orthanc instances download abc123 --transcode explicit-little
+
+  # Download all instances of a series into a directory
+  orthanc series list-instances def456 | orthanc instances download - --output series/`,
+		Args: cobra.MinimumNArgs(1),
 		RunE: func(c *cobra.Command, args []string) error {
-			return runDownload(args[0], flags)
+			return runDownload(args, flags)
 		},
 	}
 
 	// Add flags
-	command.Flags().StringVarP(&flags.output, "output", "o", "", "Output path (file or directory, defaults to current directory)")
+	command.Flags().StringVarP(&flags.output, "output", "o", "", "Output path (file or directory, defaults to current directory; a directory for several instances)")
+	command.Flags().IntVar(&flags.retries, "retries", download.DefaultRetries, "Number of retries if the download is interrupted")
+	command.Flags().IntVar(&flags.parallel, "parallel", parallel.DefaultWorkers, "Number of files downloaded in parallel")
+	command.Flags().StringVar(&flags.transcode, "transcode", "", "Transcode to a transfer syntax UID or alias (e.g. explicit-little, jpeg2000-lossless, jpeg-baseline)")
 
 	return command
 }
 
-func runDownload(instanceID string, flags *DownloadFlags) error {
-	// Get the Orthanc client
-	client, err := getClient()
+func runDownload(args []string, flags *DownloadFlags) error {
+	instanceIDs, err := batch.ReadIDs(args)
 	if err != nil {
-		return fmt.Errorf("failed to create client: %w", err)
+		return err
 	}
+	output := batch.OutputDir(flags.output, len(instanceIDs))
 
-	// Download the DICOM file
-	fmt.Printf("Downloading DICOM instance: %s\n", instanceID)
-	resp, err := client.DownloadDicomFile(instanceID)
+	// Get the Orthanc client
+	client, err := getClient()
 	if err != nil {
-		return fmt.Errorf("failed to download DICOM file: %w", err)
+		return fmt.Errorf("failed to create client: %w", err)
 	}
-	defer resp.Body.Close()
 
-	// Check response status
-	if resp.StatusCode != 200 {
-		return fmt.Errorf("failed to download DICOM file: HTTP %d", resp.StatusCode)
+	// Resolve the transfer syntax alias to its UID
+	transferSyntax := ""
+	if flags.transcode != "" {
+		transferSyntax, err = client.ResolveTransferSyntax(flags.transcode)
+		if err != nil {
+			return err
+		}
 	}
 
-	// Determine the output path
-	outputPath, err := determineOutputPath(flags.output, instanceID)
-	if err != nil {
-		return fmt.Errorf("failed to determine output path: %w", err)
+	// Progress bars of parallel downloads would overwrite each other
+	var progress io.Writer
+	if len(instanceIDs) > 1 {
+		progress = io.Discard
 	}
 
-	// Create the output file
-	outFile, err := os.Create(outputPath)
-	if err != nil {
-		return fmt.Errorf("failed to create output file: %w", err)
-	}
-	defer outFile.Close()
+	// Download the files in parallel and report them in order
+	results := make([]*download.Result, len(instanceIDs))
+	return batch.Run(instanceIDs, flags.parallel, func(i int) error {
+		// Resolve DICOM UIDs and prefixed identifiers to the Orthanc ID
+		instanceID, err := client.ResolveID(types.ResourceLevelInstance, instanceIDs[i])
+		if err != nil {
+			return err
+		}
 
-	// Copy the response body to the file
-	written, err := io.Copy(outFile, resp.Body)
-	if err != nil {
-		return fmt.Errorf("failed to write DICOM file: %w", err)
-	}
+		// Determine the output path
+		outputPath, err := determineOutputPath(output, instanceID)
+		if err != nil {
+			return fmt.Errorf("failed to determine output path: %w", err)
+		}
 
-	fmt.Printf("Successfully downloaded DICOM file to: %s\n", outputPath)
-	fmt.Printf("Size: %.2f MB\n", float64(written)/(1024*1024))
+		filePath, err := client.TranscodedPath("instances/"+instanceID+"/file", transferSyntax)
+		if err != nil {
+			return err
+		}
 
-	return nil
+		// Download the DICOM file
+		if len(instanceIDs) == 1 {
+			fmt.Printf("Downloading DICOM instance: %s\n", instanceID)
+		}
+		results[i], err = download.ToFile(outputPath, func(offset int64) (*http.Response, error) {
+			return client.Download(filePath, offset)
+		}, download.Options{
+			Retries:  flags.retries,
+			Progress: progress,
+		})
+		if err != nil {
+			return fmt.Errorf("failed to download DICOM file: %w", err)
+		}
+		return nil
+	}, func(i int) error {
+		fmt.Printf("Successfully downloaded DICOM file to: %s\n", results[i].Path)
+		fmt.Printf("Size: %.2f MB\n", float64(results[i].Size)/(1024*1024))
+		return nil
+	})
 }
 
 // determineOutputPath determines the final output path for the DICOM file
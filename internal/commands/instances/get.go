@@ -4,11 +4,15 @@ import (
 	"encoding/json"
 	"fmt"
 
+	"github.com/proencaj/gorthanc/types"
+	"github.com/proencaj/orthanc-cli/internal/batch"
+	"github.com/proencaj/orthanc-cli/internal/parallel"
 	"github.com/spf13/cobra"
 )
 
 // GetFlags holds the flags for the get command
 type GetFlags struct {
+	parallel   int
 	jsonOutput bool
 }
 
@@ -17,27 +21,36 @@ func NewGetCommand() *cobra.Command {
 	flags := &GetFlags{}
 
 	command := &cobra.Command{
-		Use:   "get <instance-id>",
+		Use:   "get <instance-id>...",
 		Short: "Get detailed information about an instance",
 		Long:  `Retrieve and display detailed information about a specific instance from the Orthanc server.`,
 		Example: `  # Get instance details
   orthanc instances get abc123
 
   # Get instance details in JSON format
-  orthanc instances get abc123 --json`,
-		Args: cobra.ExactArgs(1),
+  orthanc instances get abc123 --json
+
+  # Get several instances, reading IDs from stdin
+  orthanc instances list | orthanc instances get - --json`,
+		Args: cobra.MinimumNArgs(1),
 		RunE: func(c *cobra.Command, args []string) error {
-			return runGet(args[0], flags)
+			return runGet(args, flags)
 		},
 	}
 
 	// Add flags
+	command.Flags().IntVar(&flags.parallel, "parallel", parallel.DefaultWorkers, "Number of resources fetched in parallel")
 	command.Flags().BoolVar(&flags.jsonOutput, "json", false, "Output in JSON format")
 
 	return command
 }
 
-func runGet(instanceID string, flags *GetFlags) error {
+func runGet(args []string, flags *GetFlags) error {
+	instanceIDs, err := batch.ReadIDs(args)
+	if err != nil {
+		return err
+	}
+
 	// Get the Orthanc client
 	client, err := getClient()
 	if err != nil {
@@ -47,13 +60,26 @@ func runGet(instanceID string, flags *GetFlags) error {
 	// Check if JSON output should be used (flag or config)
 	jsonOutput := flags.jsonOutput || shouldUseJSON()
 
-	// Fetch instance details
-	instance, err := client.GetInstanceDetails(instanceID)
-	if err != nil {
-		return fmt.Errorf("failed to fetch instance details: %w", err)
-	}
+	// Fetch the instances in parallel and display them in order
+	instances := make([]*types.Instance, len(instanceIDs))
+	return batch.Run(instanceIDs, flags.parallel, func(i int) error {
+		// Resolve DICOM UIDs and prefixed identifiers to the Orthanc ID
+		instanceID, err := client.ResolveID(types.ResourceLevelInstance, instanceIDs[i])
+		if err != nil {
+			return err
+		}
+
+		instances[i], err = client.GetInstanceDetails(instanceID)
+		if err != nil {
+			return fmt.Errorf("failed to fetch instance details: %w", err)
+		}
+		return nil
+	}, func(i int) error {
+		return displayInstance(instances[i], jsonOutput)
+	})
+}
 
-	// Display the instance
+func displayInstance(instance *types.Instance, jsonOutput bool) error {
 	if jsonOutput {
 		data, err := json.MarshalIndent(instance, "", "  ")
 		if err != nil {
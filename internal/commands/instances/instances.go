@@ -41,7 +41,15 @@ func NewInstancesCommand() *cobra.Command {
 	instancesCmd := &cobra.Command{
 		Use:   "instances",
 		Short: "Manage Orthanc instances",
-		Long:  `Query, list, and manage DICOM instances in the Orthanc server.`,
+		Long: `Query, list, and manage DICOM instances in the Orthanc server.
+
+Instances can be given by Orthanc ID, by SOPInstanceUID, or as
+uid:<SOPInstanceUID>, acc:<AccessionNumber> or pid:<PatientID>.
+Identifiers that match several resources are rejected as ambiguous.
+
+Commands that take instances also accept several IDs, or - to read them from
+stdin one per line, and process them in parallel (see --parallel). If some of
+them fail, the others are still processed and the exit code is 10.`,
 	}
 
 	// Add subcommands
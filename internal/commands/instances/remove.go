@@ -4,14 +4,22 @@ import (
 	"bufio"
 	"fmt"
 	"os"
+	"slices"
 	"strings"
 
+	"github.com/proencaj/gorthanc/types"
+	"github.com/proencaj/orthanc-cli/internal/batch"
+	"github.com/proencaj/orthanc-cli/internal/clierr"
+	"github.com/proencaj/orthanc-cli/internal/guard"
+	"github.com/proencaj/orthanc-cli/internal/parallel"
+	"github.com/proencaj/orthanc-cli/internal/trash"
 	"github.com/spf13/cobra"
 )
 
 // RemoveFlags holds the flags for the remove command
 type RemoveFlags struct {
-	force bool
+	force    bool
+	parallel int
 }
 
 // NewRemoveCommand creates the instances remove command
@@ -19,36 +27,60 @@ func NewRemoveCommand() *cobra.Command {
 	flags := &RemoveFlags{}
 
 	command := &cobra.Command{
-		Use:   "remove <instance-id>",
+		Use:   "remove <instance-id>...",
 		Short: "Remove an instance from the Orthanc server",
-		Long:  `Delete an instance from the Orthanc server. This operation is irreversible.`,
+		Long: `Delete an instance from the Orthanc server.
+This operation is irreversible unless the trash is enabled.
+
+When the trash setting is enabled for the context (orthanc config set trash
+true), the instance is first downloaded to a local trash, and is only deleted
+once the copy is complete. Use 'orthanc trash restore' to upload it again.`,
 		Example: `  # Remove an instance with confirmation prompt
   orthanc instances remove abc123
 
   # Remove an instance without confirmation
-  orthanc instances remove abc123 --force`,
-		Args: cobra.ExactArgs(1),
+  orthanc instances remove abc123 --force
+
+  # Remove several instances listed in a file, one ID per line
+  orthanc instances remove - --force < instances.txt`,
+		Annotations: map[string]string{
+			guard.Annotation:  guard.Destructive,
+			guard.ConfirmFlag: "force",
+		},
+		Args: cobra.MinimumNArgs(1),
 		RunE: func(c *cobra.Command, args []string) error {
-			return runRemove(args[0], flags)
+			return runRemove(args, flags)
 		},
 	}
 
 	// Add flags
 	command.Flags().BoolVarP(&flags.force, "force", "f", false, "Skip confirmation prompt")
+	command.Flags().IntVar(&flags.parallel, "parallel", parallel.DefaultWorkers, "Number of resources deleted in parallel")
 
 	return command
 }
 
-func runRemove(instanceID string, flags *RemoveFlags) error {
+func runRemove(args []string, flags *RemoveFlags) error {
+	instanceIDs, err := batch.ReadIDs(args)
+	if err != nil {
+		return err
+	}
+	if !flags.force && slices.Contains(args, batch.Stdin) {
+		return clierr.Validation("--force is required when reading IDs from stdin")
+	}
+
 	// Get the Orthanc client
 	client, err := getClient()
 	if err != nil {
 		return fmt.Errorf("failed to create client: %w", err)
 	}
 
-	// If not using force flag, prompt for confirmation
-	if !flags.force {
-		confirmed, err := confirmRemoval(instanceID)
+	// Keep a local copy of each resource before deleting it, if enabled for the context
+	useTrash := client.GetConfig().TrashEnabled()
+
+	// Prompt for confirmation, unless forced or already confirmed for a protected context
+	if !flags.force && !guard.Confirmed() {
+		confirmed, err := confirmRemoval(instanceIDs, useTrash)
 		if err != nil {
 			return fmt.Errorf("failed to get confirmation: %w", err)
 		}
@@ -58,20 +90,54 @@ func runRemove(instanceID string, flags *RemoveFlags) error {
 		}
 	}
 
-	// Delete the instance
-	err = client.DeleteInstance(instanceID)
-	if err != nil {
-		return fmt.Errorf("failed to delete instance: %w", err)
-	}
+	// Delete the instances in parallel
+	deleted := make([]string, len(instanceIDs))
+	trashed := make([]*trash.Entry, len(instanceIDs))
+	return batch.Run(instanceIDs, flags.parallel, func(i int) error {
+		// Resolve DICOM UIDs and prefixed identifiers to the Orthanc ID
+		instanceID, err := client.ResolveID(types.ResourceLevelInstance, instanceIDs[i])
+		if err != nil {
+			return err
+		}
 
-	fmt.Printf("Successfully deleted instance: %s\n", instanceID)
-	return nil
+		if useTrash {
+			trashed[i], err = trash.Save(client, types.ResourceLevelInstance, instanceID)
+			if err != nil {
+				return fmt.Errorf("failed to keep a copy in the trash, instance not deleted: %w", err)
+			}
+		}
+
+		if err := client.DeleteInstance(instanceID); err != nil {
+			// Do not keep a copy of a instance that still exists
+			if trashed[i] != nil {
+				trash.Remove(trashed[i])
+			}
+			return fmt.Errorf("failed to delete instance: %w", err)
+		}
+		deleted[i] = instanceID
+		return nil
+	}, func(i int) error {
+		fmt.Printf("Successfully deleted instance: %s\n", deleted[i])
+		if trashed[i] != nil {
+			fmt.Printf("Kept in trash as: %s\n", trashed[i].ID)
+		}
+		return nil
+	})
 }
 
-func confirmRemoval(instanceID string) (bool, error) {
-	fmt.Printf("\n⚠️  WARNING: You are about to delete instance '%s'\n", instanceID)
-	fmt.Println("This operation is NOT reversible and will permanently remove all associated data.")
-	fmt.Print("\nDo you really want to delete this instance? (yes/no): ")
+func confirmRemoval(instanceIDs []string, useTrash bool) (bool, error) {
+	target, this := fmt.Sprintf("instance '%s'", instanceIDs[0]), "this instance"
+	if len(instanceIDs) > 1 {
+		target, this = fmt.Sprintf("%d instances", len(instanceIDs)), "these instances"
+	}
+
+	fmt.Printf("\n⚠️  WARNING: You are about to delete %s\n", target)
+	if useTrash {
+		fmt.Println("A copy will be kept in the local trash and can be restored with 'orthanc trash restore'.")
+	} else {
+		fmt.Println("This operation is NOT reversible and will permanently remove all associated data.")
+	}
+	fmt.Printf("\nDo you really want to delete %s? (yes/no): ", this)
 
 	reader := bufio.NewReader(os.Stdin)
 	response, err := reader.ReadString('\n')
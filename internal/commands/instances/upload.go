@@ -7,6 +7,8 @@ import (
 	"path/filepath"
 
 	"github.com/proencaj/gorthanc/types"
+	"github.com/proencaj/orthanc-cli/internal/clierr"
+	"github.com/proencaj/orthanc-cli/internal/guard"
 	"github.com/spf13/cobra"
 )
 
@@ -28,7 +30,8 @@ func NewUploadCommand() *cobra.Command {
 
   # Upload a DICOM file with JSON output
   orthanc instances upload /path/to/file.dcm --json`,
-		Args: cobra.ExactArgs(1),
+		Annotations: map[string]string{guard.Annotation: guard.Mutating},
+		Args:        cobra.ExactArgs(1),
 		RunE: func(c *cobra.Command, args []string) error {
 			return runUpload(args[0], flags)
 		},
@@ -54,14 +57,14 @@ func runUpload(filePath string, flags *UploadFlags) error {
 	fileInfo, err := os.Stat(filePath)
 	if err != nil {
 		if os.IsNotExist(err) {
-			return fmt.Errorf("file does not exist: %s", filePath)
+			return clierr.Validation("file does not exist: %s", filePath)
 		}
 		return fmt.Errorf("failed to stat file: %w", err)
 	}
 
 	// Check if it's a file (not a directory)
 	if fileInfo.IsDir() {
-		return fmt.Errorf("path is a directory, not a file: %s", filePath)
+		return clierr.Validation("path is a directory, not a file: %s", filePath)
 	}
 
 	// Open the file
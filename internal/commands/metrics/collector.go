@@ -0,0 +1,228 @@
+package metrics
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/proencaj/orthanc-cli/internal/client"
+	"github.com/proencaj/orthanc-cli/internal/parallel"
+)
+
+// changesPageSize is the number of changes read per request
+const changesPageSize = 1000
+
+// maxChangePages bounds the changes read in one scrape, the rest being read
+// by the next ones
+const maxChangePages = 10
+
+// unknownSource labels studies whose sending modality is not known
+const unknownSource = "unknown"
+
+// jobStates are the job states always exported, even without jobs
+var jobStates = []string{client.JobPending, client.JobRunning, client.JobSuccess, client.JobFailure, client.JobPaused, client.JobRetry}
+
+// collector scrapes one context and keeps the counters derived from its
+// change log between scrapes
+type collector struct {
+	context       string
+	native        bool
+	cursor        int64
+	changes       map[string]float64
+	stableStudies map[string]float64
+	pending       map[string]bool
+}
+
+// newCollector creates the collector of a context
+func newCollector(context string, native bool) *collector {
+	return &collector{
+		context:       context,
+		native:        native,
+		cursor:        -1,
+		changes:       map[string]float64{},
+		stableStudies: map[string]float64{},
+		pending:       map[string]bool{},
+	}
+}
+
+// scrape reads the metrics of the server. Failures are reported on stderr
+// and leave the corresponding metrics out, they never stop the exporter.
+func (col *collector) scrape(c *client.Client) *exposition {
+	start := time.Now()
+	e := newExposition()
+
+	if _, err := c.GetSystem(); err != nil {
+		col.warn("server not reachable: %v", err)
+		e.add("orthanc_cli_up", 0, "context", col.context)
+		e.add("orthanc_cli_scrape_duration_seconds", time.Since(start).Seconds(), "context", col.context)
+		return e
+	}
+	e.add("orthanc_cli_up", 1, "context", col.context)
+
+	aets, err := col.scrapeModalities(c, e)
+	if err != nil {
+		col.warn("failed to echo modalities: %v", err)
+	}
+
+	if jobs, err := c.ListJobs(); err != nil {
+		col.warn("failed to list jobs: %v", err)
+	} else {
+		counts := map[string]float64{}
+		for _, state := range jobStates {
+			counts[state] = 0
+		}
+		for _, job := range jobs {
+			counts[job.State]++
+		}
+		e.addCounts("orthanc_cli_jobs", col.context, "state", counts)
+	}
+
+	if err := col.readChanges(c, aets); err != nil {
+		col.warn("failed to read changes: %v", err)
+	}
+	e.addCounts("orthanc_cli_changes_total", col.context, "type", col.changes)
+	e.addCounts("orthanc_cli_stable_studies_total", col.context, "modality", col.stableStudies)
+	e.add("orthanc_cli_pending_studies", float64(len(col.pending)), "context", col.context)
+
+	if col.native {
+		text, err := nativeMetrics(c)
+		if err != nil {
+			col.warn("failed to read native metrics: %v", err)
+			e.add("orthanc_cli_native_up", 0, "context", col.context)
+		} else {
+			e.addNative(text, col.context)
+			e.add("orthanc_cli_native_up", 1, "context", col.context)
+		}
+	}
+
+	e.add("orthanc_cli_scrape_duration_seconds", time.Since(start).Seconds(), "context", col.context)
+	return e
+}
+
+// scrapeModalities sends a C-ECHO to each modality and returns the modality
+// names by AET, to attribute received studies
+func (col *collector) scrapeModalities(c *client.Client, e *exposition) (map[string]string, error) {
+	names, err := c.GetModalities()
+	if err != nil {
+		return nil, err
+	}
+
+	aets := make([]string, len(names))
+	durations := make([]float64, len(names))
+	errs := parallel.ForEach(len(names), parallel.DefaultWorkers, func(i int) error {
+		if details, err := c.GetModalityDetails(names[i]); err == nil {
+			aets[i] = details.AET
+		}
+		start := time.Now()
+		err := c.EchoModality(names[i])
+		durations[i] = time.Since(start).Seconds()
+		return err
+	})
+
+	byAET := map[string]string{}
+	for i, name := range names {
+		success := 1.0
+		if errs[i] != nil {
+			success = 0
+		}
+		e.add("orthanc_cli_modality_echo_success", success, "context", col.context, "modality", name)
+		e.add("orthanc_cli_modality_echo_duration_seconds", durations[i], "context", col.context, "modality", name)
+		if aets[i] != "" {
+			byAET[aets[i]] = name
+		}
+	}
+	return byAET, nil
+}
+
+// readChanges reads the change log since the previous scrape. The first
+// scrape only records the current position: counters start at zero.
+func (col *collector) readChanges(c *client.Client, aets map[string]string) error {
+	if col.cursor < 0 {
+		last, err := c.GetLastChange()
+		if err != nil {
+			return err
+		}
+		col.cursor = last
+		return nil
+	}
+
+	for page := 0; page < maxChangePages; page++ {
+		list, err := c.GetChanges(col.cursor, changesPageSize)
+		if err != nil {
+			return err
+		}
+
+		for _, change := range list.Changes {
+			col.changes[change.ChangeType]++
+			if change.ResourceType != "Study" {
+				continue
+			}
+			switch change.ChangeType {
+			case client.ChangeNewStudy:
+				col.pending[change.ID] = true
+			case client.ChangeStableStudy:
+				delete(col.pending, change.ID)
+				col.stableStudies[studySource(c, change.ID, aets)]++
+			case client.ChangeDeleted:
+				delete(col.pending, change.ID)
+			}
+		}
+
+		col.cursor = list.Last
+		if list.Done {
+			break
+		}
+	}
+	return nil
+}
+
+// studySource returns the configured modality that sent a study, from the
+// calling AET recorded with its first instance, or the AET itself
+func studySource(c *client.Client, studyID string, aets map[string]string) string {
+	var instances []struct {
+		ID string `json:"ID"`
+	}
+	if err := c.GetJSON("studies/"+url.PathEscape(studyID)+"/instances", &instances); err != nil || len(instances) == 0 {
+		return unknownSource
+	}
+
+	resp, err := c.Do(http.MethodGet, "instances/"+url.PathEscape(instances[0].ID)+"/metadata/RemoteAET", nil, "")
+	if err != nil {
+		return unknownSource
+	}
+	defer resp.Body.Close()
+
+	data, err := io.ReadAll(resp.Body)
+	aet := strings.TrimSpace(string(data))
+	if err != nil || aet == "" {
+		return unknownSource
+	}
+	if name, ok := aets[aet]; ok {
+		return name
+	}
+	return aet
+}
+
+// nativeMetrics returns the metrics Orthanc exports itself
+func nativeMetrics(c *client.Client) (string, error) {
+	resp, err := c.Do(http.MethodGet, "tools/metrics-prometheus", nil, "")
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return "", fmt.Errorf("failed to read response: %w", err)
+	}
+	return string(data), nil
+}
+
+// warn reports a scrape failure on stderr
+func (col *collector) warn(format string, args ...interface{}) {
+	fmt.Fprintf(os.Stderr, "%s Warning: context '%s': %s\n", time.Now().Format(time.RFC3339), col.context, fmt.Sprintf(format, args...))
+}
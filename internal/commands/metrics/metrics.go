@@ -0,0 +1,218 @@
+package metrics
+
+import (
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/proencaj/orthanc-cli/internal/client"
+	"github.com/proencaj/orthanc-cli/internal/config"
+	"github.com/spf13/cobra"
+)
+
+// clientGetter is a function type that returns an Orthanc client
+var clientGetter func() (*client.Client, error)
+
+// SetClientGetter sets the function to get the Orthanc client
+func SetClientGetter(getter func() (*client.Client, error)) {
+	clientGetter = getter
+}
+
+// getClient returns the Orthanc client using the configured getter
+func getClient() (*client.Client, error) {
+	if clientGetter != nil {
+		return clientGetter()
+	}
+	// Fallback: try to load config from default location
+	cfg, err := config.LoadConfig("")
+	if err != nil {
+		return nil, err
+	}
+	return client.NewClient(cfg)
+}
+
+// NewMetricsCommand creates the metrics command with all subcommands
+func NewMetricsCommand() *cobra.Command {
+	metricsCmd := &cobra.Command{
+		Use:   "metrics",
+		Short: "Export metrics of Orthanc servers to Prometheus",
+		Long: `Export metrics of Orthanc servers to Prometheus: metrics derived by the CLI,
+such as C-ECHO latency of modalities, job states and received studies, along
+with the native metrics of Orthanc (/tools/metrics-prometheus).`,
+	}
+
+	// Add subcommands
+	metricsCmd.AddCommand(NewServeCommand())
+
+	return metricsCmd
+}
+
+// metricDef describes a metric exported by the CLI
+type metricDef struct {
+	name string
+	kind string
+	help string
+}
+
+// metricDefs are the metrics exported by the CLI, in the order they are written
+var metricDefs = []metricDef{
+	{"orthanc_cli_up", "gauge", "Whether the Orthanc server answered the last scrape (1) or not (0)."},
+	{"orthanc_cli_scrape_duration_seconds", "gauge", "Duration of the last scrape of the server."},
+	{"orthanc_cli_modality_echo_success", "gauge", "Whether the last C-ECHO to the modality succeeded (1) or not (0)."},
+	{"orthanc_cli_modality_echo_duration_seconds", "gauge", "Duration of the last C-ECHO to the modality."},
+	{"orthanc_cli_jobs", "gauge", "Number of jobs known to the server, by state."},
+	{"orthanc_cli_changes_total", "counter", "Changes read from the change log since the exporter started, by type."},
+	{"orthanc_cli_stable_studies_total", "counter", "Studies that became stable since the exporter started, by modality that sent them."},
+	{"orthanc_cli_pending_studies", "gauge", "Studies received since the exporter started that are not stable yet."},
+	{"orthanc_cli_native_up", "gauge", "Whether the native Orthanc metrics could be read (1) or not (0)."},
+}
+
+// family is a metric family of the exposition, with its samples
+type family struct {
+	help  string
+	kind  string
+	lines []string
+}
+
+// exposition builds a page in the Prometheus text format, keeping the
+// samples of each metric family together as the format requires
+type exposition struct {
+	order    []string
+	families map[string]*family
+}
+
+// newExposition creates an exposition with the families of the CLI metrics
+func newExposition() *exposition {
+	e := &exposition{families: map[string]*family{}}
+	for _, def := range metricDefs {
+		f := e.family(def.name)
+		f.kind, f.help = def.kind, def.help
+	}
+	return e
+}
+
+// family returns the family of a metric, creating it if needed
+func (e *exposition) family(name string) *family {
+	f, ok := e.families[name]
+	if !ok {
+		f = &family{}
+		e.families[name] = f
+		e.order = append(e.order, name)
+	}
+	return f
+}
+
+// add adds a sample of a CLI metric; labels are name and value pairs
+func (e *exposition) add(name string, value float64, labels ...string) {
+	var b strings.Builder
+	b.WriteString(name)
+	if len(labels) > 0 {
+		b.WriteByte('{')
+		for i := 0; i+1 < len(labels); i += 2 {
+			if i > 0 {
+				b.WriteByte(',')
+			}
+			b.WriteString(labels[i] + `="` + escapeLabel(labels[i+1]) + `"`)
+		}
+		b.WriteByte('}')
+	}
+	b.WriteString(" " + strconv.FormatFloat(value, 'g', -1, 64))
+	e.family(name).lines = append(e.family(name).lines, b.String())
+}
+
+// addCounts adds one sample per key of counts, in key order
+func (e *exposition) addCounts(name, context, label string, counts map[string]float64) {
+	keys := make([]string, 0, len(counts))
+	for key := range counts {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		e.add(name, counts[key], "context", context, label, key)
+	}
+}
+
+// addNative adds the native metrics of a server, with a context label
+// added to each sample so that several servers can be exported together
+func (e *exposition) addNative(text, context string) {
+	for _, line := range strings.Split(text, "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" {
+			continue
+		}
+
+		if strings.HasPrefix(line, "#") {
+			fields := strings.SplitN(line, " ", 4)
+			if len(fields) < 4 {
+				continue
+			}
+			f := e.family(fields[2])
+			switch {
+			case fields[1] == "HELP" && f.help == "":
+				f.help = fields[3]
+			case fields[1] == "TYPE" && f.kind == "":
+				f.kind = fields[3]
+			}
+			continue
+		}
+
+		end := strings.IndexAny(line, "{ ")
+		if end < 0 {
+			continue
+		}
+		name, rest := line[:end], line[end:]
+		label := `context="` + escapeLabel(context) + `"`
+		switch {
+		case strings.HasPrefix(rest, "{}"):
+			rest = "{" + label + rest[1:]
+		case strings.HasPrefix(rest, "{"):
+			rest = "{" + label + "," + rest[1:]
+		default:
+			rest = "{" + label + "}" + rest
+		}
+		e.family(name).lines = append(e.family(name).lines, name+rest)
+	}
+}
+
+// merge appends the samples of other, family by family
+func (e *exposition) merge(other *exposition) {
+	for _, name := range other.order {
+		src, dst := other.families[name], e.family(name)
+		if dst.help == "" {
+			dst.help = src.help
+		}
+		if dst.kind == "" {
+			dst.kind = src.kind
+		}
+		dst.lines = append(dst.lines, src.lines...)
+	}
+}
+
+// WriteTo writes the exposition in the Prometheus text format, leaving out
+// families without samples
+func (e *exposition) WriteTo(w io.Writer) (int64, error) {
+	var b strings.Builder
+	for _, name := range e.order {
+		f := e.families[name]
+		if len(f.lines) == 0 {
+			continue
+		}
+		if f.help != "" {
+			b.WriteString("# HELP " + name + " " + f.help + "\n")
+		}
+		if f.kind != "" {
+			b.WriteString("# TYPE " + name + " " + f.kind + "\n")
+		}
+		for _, line := range f.lines {
+			b.WriteString(line + "\n")
+		}
+	}
+	n, err := io.WriteString(w, b.String())
+	return int64(n), err
+}
+
+// escapeLabel escapes a label value for the Prometheus text format
+func escapeLabel(value string) string {
+	return strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`).Replace(value)
+}
@@ -0,0 +1,217 @@
+package metrics
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"os"
+	"os/signal"
+	"sync"
+	"syscall"
+	"time"
+
+	"github.com/proencaj/orthanc-cli/internal/client"
+	"github.com/proencaj/orthanc-cli/internal/clierr"
+	"github.com/proencaj/orthanc-cli/internal/fanout"
+	"github.com/spf13/cobra"
+)
+
+// ServeFlags holds the flags for the serve command
+type ServeFlags struct {
+	listen   string
+	interval time.Duration
+	native   bool
+	fanout   fanout.Flags
+}
+
+// NewServeCommand creates the metrics serve command
+func NewServeCommand() *cobra.Command {
+	flags := &ServeFlags{}
+
+	command := &cobra.Command{
+		Use:   "serve",
+		Short: "Serve metrics in the Prometheus text format",
+		Long: `Scrape the Orthanc server of the current context (or of several contexts) on
+an interval and serve the metrics on /metrics in the Prometheus text format.
+
+Metrics derived by the CLI, labelled with the context:
+
+  orthanc_cli_up                               server answered the last scrape
+  orthanc_cli_scrape_duration_seconds          duration of the last scrape
+  orthanc_cli_modality_echo_success            last C-ECHO to each modality succeeded
+  orthanc_cli_modality_echo_duration_seconds   C-ECHO latency of each modality
+  orthanc_cli_jobs                             jobs by state
+  orthanc_cli_changes_total                    changes of the change log, by type
+  orthanc_cli_stable_studies_total             studies that became stable, by sending modality
+  orthanc_cli_pending_studies                  studies received but not stable yet
+
+Counters derived from the change log start when the exporter starts. Studies
+are attributed to the configured modality whose AET sent them, or to the AET
+itself.
+
+The native metrics of Orthanc (/tools/metrics-prometheus) are passed through
+with a context label added; use --native=false to leave them out.`,
+		Example: `  # Export the metrics of the current context on port 9108
+  orthanc metrics serve --listen :9108
+
+  # Export the metrics of several servers every minute
+  orthanc metrics serve --contexts production,research --interval 1m
+
+  # Only the metrics derived by the CLI
+  orthanc metrics serve --all-contexts --native=false`,
+		Args: cobra.NoArgs,
+		RunE: func(c *cobra.Command, args []string) error {
+			return runServe(flags)
+		},
+	}
+
+	// Add flags
+	command.Flags().StringVar(&flags.listen, "listen", ":9108", "Address to serve the metrics on")
+	command.Flags().DurationVar(&flags.interval, "interval", 30*time.Second, "Interval between scrapes of the servers")
+	command.Flags().BoolVar(&flags.native, "native", true, "Pass the native Orthanc metrics through")
+	fanout.AddFlags(command, &flags.fanout)
+
+	return command
+}
+
+// exporter scrapes the servers and holds the latest metrics page
+type exporter struct {
+	flags      *ServeFlags
+	mu         sync.Mutex
+	collectors map[string]*collector
+	page       []byte
+}
+
+func runServe(flags *ServeFlags) error {
+	if flags.interval < time.Second {
+		return clierr.Validation("--interval must be at least 1s")
+	}
+
+	listener, err := net.Listen("tcp", flags.listen)
+	if err != nil {
+		return fmt.Errorf("failed to listen on %s: %w", flags.listen, err)
+	}
+	defer listener.Close()
+
+	// A first scrape reports configuration errors before serving
+	e := &exporter{flags: flags, collectors: map[string]*collector{}}
+	if err := e.scrape(); err != nil {
+		return err
+	}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/metrics", e.serveMetrics)
+	mux.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/" {
+			http.NotFound(w, r)
+			return
+		}
+		fmt.Fprintln(w, "Orthanc CLI metrics exporter, see /metrics")
+	})
+	server := &http.Server{Handler: mux, ReadHeaderTimeout: 10 * time.Second}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	go e.scrapeEvery(ctx)
+
+	serveErr := make(chan error, 1)
+	go func() {
+		serveErr <- server.Serve(listener)
+	}()
+	fmt.Fprintf(os.Stderr, "Serving metrics on http://%s/metrics, scraping every %s (press Ctrl-C to stop)\n", listener.Addr(), flags.interval)
+
+	select {
+	case err := <-serveErr:
+		if !errors.Is(err, http.ErrServerClosed) {
+			return fmt.Errorf("metrics server failed: %w", err)
+		}
+	case <-ctx.Done():
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		if err := server.Shutdown(shutdownCtx); err != nil {
+			return fmt.Errorf("failed to stop the metrics server: %w", err)
+		}
+	}
+	return nil
+}
+
+// scrapeEvery scrapes the servers on the interval until ctx is done
+func (e *exporter) scrapeEvery(ctx context.Context) {
+	ticker := time.NewTicker(e.flags.interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if err := e.scrape(); err != nil {
+				fmt.Fprintf(os.Stderr, "%s Warning: %v\n", time.Now().Format(time.RFC3339), err)
+			}
+		}
+	}
+}
+
+// scrape scrapes the current context, or every selected context
+// concurrently, and replaces the metrics page
+func (e *exporter) scrape() error {
+	page := newExposition()
+
+	if e.flags.fanout.Enabled() {
+		results, err := fanout.Run(&e.flags.fanout, func(c *client.Client) (*exposition, error) {
+			return e.collector(c.ContextName()).scrape(c), nil
+		})
+		if err != nil {
+			return err
+		}
+		for _, result := range results {
+			if result.Err != nil {
+				e.collector(result.Context).warn("%v", result.Err)
+				page.add("orthanc_cli_up", 0, "context", result.Context)
+				continue
+			}
+			page.merge(result.Value)
+		}
+	} else {
+		c, err := getClient()
+		if err != nil {
+			return fmt.Errorf("failed to create client: %w", err)
+		}
+		page.merge(e.collector(c.ContextName()).scrape(c))
+	}
+
+	var buf bytes.Buffer
+	if _, err := page.WriteTo(&buf); err != nil {
+		return err
+	}
+
+	e.mu.Lock()
+	e.page = buf.Bytes()
+	e.mu.Unlock()
+	return nil
+}
+
+// collector returns the collector of a context, creating it on first use
+func (e *exporter) collector(name string) *collector {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	col, ok := e.collectors[name]
+	if !ok {
+		col = newCollector(name, e.flags.native)
+		e.collectors[name] = col
+	}
+	return col
+}
+
+// serveMetrics writes the latest metrics page
+func (e *exporter) serveMetrics(w http.ResponseWriter, r *http.Request) {
+	e.mu.Lock()
+	page := e.page
+	e.mu.Unlock()
+
+	w.Header().Set("Content-Type", "text/plain; version=0.0.4; charset=utf-8")
+	w.Write(page)
+}
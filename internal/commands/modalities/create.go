@@ -6,6 +6,8 @@ import (
 	"os"
 
 	"github.com/proencaj/gorthanc/types"
+	"github.com/proencaj/orthanc-cli/internal/clierr"
+	"github.com/proencaj/orthanc-cli/internal/guard"
 	"github.com/spf13/cobra"
 )
 
@@ -56,7 +58,8 @@ func NewCreateCommand() *cobra.Command {
     --port 4242 \
     --allow-store=false \
     --allow-get=false`,
-		Args: cobra.ExactArgs(1),
+		Annotations: map[string]string{guard.Annotation: guard.Mutating},
+		Args:        cobra.ExactArgs(1),
 		RunE: func(c *cobra.Command, args []string) error {
 			return runCreate(args[0], flags)
 		},
@@ -96,7 +99,7 @@ func runCreate(modalityName string, flags *CreateFlags) error {
 	} else {
 		// Validate required fields when not using file
 		if flags.aet == "" || flags.host == "" || flags.port == 0 {
-			return fmt.Errorf("when not using --file, the following flags are required: --aet, --host, --port")
+			return clierr.Validation("when not using --file, the following flags are required: --aet, --host, --port")
 		}
 
 		// Build request from flags
@@ -133,7 +136,7 @@ func readModalityFromFile(filePath string) (*types.ModalityCreateRequest, error)
 
 	// Validate required fields
 	if request.AET == "" || request.Host == "" || request.Port == 0 {
-		return nil, fmt.Errorf("JSON file must contain AET, Host, and Port fields")
+		return nil, clierr.Validation("JSON file must contain AET, Host, and Port fields")
 	}
 
 	return &request, nil
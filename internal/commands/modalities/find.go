@@ -5,6 +5,7 @@ import (
 	"fmt"
 
 	"github.com/proencaj/gorthanc/types"
+	"github.com/proencaj/orthanc-cli/internal/clierr"
 	"github.com/proencaj/orthanc-cli/internal/helpers"
 	"github.com/spf13/cobra"
 )
@@ -88,7 +89,7 @@ func runFind(modalityName string, flags *FindFlags) error {
 		"Instance": true,
 	}
 	if !validLevels[flags.level] {
-		return fmt.Errorf("invalid level '%s', must be one of: Patient, Study, Series, Instance", flags.level)
+		return clierr.Validation("invalid level '%s', must be one of: Patient, Study, Series, Instance", flags.level)
 	}
 
 	// Build the find request
@@ -0,0 +1,176 @@
+package modalities
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/proencaj/orthanc-cli/internal/client"
+	"github.com/proencaj/orthanc-cli/internal/clierr"
+	"github.com/spf13/cobra"
+)
+
+// worklistReturnKeys are the attributes requested by a worklist C-FIND,
+// matching any value unless the query sets them
+var worklistReturnKeys = []string{"PatientID", "PatientName", "PatientBirthDate", "PatientSex", "AccessionNumber", "RequestedProcedureDescription"}
+
+// worklistStepReturnKeys are the attributes of the scheduled procedure step
+// requested by a worklist C-FIND
+var worklistStepReturnKeys = []string{"Modality", "ScheduledStationAETitle", "ScheduledProcedureStepStartDate", "ScheduledProcedureStepStartTime", "ScheduledProcedureStepDescription"}
+
+// FindWorklistFlags holds the flags for the find-worklist command
+type FindWorklistFlags struct {
+	tags       []string
+	jsonOutput bool
+}
+
+// NewFindWorklistCommand creates the modalities find-worklist command
+func NewFindWorklistCommand() *cobra.Command {
+	flags := &FindWorklistFlags{}
+
+	command := &cobra.Command{
+		Use:   "find-worklist <modality-name>",
+		Short: "Perform a worklist C-FIND query on a DICOM modality",
+		Long: `Execute a modality worklist C-FIND query on a remote modality, such as a RIS
+or another Orthanc server with the worklists plugin, and list the scheduled
+procedures it answers.
+
+The attributes of the scheduled procedure step (Modality,
+ScheduledStationAETitle, ScheduledProcedureStepStartDate, ...) can be given
+flat with --tag and are moved into the ScheduledProcedureStepSequence of the
+query. The patient, accession number and scheduled procedure step are always
+requested.`,
+		Example: `  # Find the procedures scheduled today for a station
+  orthanc modalities find-worklist RIS \
+    --tag ScheduledStationAETitle=CT1 \
+    --tag ScheduledProcedureStepStartDate=$(date +%Y%m%d)
+
+  # Find the worklists of a patient
+  orthanc modalities find-worklist RIS --tag PatientID=12345
+
+  # Output in JSON format
+  orthanc modalities find-worklist RIS --tag Modality=MR --json`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(c *cobra.Command, args []string) error {
+			return runFindWorklist(args[0], flags)
+		},
+	}
+
+	// Add flags
+	command.Flags().StringArrayVar(&flags.tags, "tag", nil, "DICOM tag and value for query as Name=Value (can be specified multiple times)")
+	command.Flags().BoolVar(&flags.jsonOutput, "json", false, "Output in JSON format")
+
+	return command
+}
+
+func runFindWorklist(modalityName string, flags *FindWorklistFlags) error {
+	query, err := buildWorklistQuery(flags.tags)
+	if err != nil {
+		return err
+	}
+	request := &client.FindWorklistRequest{Query: query, Simplify: true}
+
+	// Get the Orthanc client
+	client, err := getClient()
+	if err != nil {
+		return fmt.Errorf("failed to create client: %w", err)
+	}
+
+	// Check if JSON output should be used (flag or config)
+	jsonOutput := flags.jsonOutput || shouldUseJSON()
+
+	answers, err := client.FindWorklist(modalityName, request)
+	if err != nil {
+		return fmt.Errorf("worklist C-FIND failed: %w", err)
+	}
+
+	if jsonOutput {
+		data, err := json.MarshalIndent(answers, "", "  ")
+		if err != nil {
+			return fmt.Errorf("failed to marshal JSON: %w", err)
+		}
+		fmt.Println(string(data))
+		return nil
+	}
+
+	// Raw text output
+	if len(answers) == 0 {
+		fmt.Println("No worklists found.")
+		return nil
+	}
+
+	fmt.Printf("%-12s  %-24s  %-14s  %-8s  %-16s  %-8s  %-6s  %s\n",
+		"PATIENT ID", "PATIENT NAME", "ACCESSION", "MODALITY", "STATION AET", "DATE", "TIME", "DESCRIPTION")
+	for _, answer := range answers {
+		step := answerStep(answer)
+		description := answerTag(step, "ScheduledProcedureStepDescription")
+		if description == "" {
+			description = answerTag(answer, "RequestedProcedureDescription")
+		}
+		fmt.Printf("%-12s  %-24s  %-14s  %-8s  %-16s  %-8s  %-6s  %s\n",
+			answerTag(answer, "PatientID"),
+			answerTag(answer, "PatientName"),
+			answerTag(answer, "AccessionNumber"),
+			answerTag(step, "Modality"),
+			answerTag(step, "ScheduledStationAETitle"),
+			answerTag(step, "ScheduledProcedureStepStartDate"),
+			answerTag(step, "ScheduledProcedureStepStartTime"),
+			description)
+	}
+	fmt.Printf("\nFound %d worklist(s)\n", len(answers))
+
+	return nil
+}
+
+// buildWorklistQuery builds a worklist C-FIND query from Name=Value tags,
+// moving the scheduled procedure step attributes into their sequence
+func buildWorklistQuery(tags []string) (map[string]interface{}, error) {
+	query := map[string]interface{}{}
+	step := map[string]interface{}{}
+	for _, key := range worklistReturnKeys {
+		query[key] = ""
+	}
+	for _, key := range worklistStepReturnKeys {
+		step[key] = ""
+	}
+
+	for _, tag := range tags {
+		name, value, ok := strings.Cut(tag, "=")
+		if !ok || name == "" {
+			return nil, clierr.Validation("invalid --tag '%s', expected Name=Value", tag)
+		}
+		if name == client.WorklistStepSequence {
+			return nil, clierr.Validation("set the attributes of %s directly, e.g. --tag Modality=CT", name)
+		}
+		if client.WorklistStepTags[name] {
+			step[name] = value
+		} else {
+			query[name] = value
+		}
+	}
+
+	query[client.WorklistStepSequence] = []interface{}{step}
+	return query, nil
+}
+
+// answerTag returns a tag of a worklist C-FIND answer as a string
+func answerTag(answer map[string]interface{}, name string) string {
+	switch value := answer[name].(type) {
+	case nil:
+		return ""
+	case string:
+		return value
+	default:
+		return fmt.Sprint(value)
+	}
+}
+
+// answerStep returns the first scheduled procedure step of a worklist C-FIND answer
+func answerStep(answer map[string]interface{}) map[string]interface{} {
+	if items, ok := answer[client.WorklistStepSequence].([]interface{}); ok && len(items) > 0 {
+		if item, ok := items[0].(map[string]interface{}); ok {
+			return item
+		}
+	}
+	return map[string]interface{}{}
+}
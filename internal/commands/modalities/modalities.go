@@ -52,6 +52,7 @@ func NewModalitiesCommand() *cobra.Command {
 	modalitiesCmd.AddCommand(NewRemoveCommand())
 	modalitiesCmd.AddCommand(NewEchoCommand())
 	modalitiesCmd.AddCommand(NewFindCommand())
+	modalitiesCmd.AddCommand(NewFindWorklistCommand())
 	modalitiesCmd.AddCommand(NewMoveCommand())
 	modalitiesCmd.AddCommand(NewStoreCommand())
 	modalitiesCmd.AddCommand(NewRetrieveCommand())
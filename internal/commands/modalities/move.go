@@ -5,6 +5,8 @@ import (
 	"fmt"
 
 	"github.com/proencaj/gorthanc/types"
+	"github.com/proencaj/orthanc-cli/internal/clierr"
+	"github.com/proencaj/orthanc-cli/internal/guard"
 	"github.com/proencaj/orthanc-cli/internal/helpers"
 	"github.com/spf13/cobra"
 )
@@ -66,7 +68,8 @@ The resources will be moved to the specified target AET (usually your local Orth
     --target-aet ORTHANC \
     --resource PatientID=12345 \
     --resource StudyDate=20240101`,
-		Args: cobra.ExactArgs(1),
+		Annotations: map[string]string{guard.Annotation: guard.Mutating},
+		Args:        cobra.ExactArgs(1),
 		RunE: func(c *cobra.Command, args []string) error {
 			return runMove(args[0], flags)
 		},
@@ -108,12 +111,12 @@ func runMove(modalityName string, flags *MoveFlags) error {
 		"Instance": true,
 	}
 	if !validLevels[flags.level] {
-		return fmt.Errorf("invalid level '%s', must be one of: Patient, Study, Series, Instance", flags.level)
+		return clierr.Validation("invalid level '%s', must be one of: Patient, Study, Series, Instance", flags.level)
 	}
 
 	// Validate priority
 	if flags.priority < 0 || flags.priority > 2 {
-		return fmt.Errorf("invalid priority '%d', must be 0 (medium), 1 (high), or 2 (low)", flags.priority)
+		return clierr.Validation("invalid priority '%d', must be 0 (medium), 1 (high), or 2 (low)", flags.priority)
 	}
 
 	// Convert resources map to []map[string]interface{}
@@ -4,6 +4,8 @@ import (
 	"fmt"
 
 	"github.com/proencaj/gorthanc/types"
+	"github.com/proencaj/orthanc-cli/internal/clierr"
+	"github.com/proencaj/orthanc-cli/internal/guard"
 	"github.com/proencaj/orthanc-cli/internal/helpers"
 	"github.com/spf13/cobra"
 )
@@ -63,7 +65,8 @@ The resources will be retrieved and stored in your local Orthanc instance.`,
     --level Study \
     --resource StudyInstanceUID=1.2.3.4.5 \
     --permissive`,
-		Args: cobra.ExactArgs(1),
+		Annotations: map[string]string{guard.Annotation: guard.Mutating},
+		Args:        cobra.ExactArgs(1),
 		RunE: func(c *cobra.Command, args []string) error {
 			return runRetrieve(args[0], flags)
 		},
@@ -101,7 +104,7 @@ func runRetrieve(modalityName string, flags *RetrieveFlags) error {
 		"Instance": true,
 	}
 	if !validLevels[flags.level] {
-		return fmt.Errorf("invalid level '%s', must be one of: Patient, Study, Series, Instance", flags.level)
+		return clierr.Validation("invalid level '%s', must be one of: Patient, Study, Series, Instance", flags.level)
 	}
 
 	// Convert resources map to []map[string]interface{}
@@ -5,6 +5,8 @@ import (
 	"fmt"
 
 	"github.com/proencaj/gorthanc/types"
+	"github.com/proencaj/orthanc-cli/internal/batch"
+	"github.com/proencaj/orthanc-cli/internal/guard"
 	"github.com/proencaj/orthanc-cli/internal/helpers"
 	"github.com/spf13/cobra"
 )
@@ -20,6 +22,7 @@ type StoreFlags struct {
 	moveOriginatorID  int
 	permissive        int
 	storageCommitment int
+	transcode         string
 	jsonOutput        bool
 }
 
@@ -28,10 +31,11 @@ func NewStoreCommand() *cobra.Command {
 	flags := &StoreFlags{}
 
 	command := &cobra.Command{
-		Use:   "store <modality-name> <resource-id> [resource-id...]",
+		Use:   "store <modality-name> <resource-id>... | -",
 		Short: "Perform a C-STORE operation to send resources to a DICOM modality",
 		Long: `Execute a DICOM C-STORE operation to send studies, series, or instances to a remote modality.
-The resources (identified by their Orthanc IDs) will be transmitted to the specified DICOM modality.`,
+The resources (identified by their Orthanc IDs) will be transmitted to the specified DICOM modality
+in a single C-STORE operation. Use - to read resource IDs from stdin, one per line.`,
 		Example: `  # Store a single study to a PACS
   orthanc modalities store PACS_SERVER a1b2c3d4-e5f6-7890-abcd-ef1234567890
 
@@ -41,6 +45,9 @@ The resources (identified by their Orthanc IDs) will be transmitted to the speci
     study-id-2 \
     series-id-1
 
+  # Store every study, reading IDs from stdin
+  orthanc studies list | orthanc modalities store PACS_SERVER -
+
   # Store with synchronous mode (wait for completion)
   orthanc modalities store PACS_SERVER study-id \
     --synchronous
@@ -50,6 +57,10 @@ The resources (identified by their Orthanc IDs) will be transmitted to the speci
     --timeout 60 \
     --local-aet MY_LOCAL_AET
 
+  # Transcode to JPEG baseline before sending
+  orthanc modalities store PACS_SERVER study-id \
+    --transcode jpeg-baseline
+
   # Store with all options
   orthanc modalities store PACS_SERVER study-id \
     --synchronous \
@@ -57,10 +68,15 @@ The resources (identified by their Orthanc IDs) will be transmitted to the speci
     --local-aet ORTHANC \
     --remote-aet PACS \
     --json`,
-		Args: cobra.MinimumNArgs(2),
+		Annotations: map[string]string{guard.Annotation: guard.Mutating},
+		Args:        cobra.MinimumNArgs(2),
 		RunE: func(c *cobra.Command, args []string) error {
 			modalityName := args[0]
-			flags.resources = args[1:]
+			resources, err := batch.ReadIDs(args[1:])
+			if err != nil {
+				return err
+			}
+			flags.resources = resources
 			return runStore(modalityName, flags)
 		},
 	}
@@ -74,6 +90,7 @@ The resources (identified by their Orthanc IDs) will be transmitted to the speci
 	command.Flags().IntVar(&flags.moveOriginatorID, "move-originator-id", 0, "Move Originator ID (for C-MOVE operations)")
 	command.Flags().IntVar(&flags.permissive, "permissive", 0, "Permissive mode (0=strict, 1=permissive)")
 	command.Flags().IntVar(&flags.storageCommitment, "storage-commitment", 0, "Storage commitment (0=disabled, 1=enabled)")
+	command.Flags().StringVar(&flags.transcode, "transcode", "", "Transcode to a transfer syntax UID or alias before sending (e.g. explicit-little, jpeg-baseline)")
 	command.Flags().BoolVar(&flags.jsonOutput, "json", false, "Output in JSON format")
 
 	return command
@@ -86,6 +103,23 @@ func runStore(modalityName string, flags *StoreFlags) error {
 		return fmt.Errorf("failed to create client: %w", err)
 	}
 
+	// Resolve the transfer syntax alias to its UID
+	transferSyntax := ""
+	if flags.transcode != "" {
+		transferSyntax, err = client.ResolveTransferSyntax(flags.transcode)
+		if err != nil {
+			return err
+		}
+	}
+
+	// Resolve DICOM UIDs and prefixed identifiers to Orthanc IDs
+	for i, resource := range flags.resources {
+		flags.resources[i], err = client.ResolveID("", resource)
+		if err != nil {
+			return err
+		}
+	}
+
 	// Check if JSON output should be used (flag or config)
 	jsonOutput := flags.jsonOutput || shouldUseJSON()
 
@@ -114,6 +148,9 @@ func runStore(modalityName string, flags *StoreFlags) error {
 			fmt.Printf("Remote AET: %s\n", flags.remoteAet)
 		}
 		fmt.Printf("Timeout: %d seconds\n", flags.timeout)
+		if transferSyntax != "" {
+			fmt.Printf("Transcode: %s\n", transferSyntax)
+		}
 		fmt.Println()
 		fmt.Println("Resource IDs:")
 		for i, resourceID := range flags.resources {
@@ -123,7 +160,12 @@ func runStore(modalityName string, flags *StoreFlags) error {
 	}
 
 	// Perform C-STORE
-	result, err := client.StoreToModalityWithOptions(modalityName, request)
+	var result *types.ModalityStoreResult
+	if transferSyntax != "" {
+		result, err = client.StoreToModalityTranscoded(modalityName, request, transferSyntax)
+	} else {
+		result, err = client.StoreToModalityWithOptions(modalityName, request)
+	}
 	if err != nil {
 		return fmt.Errorf("C-STORE failed: %w", err)
 	}
@@ -4,11 +4,15 @@ import (
 	"encoding/json"
 	"fmt"
 
+	"github.com/proencaj/gorthanc/types"
+	"github.com/proencaj/orthanc-cli/internal/batch"
+	"github.com/proencaj/orthanc-cli/internal/parallel"
 	"github.com/spf13/cobra"
 )
 
 // GetFlags holds the flags for the get command
 type GetFlags struct {
+	parallel   int
 	jsonOutput bool
 }
 
@@ -17,27 +21,36 @@ func NewGetCommand() *cobra.Command {
 	flags := &GetFlags{}
 
 	command := &cobra.Command{
-		Use:   "get <patient-id>",
+		Use:   "get <patient-id>...",
 		Short: "Get detailed information about a patient",
 		Long:  `Retrieve and display detailed information about a specific patient from the Orthanc server.`,
 		Example: `  # Get patient details
   orthanc patients get abc123
 
   # Get patient details in JSON format
-  orthanc patients get abc123 --json`,
-		Args: cobra.ExactArgs(1),
+  orthanc patients get abc123 --json
+
+  # Get several patients, reading IDs from stdin
+  orthanc patients list | orthanc patients get - --json`,
+		Args: cobra.MinimumNArgs(1),
 		RunE: func(c *cobra.Command, args []string) error {
-			return runGet(args[0], flags)
+			return runGet(args, flags)
 		},
 	}
 
 	// Add flags
+	command.Flags().IntVar(&flags.parallel, "parallel", parallel.DefaultWorkers, "Number of resources fetched in parallel")
 	command.Flags().BoolVar(&flags.jsonOutput, "json", false, "Output in JSON format")
 
 	return command
 }
 
-func runGet(patientID string, flags *GetFlags) error {
+func runGet(args []string, flags *GetFlags) error {
+	patientIDs, err := batch.ReadIDs(args)
+	if err != nil {
+		return err
+	}
+
 	// Get the Orthanc client
 	client, err := getClient()
 	if err != nil {
@@ -47,13 +60,26 @@ func runGet(patientID string, flags *GetFlags) error {
 	// Check if JSON output should be used (flag or config)
 	jsonOutput := flags.jsonOutput || shouldUseJSON()
 
-	// Fetch patient details
-	patient, err := client.GetPatientDetails(patientID)
-	if err != nil {
-		return fmt.Errorf("failed to fetch patient details: %w", err)
-	}
+	// Fetch the patients in parallel and display them in order
+	patients := make([]*types.Patient, len(patientIDs))
+	return batch.Run(patientIDs, flags.parallel, func(i int) error {
+		// Resolve DICOM UIDs and prefixed identifiers to the Orthanc ID
+		patientID, err := client.ResolveID(types.ResourceLevelPatient, patientIDs[i])
+		if err != nil {
+			return err
+		}
+
+		patients[i], err = client.GetPatientDetails(patientID)
+		if err != nil {
+			return fmt.Errorf("failed to fetch patient details: %w", err)
+		}
+		return nil
+	}, func(i int) error {
+		return displayPatient(patients[i], jsonOutput)
+	})
+}
 
-	// Display the patient
+func displayPatient(patient *types.Patient, jsonOutput bool) error {
 	if jsonOutput {
 		data, err := json.MarshalIndent(patient, "", "  ")
 		if err != nil {
@@ -41,12 +41,20 @@ func NewPatientsCommand() *cobra.Command {
 	patientsCmd := &cobra.Command{
 		Use:   "patients",
 		Short: "Manage Orthanc patients",
-		Long:  `Query, list, and manage DICOM patients in the Orthanc server.`,
+		Long: `Query, list, and manage DICOM patients in the Orthanc server.
+
+Patients can be given by Orthanc ID or as pid:<PatientID>.
+Identifiers that match several resources are rejected as ambiguous.
+
+Commands that take patients also accept several IDs, or - to read them from
+stdin one per line, and process them in parallel (see --parallel). If some of
+them fail, the others are still processed and the exit code is 10.`,
 	}
 
 	// Add subcommands
 	patientsCmd.AddCommand(NewListCommand())
 	patientsCmd.AddCommand(NewGetCommand())
+	patientsCmd.AddCommand(NewStatisticsCommand())
 	patientsCmd.AddCommand(NewRemoveCommand())
 	patientsCmd.AddCommand(NewAnonymizeCommand())
 
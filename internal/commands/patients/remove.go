@@ -4,14 +4,22 @@ import (
 	"bufio"
 	"fmt"
 	"os"
+	"slices"
 	"strings"
 
+	"github.com/proencaj/gorthanc/types"
+	"github.com/proencaj/orthanc-cli/internal/batch"
+	"github.com/proencaj/orthanc-cli/internal/clierr"
+	"github.com/proencaj/orthanc-cli/internal/guard"
+	"github.com/proencaj/orthanc-cli/internal/parallel"
+	"github.com/proencaj/orthanc-cli/internal/trash"
 	"github.com/spf13/cobra"
 )
 
 // RemoveFlags holds the flags for the remove command
 type RemoveFlags struct {
-	force bool
+	force    bool
+	parallel int
 }
 
 // NewRemoveCommand creates the patients remove command
@@ -19,36 +27,60 @@ func NewRemoveCommand() *cobra.Command {
 	flags := &RemoveFlags{}
 
 	command := &cobra.Command{
-		Use:   "remove <patient-id>",
+		Use:   "remove <patient-id>...",
 		Short: "Remove a patient from the Orthanc server",
-		Long:  `Delete a patient from the Orthanc server. This operation is irreversible.`,
+		Long: `Delete a patient from the Orthanc server.
+This operation is irreversible unless the trash is enabled.
+
+When the trash setting is enabled for the context (orthanc config set trash
+true), the patient is first downloaded to a local trash, and is only deleted
+once the copy is complete. Use 'orthanc trash restore' to upload it again.`,
 		Example: `  # Remove a patient with confirmation prompt
   orthanc patients remove abc123
 
   # Remove a patient without confirmation
-  orthanc patients remove abc123 --force`,
-		Args: cobra.ExactArgs(1),
+  orthanc patients remove abc123 --force
+
+  # Remove several patients listed in a file, one ID per line
+  orthanc patients remove - --force < patients.txt`,
+		Annotations: map[string]string{
+			guard.Annotation:  guard.Destructive,
+			guard.ConfirmFlag: "force",
+		},
+		Args: cobra.MinimumNArgs(1),
 		RunE: func(c *cobra.Command, args []string) error {
-			return runRemove(args[0], flags)
+			return runRemove(args, flags)
 		},
 	}
 
 	// Add flags
 	command.Flags().BoolVarP(&flags.force, "force", "f", false, "Skip confirmation prompt")
+	command.Flags().IntVar(&flags.parallel, "parallel", parallel.DefaultWorkers, "Number of resources deleted in parallel")
 
 	return command
 }
 
-func runRemove(patientID string, flags *RemoveFlags) error {
+func runRemove(args []string, flags *RemoveFlags) error {
+	patientIDs, err := batch.ReadIDs(args)
+	if err != nil {
+		return err
+	}
+	if !flags.force && slices.Contains(args, batch.Stdin) {
+		return clierr.Validation("--force is required when reading IDs from stdin")
+	}
+
 	// Get the Orthanc client
 	client, err := getClient()
 	if err != nil {
 		return fmt.Errorf("failed to create client: %w", err)
 	}
 
-	// If not using force flag, prompt for confirmation
-	if !flags.force {
-		confirmed, err := confirmRemoval(patientID)
+	// Keep a local copy of each resource before deleting it, if enabled for the context
+	useTrash := client.GetConfig().TrashEnabled()
+
+	// Prompt for confirmation, unless forced or already confirmed for a protected context
+	if !flags.force && !guard.Confirmed() {
+		confirmed, err := confirmRemoval(patientIDs, useTrash)
 		if err != nil {
 			return fmt.Errorf("failed to get confirmation: %w", err)
 		}
@@ -58,20 +90,54 @@ func runRemove(patientID string, flags *RemoveFlags) error {
 		}
 	}
 
-	// Delete the patient
-	err = client.DeletePatient(patientID)
-	if err != nil {
-		return fmt.Errorf("failed to delete patient: %w", err)
-	}
+	// Delete the patients in parallel
+	deleted := make([]string, len(patientIDs))
+	trashed := make([]*trash.Entry, len(patientIDs))
+	return batch.Run(patientIDs, flags.parallel, func(i int) error {
+		// Resolve DICOM UIDs and prefixed identifiers to the Orthanc ID
+		patientID, err := client.ResolveID(types.ResourceLevelPatient, patientIDs[i])
+		if err != nil {
+			return err
+		}
 
-	fmt.Printf("Successfully deleted patient: %s\n", patientID)
-	return nil
+		if useTrash {
+			trashed[i], err = trash.Save(client, types.ResourceLevelPatient, patientID)
+			if err != nil {
+				return fmt.Errorf("failed to keep a copy in the trash, patient not deleted: %w", err)
+			}
+		}
+
+		if err := client.DeletePatient(patientID); err != nil {
+			// Do not keep a copy of a patient that still exists
+			if trashed[i] != nil {
+				trash.Remove(trashed[i])
+			}
+			return fmt.Errorf("failed to delete patient: %w", err)
+		}
+		deleted[i] = patientID
+		return nil
+	}, func(i int) error {
+		fmt.Printf("Successfully deleted patient: %s\n", deleted[i])
+		if trashed[i] != nil {
+			fmt.Printf("Kept in trash as: %s\n", trashed[i].ID)
+		}
+		return nil
+	})
 }
 
-func confirmRemoval(patientID string) (bool, error) {
-	fmt.Printf("\n⚠️  WARNING: You are about to delete patient '%s'\n", patientID)
-	fmt.Println("This operation is NOT reversible and will permanently remove all associated data.")
-	fmt.Print("\nDo you really want to delete this patient? (yes/no): ")
+func confirmRemoval(patientIDs []string, useTrash bool) (bool, error) {
+	target, this := fmt.Sprintf("patient '%s'", patientIDs[0]), "this patient"
+	if len(patientIDs) > 1 {
+		target, this = fmt.Sprintf("%d patients", len(patientIDs)), "these patients"
+	}
+
+	fmt.Printf("\n⚠️  WARNING: You are about to delete %s\n", target)
+	if useTrash {
+		fmt.Println("A copy will be kept in the local trash and can be restored with 'orthanc trash restore'.")
+	} else {
+		fmt.Println("This operation is NOT reversible and will permanently remove all associated data.")
+	}
+	fmt.Printf("\nDo you really want to delete %s? (yes/no): ", this)
 
 	reader := bufio.NewReader(os.Stdin)
 	response, err := reader.ReadString('\n')
@@ -0,0 +1,105 @@
+package patients
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/proencaj/gorthanc/types"
+	"github.com/proencaj/orthanc-cli/internal/batch"
+	"github.com/proencaj/orthanc-cli/internal/parallel"
+	"github.com/spf13/cobra"
+)
+
+// StatisticsFlags holds the flags for the statistics command
+type StatisticsFlags struct {
+	parallel   int
+	jsonOutput bool
+}
+
+// NewStatisticsCommand creates the patients statistics command
+func NewStatisticsCommand() *cobra.Command {
+	flags := &StatisticsFlags{}
+
+	command := &cobra.Command{
+		Use:   "statistics <patient-id>...",
+		Short: "Show the storage usage of a patient",
+		Long:  `Show the number of studies, series and instances and the disk and uncompressed sizes of a patient.`,
+		Example: `  # Show the statistics of a patient
+  orthanc patients statistics abc123
+
+  # Show the statistics of several patients, reading IDs from stdin
+  orthanc patients list | orthanc patients statistics - --json`,
+		Args: cobra.MinimumNArgs(1),
+		RunE: func(c *cobra.Command, args []string) error {
+			return runStatistics(args, flags)
+		},
+	}
+
+	// Add flags
+	command.Flags().IntVar(&flags.parallel, "parallel", parallel.DefaultWorkers, "Number of resources fetched in parallel")
+	command.Flags().BoolVar(&flags.jsonOutput, "json", false, "Output in JSON format")
+
+	return command
+}
+
+func runStatistics(args []string, flags *StatisticsFlags) error {
+	patientIDs, err := batch.ReadIDs(args)
+	if err != nil {
+		return err
+	}
+
+	// Get the Orthanc client
+	client, err := getClient()
+	if err != nil {
+		return fmt.Errorf("failed to create client: %w", err)
+	}
+
+	// Check if JSON output should be used (flag or config)
+	jsonOutput := flags.jsonOutput || shouldUseJSON()
+
+	// Fetch the statistics in parallel and display them in order
+	statistics := make([]*types.PatientStatistics, len(patientIDs))
+	return batch.Run(patientIDs, flags.parallel, func(i int) error {
+		// Resolve DICOM UIDs and prefixed identifiers to the Orthanc ID
+		patientID, err := client.ResolveID(types.ResourceLevelPatient, patientIDs[i])
+		if err != nil {
+			return err
+		}
+
+		statistics[i], err = client.GetPatientStatistics(patientID)
+		if err != nil {
+			return fmt.Errorf("failed to get patient statistics: %w", err)
+		}
+		patientIDs[i] = patientID
+		return nil
+	}, func(i int) error {
+		return displayStatistics(patientIDs[i], statistics[i], jsonOutput)
+	})
+}
+
+// statisticsReport is the JSON output of the statistics command, with the
+// Orthanc ID of the patient
+type statisticsReport struct {
+	ID string `json:"ID"`
+	*types.PatientStatistics
+}
+
+func displayStatistics(patientID string, statistics *types.PatientStatistics, jsonOutput bool) error {
+	if jsonOutput {
+		data, err := json.MarshalIndent(&statisticsReport{ID: patientID, PatientStatistics: statistics}, "", "  ")
+		if err != nil {
+			return fmt.Errorf("failed to marshal JSON: %w", err)
+		}
+		fmt.Println(string(data))
+		return nil
+	}
+
+	// Raw text output
+	fmt.Printf("OrthancPatientID: %s\n", patientID)
+	fmt.Printf("CountStudies: %d\n", statistics.CountStudies)
+	fmt.Printf("CountSeries: %d\n", statistics.CountSeries)
+	fmt.Printf("CountInstances: %d\n", statistics.CountInstances)
+	fmt.Printf("DiskSize: %s bytes (%d MB)\n", statistics.DiskSize, statistics.DiskSizeMB)
+	fmt.Printf("UncompressedSize: %s bytes (%d MB)\n", statistics.UncompressedSize, statistics.UncompressedSizeMB)
+	return nil
+}
@@ -0,0 +1,148 @@
+package plugins
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"os"
+
+	"github.com/proencaj/gorthanc/types"
+	"github.com/proencaj/orthanc-cli/internal/client"
+	"github.com/spf13/cobra"
+)
+
+// ExplorerFlags holds the flags for the explorer command
+type ExplorerFlags struct {
+	study      string
+	jsonOutput bool
+}
+
+// explorerReport is the JSON output of the explorer command; viewers that
+// are not installed are left out
+type explorerReport struct {
+	Context          string `json:"Context"`
+	OrthancExplorer  string `json:"OrthancExplorer"`
+	OrthancExplorer2 string `json:"OrthancExplorer2,omitempty"`
+	StoneWebViewer   string `json:"StoneWebViewer,omitempty"`
+}
+
+// NewExplorerCommand creates the plugins explorer command
+func NewExplorerCommand() *cobra.Command {
+	flags := &ExplorerFlags{}
+
+	command := &cobra.Command{
+		Use:   "explorer",
+		Short: "Print the URLs of the web interfaces of the current context",
+		Long: `Print the URLs of the built-in Orthanc Explorer and, when their plugins are
+loaded, of Orthanc Explorer 2 and the Stone Web Viewer for the current context.
+
+With --study, the URLs open that study.`,
+		Example: `  # Print the URLs of the web interfaces
+  orthanc plugins explorer
+
+  # Print the URLs opening a study, by Orthanc ID or DICOM UID
+  orthanc plugins explorer --study 1.2.840.113619.2.55.3.604688119.969.1268071029.320`,
+		Args: cobra.NoArgs,
+		RunE: func(c *cobra.Command, args []string) error {
+			return runExplorer(flags)
+		},
+	}
+
+	// Add flags
+	command.Flags().StringVar(&flags.study, "study", "", "Open this study (Orthanc ID, StudyInstanceUID or acc:/pid: identifier)")
+	command.Flags().BoolVar(&flags.jsonOutput, "json", false, "Output in JSON format")
+
+	return command
+}
+
+func runExplorer(flags *ExplorerFlags) error {
+	// Get the Orthanc client
+	client, err := getClient()
+	if err != nil {
+		return fmt.Errorf("failed to create client: %w", err)
+	}
+
+	// Check if JSON output should be used (flag or config)
+	jsonOutput := flags.jsonOutput || shouldUseJSON()
+
+	// Resolve the study to both its Orthanc ID and its StudyInstanceUID
+	var studyID, studyUID string
+	if flags.study != "" {
+		studyID, err = client.ResolveID(types.ResourceLevelStudy, flags.study)
+		if err != nil {
+			return err
+		}
+		study, err := client.GetStudy(studyID)
+		if err != nil {
+			return fmt.Errorf("failed to fetch study: %w", err)
+		}
+		studyUID = study.MainDicomTags.StudyInstanceUID
+	}
+
+	report := &explorerReport{Context: client.ContextName()}
+	report.OrthancExplorer, err = client.WebURL("app/explorer.html")
+	if err != nil {
+		return err
+	}
+	if studyID != "" {
+		report.OrthancExplorer += "#study?uuid=" + url.QueryEscape(studyID)
+	}
+
+	ids, err := client.ListPlugins()
+	if err != nil {
+		return fmt.Errorf("failed to fetch plugins: %w", err)
+	}
+	for _, id := range ids {
+		switch id {
+		case pluginExplorer2:
+			if report.OrthancExplorer2, err = viewerURL(client, id); err != nil {
+				return err
+			}
+			if studyUID != "" {
+				report.OrthancExplorer2 += "#/filtered-studies?StudyInstanceUID=" + url.QueryEscape(studyUID) + "&expand=series"
+			}
+		case pluginStone:
+			if report.StoneWebViewer, err = viewerURL(client, id); err != nil {
+				return err
+			}
+			if studyUID != "" {
+				report.StoneWebViewer += "?study=" + url.QueryEscape(studyUID)
+			}
+		}
+	}
+
+	if jsonOutput {
+		// Keep the '&' of URLs readable instead of escaping it for HTML
+		encoder := json.NewEncoder(os.Stdout)
+		encoder.SetEscapeHTML(false)
+		encoder.SetIndent("", "  ")
+		if err := encoder.Encode(report); err != nil {
+			return fmt.Errorf("failed to marshal JSON: %w", err)
+		}
+		return nil
+	}
+
+	fmt.Printf("Context:             %s\n", report.Context)
+	fmt.Printf("Orthanc Explorer:    %s\n", report.OrthancExplorer)
+	fmt.Printf("Orthanc Explorer 2:  %s\n", orNotInstalled(report.OrthancExplorer2))
+	fmt.Printf("Stone Web Viewer:    %s\n", orNotInstalled(report.StoneWebViewer))
+
+	return nil
+}
+
+// viewerURL returns the URL of the web interface of a plugin
+func viewerURL(c *client.Client, id string) (string, error) {
+	plugin, err := c.GetPlugin(id)
+	if err != nil {
+		return "", fmt.Errorf("failed to fetch plugin details of %s: %w", id, err)
+	}
+	return c.PluginURL(plugin)
+}
+
+// orNotInstalled returns the URL, or a note if the viewer is not installed
+func orNotInstalled(link string) string {
+	if link == "" {
+		return "(not installed)"
+	}
+	return link
+}
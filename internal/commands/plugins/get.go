@@ -0,0 +1,104 @@
+package plugins
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/proencaj/orthanc-cli/internal/batch"
+	"github.com/proencaj/orthanc-cli/internal/parallel"
+	"github.com/spf13/cobra"
+)
+
+// GetFlags holds the flags for the get command
+type GetFlags struct {
+	jsonOutput bool
+}
+
+// NewGetCommand creates the plugins get command
+func NewGetCommand() *cobra.Command {
+	flags := &GetFlags{}
+
+	command := &cobra.Command{
+		Use:   "get <plugin-id>...",
+		Short: "Get details of a plugin",
+		Long: `Get the version, description and web interface URL of a plugin, and the CLI
+features it enables.`,
+		Example: `  # Get details of the DICOMweb plugin
+  orthanc plugins get dicom-web
+
+  # Get details of several plugins in JSON format
+  orthanc plugins get dicom-web orthanc-explorer-2 --json`,
+		Args: cobra.MinimumNArgs(1),
+		RunE: func(c *cobra.Command, args []string) error {
+			return runGet(args, flags)
+		},
+	}
+
+	// Add flags
+	command.Flags().BoolVar(&flags.jsonOutput, "json", false, "Output in JSON format")
+
+	return command
+}
+
+func runGet(args []string, flags *GetFlags) error {
+	ids, err := batch.ReadIDs(args)
+	if err != nil {
+		return err
+	}
+
+	// Get the Orthanc client
+	client, err := getClient()
+	if err != nil {
+		return fmt.Errorf("failed to create client: %w", err)
+	}
+
+	// Check if JSON output should be used (flag or config)
+	jsonOutput := flags.jsonOutput || shouldUseJSON()
+
+	// Fetch the plugins in parallel and display them in order
+	reports := make([]*pluginReport, len(ids))
+	return batch.Run(ids, parallel.DefaultWorkers, func(i int) error {
+		var err error
+		reports[i], err = describePlugin(client, ids[i])
+		if err != nil {
+			return fmt.Errorf("failed to fetch plugin details: %w", err)
+		}
+		return nil
+	}, func(i int) error {
+		return displayPlugin(reports[i], jsonOutput)
+	})
+}
+
+func displayPlugin(report *pluginReport, jsonOutput bool) error {
+	if jsonOutput {
+		data, err := json.MarshalIndent(report, "", "  ")
+		if err != nil {
+			return fmt.Errorf("failed to marshal JSON: %w", err)
+		}
+		fmt.Println(string(data))
+		return nil
+	}
+
+	// Raw text output
+	fmt.Printf("ID: %s\n", report.ID)
+	fmt.Printf("Version: %s\n", report.Version)
+	if report.ExtendedID != "" {
+		fmt.Printf("ExtendedID: %s\n", report.ExtendedID)
+	}
+	fmt.Printf("Description: %s\n", report.Description)
+	if report.URL != "" {
+		fmt.Printf("URL: %s\n", report.URL)
+	}
+	if len(report.Features) > 0 {
+		fmt.Println("CLI Features:")
+		for _, feature := range report.Features {
+			if feature.Command != "" {
+				fmt.Printf("  - %s: %s\n", feature.Command, feature.Description)
+			} else {
+				fmt.Printf("  - %s\n", feature.Description)
+			}
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,99 @@
+package plugins
+
+import (
+	"encoding/json"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/proencaj/orthanc-cli/internal/parallel"
+	"github.com/spf13/cobra"
+)
+
+// ListFlags holds the flags for the list command
+type ListFlags struct {
+	jsonOutput bool
+}
+
+// NewListCommand creates the plugins list command
+func NewListCommand() *cobra.Command {
+	flags := &ListFlags{}
+
+	command := &cobra.Command{
+		Use:   "list",
+		Short: "List the plugins of the Orthanc server",
+		Long: `List the plugins loaded by the Orthanc server with their version and the CLI
+commands they enable. Plugins whose details cannot be read are listed with
+the error.`,
+		Example: `  # List the plugins
+  orthanc plugins list
+
+  # Output in JSON format
+  orthanc plugins list --json`,
+		Args: cobra.NoArgs,
+		RunE: func(c *cobra.Command, args []string) error {
+			return runList(flags)
+		},
+	}
+
+	// Add flags
+	command.Flags().BoolVar(&flags.jsonOutput, "json", false, "Output in JSON format")
+
+	return command
+}
+
+func runList(flags *ListFlags) error {
+	// Get the Orthanc client
+	client, err := getClient()
+	if err != nil {
+		return fmt.Errorf("failed to create client: %w", err)
+	}
+
+	// Check if JSON output should be used (flag or config)
+	jsonOutput := flags.jsonOutput || shouldUseJSON()
+
+	ids, err := client.ListPlugins()
+	if err != nil {
+		return fmt.Errorf("failed to fetch plugins: %w", err)
+	}
+
+	reports := make([]*pluginReport, len(ids))
+	parallel.ForEach(len(ids), parallel.DefaultWorkers, func(i int) error {
+		// Failures are listed with the plugin instead of aborting
+		reports[i], _ = describePlugin(client, ids[i])
+		return nil
+	})
+
+	if jsonOutput {
+		data, err := json.MarshalIndent(reports, "", "  ")
+		if err != nil {
+			return fmt.Errorf("failed to marshal JSON: %w", err)
+		}
+		fmt.Println(string(data))
+		return nil
+	}
+
+	if len(reports) == 0 {
+		fmt.Println("No plugins loaded")
+		return nil
+	}
+
+	fmt.Printf("%-24s  %-12s  %s\n", "ID", "VERSION", "CLI FEATURES")
+	for _, report := range reports {
+		var commands []string
+		for _, feature := range report.Features {
+			if feature.Command != "" && !slices.Contains(commands, feature.Command) {
+				commands = append(commands, feature.Command)
+			}
+		}
+		features := strings.Join(commands, ", ")
+		if report.Error != "" {
+			features = "error: " + report.Error
+		} else if features == "" {
+			features = "-"
+		}
+		fmt.Printf("%-24s  %-12s  %s\n", report.ID, report.Version, features)
+	}
+
+	return nil
+}
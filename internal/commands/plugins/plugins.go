@@ -0,0 +1,133 @@
+package plugins
+
+import (
+	"github.com/proencaj/orthanc-cli/internal/client"
+	"github.com/proencaj/orthanc-cli/internal/config"
+	"github.com/spf13/cobra"
+)
+
+// clientGetter is a function type that returns an Orthanc client
+var clientGetter func() (*client.Client, error)
+
+// SetClientGetter sets the function to get the Orthanc client
+func SetClientGetter(getter func() (*client.Client, error)) {
+	clientGetter = getter
+}
+
+// getClient returns the Orthanc client using the configured getter
+func getClient() (*client.Client, error) {
+	if clientGetter != nil {
+		return clientGetter()
+	}
+	// Fallback: try to load config from default location
+	cfg, err := config.LoadConfig("")
+	if err != nil {
+		return nil, err
+	}
+	return client.NewClient(cfg)
+}
+
+// shouldUseJSON checks if JSON output is enabled in config
+func shouldUseJSON() bool {
+	cfg, err := config.LoadConfig("")
+	if err != nil {
+		return false
+	}
+	return cfg.Output.JSON
+}
+
+// Plugin IDs the CLI knows about
+const (
+	pluginDicomWeb      = "dicom-web"
+	pluginWorklists     = "worklists"
+	pluginTransfers     = "transfers"
+	pluginAuthorization = "authorization"
+	pluginExplorer2     = "orthanc-explorer-2"
+	pluginStone         = "stone-webviewer"
+	pluginGdcm          = "gdcm"
+)
+
+// pluginFeature is something a plugin changes for the CLI: a command or
+// flag it enables, or a behaviour of the server
+type pluginFeature struct {
+	Command     string `json:"Command,omitempty"`
+	Description string `json:"Description"`
+}
+
+// pluginFeatures describes what known plugins change for the CLI
+var pluginFeatures = map[string][]pluginFeature{
+	pluginDicomWeb: {
+		{"dicomweb", "QIDO-RS, WADO-RS and STOW-RS operations"},
+		{"servers", "DICOMweb server management"},
+		{"sync --method dicomweb", "Sending resources to DICOMweb servers"},
+		{"health --checks dicomweb", "Reachability of DICOMweb servers"},
+	},
+	pluginWorklists: {
+		{"worklists", "Modality worklist management"},
+		{"", "Modality worklists answered to the C-FIND requests of modalities"},
+	},
+	pluginTransfers: {
+		{"", "Accelerated transfers between Orthanc peers; sync --method peer uses the built-in peer store"},
+	},
+	pluginAuthorization: {
+		{"", "Access control by an authorization service: commands may be rejected as forbidden (exit code 5)"},
+	},
+	pluginExplorer2: {
+		{"plugins explorer", "Orthanc Explorer 2 URL"},
+	},
+	pluginStone: {
+		{"plugins explorer", "Stone Web Viewer URL, for a study with --study"},
+	},
+	pluginGdcm: {
+		{"--transcode", "JPEG 2000 transfer syntaxes for server-side transcoding"},
+	},
+}
+
+// NewPluginsCommand creates the plugins command with all subcommands
+func NewPluginsCommand() *cobra.Command {
+	pluginsCmd := &cobra.Command{
+		Use:   "plugins",
+		Short: "Inspect the plugins of the Orthanc server",
+		Long: `Inspect the plugins loaded by the Orthanc server: their version and
+description, the CLI features they enable, and the URLs of the web viewers
+they provide.`,
+	}
+
+	// Add subcommands
+	pluginsCmd.AddCommand(NewListCommand())
+	pluginsCmd.AddCommand(NewGetCommand())
+	pluginsCmd.AddCommand(NewExplorerCommand())
+
+	return pluginsCmd
+}
+
+// pluginReport is a plugin with the CLI features it enables and, if its
+// details could not be read, the error
+type pluginReport struct {
+	*client.Plugin
+	URL      string          `json:"URL,omitempty"`
+	Features []pluginFeature `json:"Features"`
+	Error    string          `json:"Error,omitempty"`
+}
+
+// describePlugin reads the details of a plugin. On failure, the report
+// holds the plugin ID and the error.
+func describePlugin(c *client.Client, id string) (*pluginReport, error) {
+	report := &pluginReport{Plugin: &client.Plugin{ID: id}, Features: pluginFeatures[id]}
+	if report.Features == nil {
+		report.Features = []pluginFeature{}
+	}
+
+	plugin, err := c.GetPlugin(id)
+	if err != nil {
+		report.Error = err.Error()
+		return report, err
+	}
+	report.Plugin = plugin
+
+	if report.URL, err = c.PluginURL(plugin); err != nil {
+		report.Error = err.Error()
+		return report, err
+	}
+	return report, nil
+}
@@ -1,28 +1,62 @@
 package cmd
 
 import (
+	"encoding/json"
+	"errors"
 	"fmt"
 	"os"
+	"strings"
 
+	"github.com/proencaj/orthanc-cli/internal/audit"
 	"github.com/proencaj/orthanc-cli/internal/client"
+	"github.com/proencaj/orthanc-cli/internal/clierr"
 	configCmd "github.com/proencaj/orthanc-cli/internal/commands/config"
 	"github.com/proencaj/orthanc-cli/internal/config"
+	"github.com/proencaj/orthanc-cli/internal/guard"
 	"github.com/spf13/cobra"
 )
 
 var (
-	cfgFile string
-	cfg     *config.Config
+	cfgFile   string
+	debugMode string
+	curlMode  bool
+	cfg       *config.Config
+
+	// auditRecorder collects the changes sent to the server for the audit log
+	auditRecorder = audit.NewRecorder()
 )
 
 // rootCmd represents the base command when called without any subcommands
 var rootCmd = &cobra.Command{
-	Use:   "orthanc",
-	Short: "A CLI tool to interact with Orthanc DICOM servers",
-	SilenceUsage: true,
+	Use:           "orthanc",
+	Short:         "A CLI tool to interact with Orthanc DICOM servers",
+	SilenceUsage:  true,
+	SilenceErrors: true,
 	Long: `orthanc is a command-line interface for managing and querying
 Orthanc DICOM servers. It provides commands to interact with instances,
-studies, series, patients, and other Orthanc resources.`,
+studies, series, patients, and other Orthanc resources.
+
+Exit codes:
+  0   Success
+  1   Unclassified error
+  2   Validation error (invalid arguments, flags, input or configuration)
+  3   Resource not found
+  4   Unauthorized (authentication failed)
+  5   Forbidden
+  6   Conflict
+  7   Network error (server unreachable)
+  8   Timeout
+  9   Server error (HTTP 5xx)
+  10  Partial failure (some items of a batch operation failed)
+  11  Unsupported (the server lacks a required version or plugin)
+
+When --json is used (or output.json is set), errors are also reported as a
+JSON object on stderr.
+
+Contexts can be marked read-only, which rejects every command that changes
+the server, or protected, which requires typing the context name before
+destructive commands and refuses --force unless ORTHANC_ALLOW_DESTRUCTIVE=1
+is set (see 'orthanc config set').`,
 	PersistentPreRunE: func(cmd *cobra.Command, args []string) error {
 		// Initialize configuration
 		var err error
@@ -30,21 +64,134 @@ studies, series, patients, and other Orthanc resources.`,
 		if err != nil {
 			return fmt.Errorf("failed to load config: %w", err)
 		}
-		return nil
+
+		// Command-line debug flags take precedence over ORTHANC_DEBUG/ORTHANC_CURL
+		if cmd.Flags().Changed("debug") {
+			cfg.Debug.Mode = debugMode
+		}
+		if cmd.Flags().Changed("curl") {
+			cfg.Debug.Curl = curlMode
+		}
+		if _, err := client.ParseDebugLevel(cfg.Debug.Mode); err != nil {
+			return clierr.Wrap(clierr.KindValidation, err)
+		}
+
+		// Only commands that change a server are audited. Read-only commands do
+		// not record their requests, which long-running ones such as
+		// 'metrics serve' would otherwise accumulate until they exit.
+		if guard.Effect(cmd, args) == "" {
+			client.SetRequestObserver(nil)
+		}
+
+		// Enforce the read-only and protected settings of the current context
+		return guard.Check(cmd, args, cfg)
 	},
 }
 
 // Execute adds all child commands to the root command and sets flags appropriately.
 // This is called by main.main(). It only needs to happen once to the rootCmd.
 func Execute() {
-	if err := rootCmd.Execute(); err != nil {
-		os.Exit(1)
+	// Argument and flag errors detected by cobra are validation errors
+	rootCmd.SetFlagErrorFunc(func(c *cobra.Command, err error) error {
+		return clierr.Wrap(clierr.KindValidation, err)
+	})
+	wrapArgsValidation(rootCmd)
+
+	command, err := rootCmd.ExecuteC()
+	recordAudit(command, err)
+	if err != nil {
+		// Unknown subcommands are reported by cobra before any validator runs
+		if strings.HasPrefix(err.Error(), "unknown command") {
+			err = clierr.Wrap(clierr.KindValidation, err)
+		}
+		reportError(command, err)
+		os.Exit(clierr.ExitCode(err))
+	}
+}
+
+// recordAudit appends commands that change the server to the audit log,
+// whatever their outcome
+func recordAudit(command *cobra.Command, err error) {
+	if command == nil || guard.Effect(command, command.Flags().Args()) == "" {
+		return
+	}
+	if auditErr := audit.Append(auditRecorder.Entry(command, cfg, err)); auditErr != nil {
+		fmt.Fprintf(os.Stderr, "Warning: %v\n", auditErr)
 	}
 }
 
+// wrapArgsValidation tags positional argument errors of every command as validation errors
+func wrapArgsValidation(command *cobra.Command) {
+	if validate := command.Args; validate != nil {
+		command.Args = func(c *cobra.Command, args []string) error {
+			return clierr.Wrap(clierr.KindValidation, validate(c, args))
+		}
+	}
+	for _, child := range command.Commands() {
+		wrapArgsValidation(child)
+	}
+}
+
+// errorReport is the machine-readable error written to stderr in JSON mode
+type errorReport struct {
+	Error errorDetails `json:"error"`
+}
+
+type errorDetails struct {
+	Kind     clierr.Kind `json:"kind"`
+	Message  string      `json:"message"`
+	Status   int         `json:"status,omitempty"`
+	ExitCode int         `json:"exit_code"`
+}
+
+// reportError prints err to stderr, as JSON if JSON output was requested
+func reportError(command *cobra.Command, err error) {
+	// Commands returning an exit status have already reported their outcome
+	var status *clierr.Status
+	if errors.As(err, &status) {
+		return
+	}
+
+	if !wantsJSON(command) {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		return
+	}
+
+	report := errorReport{
+		Error: errorDetails{
+			Kind:     clierr.KindOf(err),
+			Message:  err.Error(),
+			Status:   clierr.StatusOf(err),
+			ExitCode: clierr.ExitCode(err),
+		},
+	}
+	data, marshalErr := json.MarshalIndent(report, "", "  ")
+	if marshalErr != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		return
+	}
+	fmt.Fprintln(os.Stderr, string(data))
+}
+
+// wantsJSON reports whether the command was run with --json or output.json is enabled
+func wantsJSON(command *cobra.Command) bool {
+	if command != nil {
+		if flag := command.Flags().Lookup("json"); flag != nil && flag.Value.String() == "true" {
+			return true
+		}
+	}
+	return cfg != nil && cfg.Output.JSON
+}
+
 func init() {
 	// Global flags
 	rootCmd.PersistentFlags().StringVar(&cfgFile, "config", "", "config file (default is $HOME/.orthanc-cli.yaml)")
+	rootCmd.PersistentFlags().StringVar(&debugMode, "debug", "", "trace HTTP requests to stderr (--debug, or --debug=body to also dump bodies; env ORTHANC_DEBUG)")
+	rootCmd.PersistentFlags().Lookup("debug").NoOptDefVal = "true"
+	rootCmd.PersistentFlags().BoolVar(&curlMode, "curl", false, "print the equivalent curl command for each HTTP request to stderr")
+
+	// Report the requests of every client to the audit log
+	client.SetRequestObserver(auditRecorder.Observe)
 
 	// Register subcommands
 	rootCmd.AddCommand(configCmd.NewConfigCommand())
@@ -63,7 +210,21 @@ func GetConfig() *config.Config {
 // GetClient creates a new Orthanc client using the current configuration
 func GetClient() (*client.Client, error) {
 	if cfg == nil {
-		return nil, fmt.Errorf("configuration not loaded")
+		return nil, clierr.Validation("configuration not loaded")
 	}
 	return client.NewClient(cfg)
 }
+
+// RecordTransfer records the source and destination contexts of a command
+// in its audit log entry
+func RecordTransfer(source, destination string) {
+	auditRecorder.SetTransfer(source, destination)
+}
+
+// GetClientForContext creates a new Orthanc client for a named context
+func GetClientForContext(name string) (*client.Client, error) {
+	if cfg == nil {
+		return nil, clierr.Validation("configuration not loaded")
+	}
+	return client.NewClientForContext(cfg, name)
+}
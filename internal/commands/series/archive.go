@@ -3,15 +3,24 @@ package series
 import (
 	"fmt"
 	"io"
+	"net/http"
 	"os"
 	"path/filepath"
 
+	"github.com/proencaj/gorthanc/types"
+	"github.com/proencaj/orthanc-cli/internal/batch"
+	"github.com/proencaj/orthanc-cli/internal/download"
+	"github.com/proencaj/orthanc-cli/internal/parallel"
 	"github.com/spf13/cobra"
 )
 
 // ArchiveFlags holds the flags for the archive command
 type ArchiveFlags struct {
-	output string
+	output    string
+	transcode string
+	retries   int
+	verify    bool
+	parallel  int
 }
 
 // NewArchiveCommand creates the series archive command
@@ -19,9 +28,14 @@ func NewArchiveCommand() *cobra.Command {
 	flags := &ArchiveFlags{}
 
 	command := &cobra.Command{
-		Use:   "archive <series-id>",
+		Use:   "archive <series-id>...",
 		Short: "Download and archive a series from the Orthanc server",
-		Long:  `Download a series as a ZIP archive from the Orthanc server and save it to disk.`,
+		Long: `Download a series as a ZIP archive from the Orthanc server and save it to disk.
+
+The archive is written to a temporary file and only moved into place once it
+is complete and its ZIP directory has been checked, so an interrupted download
+never leaves a truncated file behind. Interrupted downloads are retried, and a
+progress bar is shown when stderr is a terminal.`,
 		Example: `  # Archive a series to current directory
   orthanc series archive abc123
 
@@ -29,62 +43,113 @@ func NewArchiveCommand() *cobra.Command {
   orthanc series archive abc123 --output /path/to/series.zip
 
   # Archive a series to a specific directory
-  orthanc series archive abc123 --output /path/to/directory/`,
-		Args: cobra.ExactArgs(1),
+  orthanc series archive abc123 --output /path/to/directory/
+
+  # Transcode to Explicit VR Little Endian for viewers without JPEG2000 support
+  orthanc series archive abc123 --transcode explicit-little
+
+  # Check that the archive contains every instance of the series
+  orthanc series archive abc123 --verify
+
+  # Archive several series into a directory, one ZIP file each
+  orthanc series list | orthanc series archive - --output archives/`,
+		Args: cobra.MinimumNArgs(1),
 		RunE: func(c *cobra.Command, args []string) error {
-			return runArchive(args[0], flags)
+			return runArchive(args, flags)
 		},
 	}
 
 	// Add flags
-	command.Flags().StringVarP(&flags.output, "output", "o", "", "Output path (file or directory, defaults to current directory)")
+	command.Flags().StringVarP(&flags.output, "output", "o", "", "Output path (file or directory, defaults to current directory; a directory for several series)")
+	command.Flags().IntVar(&flags.retries, "retries", download.DefaultRetries, "Number of retries if the download is interrupted")
+	command.Flags().BoolVar(&flags.verify, "verify", false, "Check that the archive contains one DICOM file per instance")
+	command.Flags().IntVar(&flags.parallel, "parallel", parallel.DefaultWorkers, "Number of archives downloaded in parallel")
+	command.Flags().StringVar(&flags.transcode, "transcode", "", "Transcode to a transfer syntax UID or alias (e.g. explicit-little, jpeg2000-lossless, jpeg-baseline)")
 
 	return command
 }
 
-func runArchive(seriesID string, flags *ArchiveFlags) error {
+func runArchive(args []string, flags *ArchiveFlags) error {
+	seriesIDs, err := batch.ReadIDs(args)
+	if err != nil {
+		return err
+	}
+	output := batch.OutputDir(flags.output, len(seriesIDs))
+
 	// Get the Orthanc client
 	client, err := getClient()
 	if err != nil {
 		return fmt.Errorf("failed to create client: %w", err)
 	}
 
-	// Download the series archive
-	fmt.Printf("Downloading series archive: %s\n", seriesID)
-	resp, err := client.DownloadSeriesArchive(seriesID)
-	if err != nil {
-		return fmt.Errorf("failed to download series archive: %w", err)
+	// Resolve the transfer syntax alias to its UID
+	transferSyntax := ""
+	if flags.transcode != "" {
+		transferSyntax, err = client.ResolveTransferSyntax(flags.transcode)
+		if err != nil {
+			return err
+		}
 	}
-	defer resp.Body.Close()
 
-	// Check response status
-	if resp.StatusCode != 200 {
-		return fmt.Errorf("failed to download series archive: HTTP %d", resp.StatusCode)
+	// Progress bars of parallel downloads would overwrite each other
+	var progress io.Writer
+	if len(seriesIDs) > 1 {
+		progress = io.Discard
 	}
 
-	// Determine the output path
-	outputPath, err := determineOutputPath(flags.output, seriesID)
-	if err != nil {
-		return fmt.Errorf("failed to determine output path: %w", err)
-	}
+	// Download the archives in parallel and report them in order
+	results := make([]*download.Result, len(seriesIDs))
+	expectedFiles := make([]int, len(seriesIDs))
+	return batch.Run(seriesIDs, flags.parallel, func(i int) error {
+		// Resolve DICOM UIDs and prefixed identifiers to the Orthanc ID
+		seriesID, err := client.ResolveID(types.ResourceLevelSeries, seriesIDs[i])
+		if err != nil {
+			return err
+		}
 
-	// Create the output file
-	outFile, err := os.Create(outputPath)
-	if err != nil {
-		return fmt.Errorf("failed to create output file: %w", err)
-	}
-	defer outFile.Close()
+		// Determine the output path
+		outputPath, err := determineOutputPath(output, seriesID)
+		if err != nil {
+			return fmt.Errorf("failed to determine output path: %w", err)
+		}
 
-	// Copy the response body to the file
-	written, err := io.Copy(outFile, resp.Body)
-	if err != nil {
-		return fmt.Errorf("failed to write archive to file: %w", err)
-	}
+		// Count the instances to compare with the archive content
+		if flags.verify {
+			statistics, err := client.GetSeriesStatistics(seriesID)
+			if err != nil {
+				return fmt.Errorf("failed to get series statistics: %w", err)
+			}
+			expectedFiles[i] = statistics.CountInstances
+		}
 
-	fmt.Printf("Successfully downloaded series archive to: %s\n", outputPath)
-	fmt.Printf("Size: %.2f MB\n", float64(written)/(1024*1024))
+		archivePath, err := client.TranscodedPath("series/"+seriesID+"/archive", transferSyntax)
+		if err != nil {
+			return err
+		}
 
-	return nil
+		// Download the series archive
+		if len(seriesIDs) == 1 {
+			fmt.Printf("Downloading series archive: %s\n", seriesID)
+		}
+		results[i], err = download.ToFile(outputPath, func(offset int64) (*http.Response, error) {
+			return client.Download(archivePath, offset)
+		}, download.Options{
+			Retries:  flags.retries,
+			Verify:   download.VerifyZip(expectedFiles[i]),
+			Progress: progress,
+		})
+		if err != nil {
+			return fmt.Errorf("failed to download series archive: %w", err)
+		}
+		return nil
+	}, func(i int) error {
+		fmt.Printf("Successfully downloaded series archive to: %s\n", results[i].Path)
+		fmt.Printf("Size: %.2f MB\n", float64(results[i].Size)/(1024*1024))
+		if flags.verify {
+			fmt.Printf("Verified: %d DICOM file(s)\n", expectedFiles[i])
+		}
+		return nil
+	})
 }
 
 // determineOutputPath determines the final output path for the archive
@@ -4,11 +4,15 @@ import (
 	"encoding/json"
 	"fmt"
 
+	"github.com/proencaj/gorthanc/types"
+	"github.com/proencaj/orthanc-cli/internal/batch"
+	"github.com/proencaj/orthanc-cli/internal/parallel"
 	"github.com/spf13/cobra"
 )
 
 // GetFlags holds the flags for the get command
 type GetFlags struct {
+	parallel   int
 	jsonOutput bool
 }
 
@@ -17,27 +21,36 @@ func NewGetCommand() *cobra.Command {
 	flags := &GetFlags{}
 
 	command := &cobra.Command{
-		Use:   "get <series-id>",
+		Use:   "get <series-id>...",
 		Short: "Get detailed information about a series",
 		Long:  `Retrieve and display detailed information about a specific series from the Orthanc server.`,
 		Example: `  # Get series details
   orthanc series get abc123
 
   # Get series details in JSON format
-  orthanc series get abc123 --json`,
-		Args: cobra.ExactArgs(1),
+  orthanc series get abc123 --json
+
+  # Get several series, reading IDs from stdin
+  orthanc series list | orthanc series get - --json`,
+		Args: cobra.MinimumNArgs(1),
 		RunE: func(c *cobra.Command, args []string) error {
-			return runGet(args[0], flags)
+			return runGet(args, flags)
 		},
 	}
 
 	// Add flags
+	command.Flags().IntVar(&flags.parallel, "parallel", parallel.DefaultWorkers, "Number of resources fetched in parallel")
 	command.Flags().BoolVar(&flags.jsonOutput, "json", false, "Output in JSON format")
 
 	return command
 }
 
-func runGet(seriesID string, flags *GetFlags) error {
+func runGet(args []string, flags *GetFlags) error {
+	seriesIDs, err := batch.ReadIDs(args)
+	if err != nil {
+		return err
+	}
+
 	// Get the Orthanc client
 	client, err := getClient()
 	if err != nil {
@@ -47,13 +60,26 @@ func runGet(seriesID string, flags *GetFlags) error {
 	// Check if JSON output should be used (flag or config)
 	jsonOutput := flags.jsonOutput || shouldUseJSON()
 
-	// Fetch series details
-	series, err := client.GetSeriesDetail(seriesID)
-	if err != nil {
-		return fmt.Errorf("failed to fetch series details: %w", err)
-	}
+	// Fetch the series in parallel and display them in order
+	seriesList := make([]*types.Series, len(seriesIDs))
+	return batch.Run(seriesIDs, flags.parallel, func(i int) error {
+		// Resolve DICOM UIDs and prefixed identifiers to the Orthanc ID
+		seriesID, err := client.ResolveID(types.ResourceLevelSeries, seriesIDs[i])
+		if err != nil {
+			return err
+		}
+
+		seriesList[i], err = client.GetSeriesDetail(seriesID)
+		if err != nil {
+			return fmt.Errorf("failed to fetch series details: %w", err)
+		}
+		return nil
+	}, func(i int) error {
+		return displaySeries(seriesList[i], jsonOutput)
+	})
+}
 
-	// Display the series
+func displaySeries(series *types.Series, jsonOutput bool) error {
 	if jsonOutput {
 		data, err := json.MarshalIndent(series, "", "  ")
 		if err != nil {
@@ -4,6 +4,7 @@ import (
 	"encoding/json"
 	"fmt"
 
+	"github.com/proencaj/gorthanc/types"
 	"github.com/spf13/cobra"
 )
 
@@ -44,6 +45,12 @@ func runListInstances(seriesID string, flags *ListInstancesFlags) error {
 		return fmt.Errorf("failed to create client: %w", err)
 	}
 
+	// Resolve DICOM UIDs and prefixed identifiers to the Orthanc ID
+	seriesID, err = client.ResolveID(types.ResourceLevelSeries, seriesID)
+	if err != nil {
+		return err
+	}
+
 	// Check if JSON output should be used (flag or config)
 	jsonOutput := flags.jsonOutput || shouldUseJSON()
 
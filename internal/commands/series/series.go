@@ -41,12 +41,21 @@ func NewSeriesCommand() *cobra.Command {
 	seriesCmd := &cobra.Command{
 		Use:   "series",
 		Short: "Manage Orthanc series",
-		Long:  `Query, list, and manage DICOM series in the Orthanc server.`,
+		Long: `Query, list, and manage DICOM series in the Orthanc server.
+
+Series can be given by Orthanc ID, by SeriesInstanceUID, or as
+uid:<SeriesInstanceUID>, acc:<AccessionNumber> or pid:<PatientID>.
+Identifiers that match several resources are rejected as ambiguous.
+
+Commands that take series also accept several IDs, or - to read them from
+stdin one per line, and process them in parallel (see --parallel). If some of
+them fail, the others are still processed and the exit code is 10.`,
 	}
 
 	// Add subcommands
 	seriesCmd.AddCommand(NewListCommand())
 	seriesCmd.AddCommand(NewGetCommand())
+	seriesCmd.AddCommand(NewStatisticsCommand())
 	seriesCmd.AddCommand(NewRemoveCommand())
 	seriesCmd.AddCommand(NewAnonymizeCommand())
 	seriesCmd.AddCommand(NewArchiveCommand())
@@ -0,0 +1,103 @@
+package series
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/proencaj/gorthanc/types"
+	"github.com/proencaj/orthanc-cli/internal/batch"
+	"github.com/proencaj/orthanc-cli/internal/parallel"
+	"github.com/spf13/cobra"
+)
+
+// StatisticsFlags holds the flags for the statistics command
+type StatisticsFlags struct {
+	parallel   int
+	jsonOutput bool
+}
+
+// NewStatisticsCommand creates the series statistics command
+func NewStatisticsCommand() *cobra.Command {
+	flags := &StatisticsFlags{}
+
+	command := &cobra.Command{
+		Use:   "statistics <series-id>...",
+		Short: "Show the storage usage of a series",
+		Long:  `Show the number of instances and the disk and uncompressed sizes of a series.`,
+		Example: `  # Show the statistics of a series
+  orthanc series statistics abc123
+
+  # Show the statistics of several series, reading IDs from stdin
+  orthanc series list | orthanc series statistics - --json`,
+		Args: cobra.MinimumNArgs(1),
+		RunE: func(c *cobra.Command, args []string) error {
+			return runStatistics(args, flags)
+		},
+	}
+
+	// Add flags
+	command.Flags().IntVar(&flags.parallel, "parallel", parallel.DefaultWorkers, "Number of resources fetched in parallel")
+	command.Flags().BoolVar(&flags.jsonOutput, "json", false, "Output in JSON format")
+
+	return command
+}
+
+func runStatistics(args []string, flags *StatisticsFlags) error {
+	seriesIDs, err := batch.ReadIDs(args)
+	if err != nil {
+		return err
+	}
+
+	// Get the Orthanc client
+	client, err := getClient()
+	if err != nil {
+		return fmt.Errorf("failed to create client: %w", err)
+	}
+
+	// Check if JSON output should be used (flag or config)
+	jsonOutput := flags.jsonOutput || shouldUseJSON()
+
+	// Fetch the statistics in parallel and display them in order
+	statistics := make([]*types.Statistics, len(seriesIDs))
+	return batch.Run(seriesIDs, flags.parallel, func(i int) error {
+		// Resolve DICOM UIDs and prefixed identifiers to the Orthanc ID
+		seriesID, err := client.ResolveID(types.ResourceLevelSeries, seriesIDs[i])
+		if err != nil {
+			return err
+		}
+
+		statistics[i], err = client.GetSeriesStatistics(seriesID)
+		if err != nil {
+			return fmt.Errorf("failed to get series statistics: %w", err)
+		}
+		seriesIDs[i] = seriesID
+		return nil
+	}, func(i int) error {
+		return displayStatistics(seriesIDs[i], statistics[i], jsonOutput)
+	})
+}
+
+// statisticsReport is the JSON output of the statistics command, with the
+// Orthanc ID of the series
+type statisticsReport struct {
+	ID string `json:"ID"`
+	*types.Statistics
+}
+
+func displayStatistics(seriesID string, statistics *types.Statistics, jsonOutput bool) error {
+	if jsonOutput {
+		data, err := json.MarshalIndent(&statisticsReport{ID: seriesID, Statistics: statistics}, "", "  ")
+		if err != nil {
+			return fmt.Errorf("failed to marshal JSON: %w", err)
+		}
+		fmt.Println(string(data))
+		return nil
+	}
+
+	// Raw text output
+	fmt.Printf("OrthancSeriesID: %s\n", seriesID)
+	fmt.Printf("CountInstances: %d\n", statistics.CountInstances)
+	fmt.Printf("DiskSize: %s bytes (%d MB)\n", statistics.DiskSize, statistics.DiskSizeMB)
+	fmt.Printf("UncompressedSize: %s bytes (%d MB)\n", statistics.UncompressedSize, statistics.UncompressedSizeMB)
+	return nil
+}
@@ -7,17 +7,19 @@ import (
 
 	"github.com/proencaj/gorthanc"
 	"github.com/proencaj/gorthanc/types"
+	"github.com/proencaj/orthanc-cli/internal/clierr"
+	"github.com/proencaj/orthanc-cli/internal/guard"
 	"github.com/spf13/cobra"
 )
 
 // CreateFlags holds the flags for the create command
 type CreateFlags struct {
-	file                           string
-	url                            string
-	username                       string
-	password                       string
-	hasDelete                      bool
-	chunkedTransfers               bool
+	file                             string
+	url                              string
+	username                         string
+	password                         string
+	hasDelete                        bool
+	chunkedTransfers                 bool
 	hasWadoRsUniversalTransferSyntax bool
 }
 
@@ -49,7 +51,8 @@ func NewCreateCommand() *cobra.Command {
     --has-delete \
     --chunked-transfers \
     --has-wado-rs-universal-transfer-syntax`,
-		Args: cobra.ExactArgs(1),
+		Annotations: map[string]string{guard.Annotation: guard.Mutating},
+		Args:        cobra.ExactArgs(1),
 		RunE: func(c *cobra.Command, args []string) error {
 			return runCreate(args[0], flags)
 		},
@@ -85,7 +88,7 @@ func runCreate(serverName string, flags *CreateFlags) error {
 	} else {
 		// Validate required fields when not using file
 		if flags.url == "" {
-			return fmt.Errorf("when not using --file, the --url flag is required")
+			return clierr.Validation("when not using --file, the --url flag is required")
 		}
 
 		// Build request from flags
@@ -123,7 +126,7 @@ func readServerFromFile(filePath string) (*types.DicomWebServerCreateRequest, er
 
 	// Validate required fields
 	if request.Url == "" {
-		return nil, fmt.Errorf("JSON file must contain Url field")
+		return nil, clierr.Validation("JSON file must contain Url field")
 	}
 
 	return &request, nil
@@ -5,6 +5,7 @@ import (
 	"fmt"
 
 	"github.com/proencaj/gorthanc/types"
+	"github.com/proencaj/orthanc-cli/internal/clierr"
 	"github.com/spf13/cobra"
 )
 
@@ -56,7 +57,7 @@ func runGet(serverName string, flags *GetFlags) error {
 
 	server, ok := servers[serverName]
 	if !ok {
-		return fmt.Errorf("server '%s' not found", serverName)
+		return clierr.NotFound("server '%s' not found", serverName)
 	}
 
 	return displayServer(serverName, &server, jsonOutput)
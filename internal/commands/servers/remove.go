@@ -6,6 +6,7 @@ import (
 	"os"
 	"strings"
 
+	"github.com/proencaj/orthanc-cli/internal/guard"
 	"github.com/spf13/cobra"
 )
 
@@ -27,6 +28,10 @@ func NewRemoveCommand() *cobra.Command {
 
   # Remove a server without confirmation
   orthanc servers remove my-pacs --force`,
+		Annotations: map[string]string{
+			guard.Annotation:  guard.Destructive,
+			guard.ConfirmFlag: "force",
+		},
 		Args: cobra.ExactArgs(1),
 		RunE: func(c *cobra.Command, args []string) error {
 			return runRemove(args[0], flags)
@@ -46,8 +51,8 @@ func runRemove(serverName string, flags *RemoveFlags) error {
 		return fmt.Errorf("failed to create client: %w", err)
 	}
 
-	// If not using force flag, prompt for confirmation
-	if !flags.force {
+	// Prompt for confirmation, unless forced or already confirmed for a protected context
+	if !flags.force && !guard.Confirmed() {
 		confirmed, err := confirmRemoval(serverName)
 		if err != nil {
 			return fmt.Errorf("failed to get confirmation: %w", err)
@@ -1,6 +1,7 @@
 package servers
 
 import (
+	"github.com/proencaj/orthanc-cli/internal/guard"
 	"github.com/spf13/cobra"
 )
 
@@ -23,7 +24,8 @@ func NewUpdateCommand() *cobra.Command {
     --url https://pacs.example.com/dicom-web \
     --username newadmin \
     --password newsecret`,
-		Args: cobra.ExactArgs(1),
+		Annotations: map[string]string{guard.Annotation: guard.Mutating},
+		Args:        cobra.ExactArgs(1),
 		RunE: func(c *cobra.Command, args []string) error {
 			// Reuse the same logic as create since the API endpoint is the same
 			return runCreate(args[0], flags)
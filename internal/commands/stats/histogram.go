@@ -0,0 +1,259 @@
+package stats
+
+import (
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/proencaj/gorthanc/types"
+	"github.com/proencaj/orthanc-cli/internal/client"
+	"github.com/proencaj/orthanc-cli/internal/clierr"
+	"github.com/proencaj/orthanc-cli/internal/helpers"
+	"github.com/proencaj/orthanc-cli/internal/parallel"
+	"github.com/spf13/cobra"
+)
+
+// histogramWidth is the width of the bars of the histogram
+const histogramWidth = 30
+
+// unknownBucket holds the series without a modality or study date
+const unknownBucket = "unknown"
+
+// HistogramFlags holds the flags for the histogram command
+type HistogramFlags struct {
+	by         string
+	tags       map[string]string
+	parallel   int
+	jsonOutput bool
+}
+
+// histogramBucket is the storage usage of a modality or month
+type histogramBucket struct {
+	Key            string `json:"Key"`
+	CountStudies   int    `json:"CountStudies"`
+	CountSeries    int    `json:"CountSeries"`
+	CountInstances int    `json:"CountInstances"`
+	DiskSize       int64  `json:"DiskSize"`
+	studies        map[string]bool
+}
+
+// NewHistogramCommand creates the stats histogram command
+func NewHistogramCommand() *cobra.Command {
+	flags := &HistogramFlags{}
+
+	command := &cobra.Command{
+		Use:   "histogram",
+		Short: "Break storage usage down by modality or month",
+		Long: `Break the storage usage of the server down by modality (of the series) or by
+month (of the study date), with the number of studies, series and instances
+and the disk size of each.
+
+Series are found with tools find and the statistics of each are fetched, one
+request per series; use --tag to restrict the series considered on large
+servers.`,
+		Example: `  # Storage usage by modality
+  orthanc stats histogram --by modality
+
+  # Storage usage by month over 2024
+  orthanc stats histogram --by month --tag StudyDate=20240101-20241231
+
+  # Output in JSON format
+  orthanc stats histogram --by month --json`,
+		Args: cobra.NoArgs,
+		RunE: func(c *cobra.Command, args []string) error {
+			return runHistogram(flags)
+		},
+	}
+
+	// Add flags
+	command.Flags().StringVar(&flags.by, "by", "modality", "Break down by: modality, month")
+	command.Flags().StringToStringVar(&flags.tags, "tag", nil, "Only consider series matching a DICOM tag (can be specified multiple times)")
+	command.Flags().IntVar(&flags.parallel, "parallel", parallel.DefaultWorkers, "Number of parallel statistics requests")
+	command.Flags().BoolVar(&flags.jsonOutput, "json", false, "Output in JSON format")
+
+	return command
+}
+
+func runHistogram(flags *HistogramFlags) error {
+	if flags.by != "modality" && flags.by != "month" {
+		return clierr.Validation("invalid --by '%s', must be one of: modality, month", flags.by)
+	}
+	if flags.parallel < 1 {
+		return clierr.Validation("--parallel must be at least 1")
+	}
+
+	// Get the Orthanc client
+	client, err := getClient()
+	if err != nil {
+		return fmt.Errorf("failed to create client: %w", err)
+	}
+
+	// Check if JSON output should be used (flag or config)
+	jsonOutput := flags.jsonOutput || shouldUseJSON()
+
+	// Orthanc requires Query to be an object, even when no tag filter is given
+	if flags.tags == nil {
+		flags.tags = make(map[string]string)
+	}
+	request := &types.ToolsFindRequest{
+		Level:  types.ResourceLevelSeries,
+		Query:  flags.tags,
+		Expand: helpers.BoolPtr(true),
+	}
+	byMonth := flags.by == "month"
+	requestedTags := byMonth && supportsRequestedTags(client)
+	if requestedTags {
+		request.RequestedTags = []string{"StudyDate"}
+	}
+
+	results, err := client.FindExpanded(request)
+	if err != nil {
+		return fmt.Errorf("search failed: %w", err)
+	}
+
+	series := make([]*resourceUsage, len(results))
+	for i, result := range results {
+		series[i] = &resourceUsage{ID: result.ID, Tags: flattenTags(result.MainDicomTags, result.RequestedTags)}
+		series[i].Tags["ParentStudy"] = result.ParentStudy
+	}
+
+	// Servers without requested tags need the study date from each study
+	if byMonth && !requestedTags {
+		if err := fetchStudyDates(client, series, flags.parallel); err != nil {
+			return err
+		}
+	}
+
+	if err := fetchUsage(client, types.ResourceLevelSeries, series, flags.parallel); err != nil {
+		return err
+	}
+
+	buckets := map[string]*histogramBucket{}
+	for _, resource := range series {
+		key := bucketKey(resource, byMonth)
+		bucket, ok := buckets[key]
+		if !ok {
+			bucket = &histogramBucket{Key: key, studies: map[string]bool{}}
+			buckets[key] = bucket
+		}
+		bucket.studies[resource.Tags["ParentStudy"]] = true
+		bucket.CountSeries++
+		bucket.CountInstances += resource.CountInstances
+		bucket.DiskSize += resource.DiskSize
+	}
+
+	sorted := make([]*histogramBucket, 0, len(buckets))
+	for _, bucket := range buckets {
+		bucket.CountStudies = len(bucket.studies)
+		sorted = append(sorted, bucket)
+	}
+	sort.Slice(sorted, func(i, j int) bool {
+		// Months in order, modalities by decreasing usage, unknown last
+		if (sorted[i].Key == unknownBucket) != (sorted[j].Key == unknownBucket) {
+			return sorted[j].Key == unknownBucket
+		}
+		if byMonth || sorted[i].DiskSize == sorted[j].DiskSize {
+			return sorted[i].Key < sorted[j].Key
+		}
+		return sorted[i].DiskSize > sorted[j].DiskSize
+	})
+
+	if jsonOutput {
+		return printJSON(sorted)
+	}
+
+	if len(sorted) == 0 {
+		fmt.Println("No series found.")
+		return nil
+	}
+
+	var total, largest int64
+	for _, bucket := range sorted {
+		total += bucket.DiskSize
+		largest = max(largest, bucket.DiskSize)
+	}
+
+	fmt.Printf("%-10s  %-7s  %-7s  %-9s  %-10s  %-6s  %s\n", strings.ToUpper(flags.by), "STUDIES", "SERIES", "INSTANCES", "DISK", "SHARE", "")
+	for _, bucket := range sorted {
+		share, bar := 0.0, 0
+		if total > 0 {
+			share = 100 * float64(bucket.DiskSize) / float64(total)
+		}
+		if largest > 0 {
+			bar = int(float64(histogramWidth) * float64(bucket.DiskSize) / float64(largest))
+		}
+		fmt.Printf("%-10s  %-7d  %-7d  %-9d  %-10s  %5.1f%%  %s\n", bucket.Key, bucket.CountStudies, bucket.CountSeries,
+			bucket.CountInstances, helpers.FormatBytes(bucket.DiskSize), share, strings.Repeat("#", bar))
+	}
+
+	return nil
+}
+
+// supportsRequestedTags reports whether tools find can return the study date of series
+func supportsRequestedTags(c *client.Client) bool {
+	caps, err := c.Capabilities()
+	if err != nil {
+		// Unknown server capabilities: assume a recent server
+		return true
+	}
+	return caps.Supports(client.FeatureRequestedTags)
+}
+
+// fetchStudyDates sets the StudyDate tag of series from their parent studies
+func fetchStudyDates(c *client.Client, series []*resourceUsage, workers int) error {
+	var studyIDs []string
+	seen := map[string]bool{}
+	for _, resource := range series {
+		if id := resource.Tags["ParentStudy"]; id != "" && !seen[id] {
+			seen[id] = true
+			studyIDs = append(studyIDs, id)
+		}
+	}
+
+	fmt.Fprintf(os.Stderr, "Fetching the dates of %d studies, the server does not support requested tags\n", len(studyIDs))
+	dates := make([]string, len(studyIDs))
+	errs := parallel.ForEach(len(studyIDs), workers, func(i int) error {
+		study, err := c.GetStudy(studyIDs[i])
+		if err != nil {
+			return fmt.Errorf("failed to fetch study %s: %w", studyIDs[i], err)
+		}
+		dates[i] = study.MainDicomTags.StudyDate
+		return nil
+	})
+	for _, err := range errs {
+		if err != nil {
+			return err
+		}
+	}
+
+	byStudy := make(map[string]string, len(studyIDs))
+	for i, id := range studyIDs {
+		byStudy[id] = dates[i]
+	}
+	for _, resource := range series {
+		resource.Tags["StudyDate"] = byStudy[resource.Tags["ParentStudy"]]
+	}
+	return nil
+}
+
+// bucketKey returns the modality of a series, or the month of its study as YYYY-MM
+func bucketKey(resource *resourceUsage, byMonth bool) string {
+	if !byMonth {
+		if modality := strings.TrimSpace(resource.Tags["Modality"]); modality != "" {
+			return modality
+		}
+		return unknownBucket
+	}
+
+	date := strings.TrimSpace(resource.Tags["StudyDate"])
+	if len(date) < 6 {
+		return unknownBucket
+	}
+	for _, r := range date[:6] {
+		if r < '0' || r > '9' {
+			return unknownBucket
+		}
+	}
+	return date[:4] + "-" + date[4:6]
+}
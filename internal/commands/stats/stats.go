@@ -0,0 +1,212 @@
+package stats
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strconv"
+	"sync"
+
+	"github.com/proencaj/gorthanc/types"
+	"github.com/proencaj/orthanc-cli/internal/client"
+	"github.com/proencaj/orthanc-cli/internal/config"
+	"github.com/proencaj/orthanc-cli/internal/download"
+	"github.com/proencaj/orthanc-cli/internal/helpers"
+	"github.com/proencaj/orthanc-cli/internal/parallel"
+	"github.com/spf13/cobra"
+)
+
+// clientGetter is a function type that returns an Orthanc client
+var clientGetter func() (*client.Client, error)
+
+// SetClientGetter sets the function to get the Orthanc client
+func SetClientGetter(getter func() (*client.Client, error)) {
+	clientGetter = getter
+}
+
+// getClient returns the Orthanc client using the configured getter
+func getClient() (*client.Client, error) {
+	if clientGetter != nil {
+		return clientGetter()
+	}
+	// Fallback: try to load config from default location
+	cfg, err := config.LoadConfig("")
+	if err != nil {
+		return nil, err
+	}
+	return client.NewClient(cfg)
+}
+
+// shouldUseJSON checks if JSON output is enabled in config
+func shouldUseJSON() bool {
+	cfg, err := config.LoadConfig("")
+	if err != nil {
+		return false
+	}
+	return cfg.Output.JSON
+}
+
+// StatsFlags holds the flags for the stats command
+type StatsFlags struct {
+	jsonOutput bool
+}
+
+// statsReport is the JSON output of the stats command
+type statsReport struct {
+	*client.ServerStatistics
+	CompressionRatio float64 `json:"CompressionRatio"`
+}
+
+// NewStatsCommand creates the stats command with all subcommands
+func NewStatsCommand() *cobra.Command {
+	flags := &StatsFlags{}
+
+	command := &cobra.Command{
+		Use:   "stats",
+		Short: "Show resource counts and storage usage of the Orthanc server",
+		Long: `Show the number of patients, studies, series and instances stored by the
+Orthanc server, with their disk size, uncompressed size and the resulting
+storage compression ratio.
+
+Use 'stats top' to find the biggest resources and 'stats histogram' to break
+storage usage down by modality or month.`,
+		Example: `  # Show the server statistics
+  orthanc stats
+
+  # Show the server statistics in JSON format
+  orthanc stats --json`,
+		Args: cobra.NoArgs,
+		RunE: func(c *cobra.Command, args []string) error {
+			return runStats(flags)
+		},
+	}
+
+	// Add flags
+	command.Flags().BoolVar(&flags.jsonOutput, "json", false, "Output in JSON format")
+
+	// Add subcommands
+	command.AddCommand(NewTopCommand())
+	command.AddCommand(NewHistogramCommand())
+
+	return command
+}
+
+func runStats(flags *StatsFlags) error {
+	// Get the Orthanc client
+	client, err := getClient()
+	if err != nil {
+		return fmt.Errorf("failed to create client: %w", err)
+	}
+
+	// Check if JSON output should be used (flag or config)
+	jsonOutput := flags.jsonOutput || shouldUseJSON()
+
+	statistics, err := client.GetStatistics()
+	if err != nil {
+		return fmt.Errorf("failed to get statistics: %w", err)
+	}
+
+	diskSize := parseSize(statistics.TotalDiskSize)
+	uncompressedSize := parseSize(statistics.TotalUncompressedSize)
+	report := &statsReport{ServerStatistics: statistics}
+	if diskSize > 0 {
+		report.CompressionRatio = float64(uncompressedSize) / float64(diskSize)
+	}
+
+	if jsonOutput {
+		return printJSON(report)
+	}
+
+	fmt.Println("Orthanc Storage Statistics")
+	fmt.Println("==========================")
+	fmt.Println()
+
+	fmt.Printf("Patients:            %d\n", statistics.CountPatients)
+	fmt.Printf("Studies:             %d\n", statistics.CountStudies)
+	fmt.Printf("Series:              %d\n", statistics.CountSeries)
+	fmt.Printf("Instances:           %d\n", statistics.CountInstances)
+	fmt.Println()
+
+	fmt.Printf("Disk Size:           %s\n", helpers.FormatBytes(diskSize))
+	fmt.Printf("Uncompressed Size:   %s\n", helpers.FormatBytes(uncompressedSize))
+	if diskSize > 0 {
+		fmt.Printf("Compression Ratio:   %.2f:1 (%.1f%% saved)\n", report.CompressionRatio,
+			100*(1-float64(diskSize)/float64(max(uncompressedSize, diskSize))))
+	} else {
+		fmt.Printf("Compression Ratio:   n/a\n")
+	}
+
+	return nil
+}
+
+// resourceUsage is the storage usage of a resource
+type resourceUsage struct {
+	ID               string            `json:"ID"`
+	Tags             map[string]string `json:"MainDicomTags"`
+	CountInstances   int               `json:"CountInstances"`
+	DiskSize         int64             `json:"DiskSize"`
+	UncompressedSize int64             `json:"UncompressedSize"`
+}
+
+// fetchUsage fills in the statistics of resources, with at most workers
+// concurrent requests and a progress counter on terminals
+func fetchUsage(c *client.Client, level types.ResourceLevel, resources []*resourceUsage, workers int) error {
+	showProgress := download.IsTerminal(os.Stderr)
+	var mu sync.Mutex
+	done := 0
+	errs := parallel.ForEach(len(resources), workers, func(i int) error {
+		statistics, err := c.GetResourceStatistics(level, resources[i].ID)
+
+		mu.Lock()
+		defer mu.Unlock()
+		done++
+		if showProgress {
+			fmt.Fprintf(os.Stderr, "\rFetched statistics of %d/%d %s", done, len(resources), client.PluralLevelName(level))
+		}
+
+		if err != nil {
+			return fmt.Errorf("failed to get statistics of %s: %w", resources[i].ID, err)
+		}
+		resources[i].CountInstances = statistics.CountInstances
+		resources[i].DiskSize = parseSize(statistics.DiskSize)
+		resources[i].UncompressedSize = parseSize(statistics.UncompressedSize)
+		return nil
+	})
+	if showProgress && len(resources) > 0 {
+		fmt.Fprintln(os.Stderr)
+	}
+
+	for _, err := range errs {
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// flattenTags merges patient and resource tags into strings
+func flattenTags(sources ...map[string]interface{}) map[string]string {
+	tags := map[string]string{}
+	for _, source := range sources {
+		for name, value := range source {
+			tags[name] = fmt.Sprint(value)
+		}
+	}
+	return tags
+}
+
+// parseSize parses a size of the Orthanc API, which is a string of bytes
+func parseSize(value string) int64 {
+	size, _ := strconv.ParseInt(value, 10, 64)
+	return size
+}
+
+// printJSON prints a value as indented JSON
+func printJSON(value interface{}) error {
+	data, err := json.MarshalIndent(value, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal JSON: %w", err)
+	}
+	fmt.Println(string(data))
+	return nil
+}
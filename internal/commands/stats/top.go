@@ -0,0 +1,152 @@
+package stats
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/proencaj/gorthanc/types"
+	"github.com/proencaj/orthanc-cli/internal/clierr"
+	"github.com/proencaj/orthanc-cli/internal/helpers"
+	"github.com/proencaj/orthanc-cli/internal/parallel"
+	"github.com/spf13/cobra"
+)
+
+// summaryTags are the tags shown for each resource of top, by level
+var summaryTags = map[types.ResourceLevel][]string{
+	types.ResourceLevelPatient: {"PatientID", "PatientName"},
+	types.ResourceLevelStudy:   {"PatientID", "StudyDate", "StudyDescription"},
+	types.ResourceLevelSeries:  {"Modality", "SeriesDescription"},
+}
+
+// topKeys are the valid values of --by, with the value they sort on
+var topKeys = map[string]func(*resourceUsage) int64{
+	"disk-size":         func(r *resourceUsage) int64 { return r.DiskSize },
+	"uncompressed-size": func(r *resourceUsage) int64 { return r.UncompressedSize },
+	"instances":         func(r *resourceUsage) int64 { return int64(r.CountInstances) },
+}
+
+// TopFlags holds the flags for the top command
+type TopFlags struct {
+	by         string
+	level      string
+	limit      int
+	tags       map[string]string
+	parallel   int
+	jsonOutput bool
+}
+
+// NewTopCommand creates the stats top command
+func NewTopCommand() *cobra.Command {
+	flags := &TopFlags{}
+
+	command := &cobra.Command{
+		Use:   "top",
+		Short: "List the biggest patients, studies or series",
+		Long: `List the patients, studies or series using the most storage, by disk size,
+uncompressed size or number of instances.
+
+The statistics of every matching resource are fetched, one request each; use
+--tag to restrict the resources considered on large servers.`,
+		Example: `  # The 20 biggest studies
+  orthanc stats top --by disk-size --level Study --limit 20
+
+  # The series with the most instances in 2024
+  orthanc stats top --by instances --level Series --tag StudyDate=20240101-20241231
+
+  # The biggest patients, as JSON
+  orthanc stats top --level Patient --json`,
+		Args: cobra.NoArgs,
+		RunE: func(c *cobra.Command, args []string) error {
+			return runTop(flags)
+		},
+	}
+
+	// Add flags
+	command.Flags().StringVar(&flags.by, "by", "disk-size", "Sort by: disk-size, uncompressed-size, instances")
+	command.Flags().StringVar(&flags.level, "level", "Study", "Resource level (Patient, Study, Series)")
+	command.Flags().IntVar(&flags.limit, "limit", 20, "Number of resources to show")
+	command.Flags().StringToStringVar(&flags.tags, "tag", nil, "Only consider resources matching a DICOM tag (can be specified multiple times)")
+	command.Flags().IntVar(&flags.parallel, "parallel", parallel.DefaultWorkers, "Number of parallel statistics requests")
+	command.Flags().BoolVar(&flags.jsonOutput, "json", false, "Output in JSON format")
+
+	return command
+}
+
+func runTop(flags *TopFlags) error {
+	key, ok := topKeys[flags.by]
+	if !ok {
+		return clierr.Validation("invalid --by '%s', must be one of: disk-size, uncompressed-size, instances", flags.by)
+	}
+	level := types.ResourceLevel(flags.level)
+	if _, ok := summaryTags[level]; !ok {
+		return clierr.Validation("invalid level '%s', must be one of: Patient, Study, Series", flags.level)
+	}
+	if flags.limit < 1 {
+		return clierr.Validation("--limit must be at least 1")
+	}
+	if flags.parallel < 1 {
+		return clierr.Validation("--parallel must be at least 1")
+	}
+
+	// Get the Orthanc client
+	client, err := getClient()
+	if err != nil {
+		return fmt.Errorf("failed to create client: %w", err)
+	}
+
+	// Check if JSON output should be used (flag or config)
+	jsonOutput := flags.jsonOutput || shouldUseJSON()
+
+	// Orthanc requires Query to be an object, even when no tag filter is given
+	if flags.tags == nil {
+		flags.tags = make(map[string]string)
+	}
+	results, err := client.FindExpanded(&types.ToolsFindRequest{
+		Level:  level,
+		Query:  flags.tags,
+		Expand: helpers.BoolPtr(true),
+	})
+	if err != nil {
+		return fmt.Errorf("search failed: %w", err)
+	}
+
+	resources := make([]*resourceUsage, len(results))
+	for i, result := range results {
+		resources[i] = &resourceUsage{
+			ID:   result.ID,
+			Tags: flattenTags(result.PatientMainDicomTags, result.MainDicomTags),
+		}
+	}
+	if err := fetchUsage(client, level, resources, flags.parallel); err != nil {
+		return err
+	}
+
+	sort.SliceStable(resources, func(i, j int) bool {
+		return key(resources[i]) > key(resources[j])
+	})
+	if len(resources) > flags.limit {
+		resources = resources[:flags.limit]
+	}
+
+	if jsonOutput {
+		return printJSON(resources)
+	}
+
+	if len(resources) == 0 {
+		fmt.Println("No resources found.")
+		return nil
+	}
+
+	fmt.Printf("%-4s  %-10s  %-12s  %-9s  %-44s  %s\n", "#", "DISK", "UNCOMPRESSED", "INSTANCES", "ID", strings.Join(summaryTags[level], " / "))
+	for i, resource := range resources {
+		values := make([]string, 0, len(summaryTags[level]))
+		for _, name := range summaryTags[level] {
+			values = append(values, resource.Tags[name])
+		}
+		fmt.Printf("%-4d  %-10s  %-12s  %-9d  %-44s  %s\n", i+1, helpers.FormatBytes(resource.DiskSize),
+			helpers.FormatBytes(resource.UncompressedSize), resource.CountInstances, resource.ID, strings.Join(values, " / "))
+	}
+
+	return nil
+}
@@ -5,7 +5,10 @@ import (
 	"fmt"
 
 	"github.com/proencaj/gorthanc/types"
+	"github.com/proencaj/orthanc-cli/internal/batch"
+	"github.com/proencaj/orthanc-cli/internal/guard"
 	"github.com/proencaj/orthanc-cli/internal/helpers"
+	"github.com/proencaj/orthanc-cli/internal/parallel"
 	"github.com/spf13/cobra"
 )
 
@@ -14,6 +17,7 @@ type AnonymizeFlags struct {
 	force      bool
 	keepSource bool
 	permissive bool
+	parallel   int
 	jsonOutput bool
 }
 
@@ -22,7 +26,7 @@ func NewAnonymizeCommand() *cobra.Command {
 	flags := &AnonymizeFlags{}
 
 	command := &cobra.Command{
-		Use:   "anonymize <study-id>",
+		Use:   "anonymize <study-id>...",
 		Short: "Anonymize a study in the Orthanc server",
 		Long:  `Anonymize a study, creating a new anonymized copy in the Orthanc server.`,
 		Example: `  # Anonymize a study (keeps source by default)
@@ -38,10 +42,14 @@ func NewAnonymizeCommand() *cobra.Command {
   orthanc studies anonymize abc123 --permissive
 
   # Anonymize with JSON output
-  orthanc studies anonymize abc123 --json`,
-		Args: cobra.ExactArgs(1),
+  orthanc studies anonymize abc123 --json
+
+  # Anonymize several studies
+  orthanc studies anonymize abc123 def456 ghi789`,
+		Annotations: map[string]string{guard.Annotation: guard.Mutating},
+		Args:        cobra.MinimumNArgs(1),
 		RunE: func(c *cobra.Command, args []string) error {
-			return runAnonymize(args[0], flags)
+			return runAnonymize(args, flags)
 		},
 	}
 
@@ -49,12 +57,18 @@ func NewAnonymizeCommand() *cobra.Command {
 	command.Flags().BoolVar(&flags.force, "force", false, "Force operation even if it would create an invalid DICOM file")
 	command.Flags().BoolVar(&flags.keepSource, "keep-source", true, "Keep the source study after anonymization")
 	command.Flags().BoolVar(&flags.permissive, "permissive", false, "Ignore errors during individual steps of the job")
+	command.Flags().IntVar(&flags.parallel, "parallel", parallel.DefaultWorkers, "Number of resources anonymized in parallel")
 	command.Flags().BoolVar(&flags.jsonOutput, "json", false, "Output in JSON format")
 
 	return command
 }
 
-func runAnonymize(studyID string, flags *AnonymizeFlags) error {
+func runAnonymize(args []string, flags *AnonymizeFlags) error {
+	studyIDs, err := batch.ReadIDs(args)
+	if err != nil {
+		return err
+	}
+
 	// Get the Orthanc client
 	client, err := getClient()
 	if err != nil {
@@ -65,17 +79,25 @@ func runAnonymize(studyID string, flags *AnonymizeFlags) error {
 	jsonOutput := flags.jsonOutput || shouldUseJSON()
 
 	// Prepare the anonymize request
-	// Create a custom request structure to work around the omitempty issue
 	request := buildAnonymizeRequest(flags)
 
-	// Call the anonymize method
-	response, err := client.AnonymizeStudy(studyID, request)
-	if err != nil {
-		return fmt.Errorf("failed to anonymize study: %w", err)
-	}
+	// Anonymize in parallel and display the results in order
+	responses := make([]*types.StudyAnonymizeResponse, len(studyIDs))
+	return batch.Run(studyIDs, flags.parallel, func(i int) error {
+		// Resolve DICOM UIDs and prefixed identifiers to the Orthanc ID
+		studyID, err := client.ResolveID(types.ResourceLevelStudy, studyIDs[i])
+		if err != nil {
+			return err
+		}
 
-	// Display the results
-	return displayAnonymizeResponse(response, jsonOutput)
+		responses[i], err = client.AnonymizeStudy(studyID, request)
+		if err != nil {
+			return fmt.Errorf("failed to anonymize study: %w", err)
+		}
+		return nil
+	}, func(i int) error {
+		return displayAnonymizeResponse(responses[i], jsonOutput)
+	})
 }
 
 // buildAnonymizeRequest creates a properly formatted anonymize request
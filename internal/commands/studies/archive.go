@@ -3,15 +3,24 @@ package studies
 import (
 	"fmt"
 	"io"
+	"net/http"
 	"os"
 	"path/filepath"
 
+	"github.com/proencaj/gorthanc/types"
+	"github.com/proencaj/orthanc-cli/internal/batch"
+	"github.com/proencaj/orthanc-cli/internal/download"
+	"github.com/proencaj/orthanc-cli/internal/parallel"
 	"github.com/spf13/cobra"
 )
 
 // ArchiveFlags holds the flags for the archive command
 type ArchiveFlags struct {
-	output string
+	output    string
+	transcode string
+	retries   int
+	verify    bool
+	parallel  int
 }
 
 // NewArchiveCommand creates the studies archive command
@@ -19,9 +28,14 @@ func NewArchiveCommand() *cobra.Command {
 	flags := &ArchiveFlags{}
 
 	command := &cobra.Command{
-		Use:   "archive <study-id>",
+		Use:   "archive <study-id>...",
 		Short: "Download and archive a study from the Orthanc server",
-		Long:  `Download a study as a ZIP archive from the Orthanc server and save it to disk.`,
+		Long: `Download a study as a ZIP archive from the Orthanc server and save it to disk.
+
+The archive is written to a temporary file and only moved into place once it
+is complete and its ZIP directory has been checked, so an interrupted download
+never leaves a truncated file behind. Interrupted downloads are retried, and a
+progress bar is shown when stderr is a terminal.`,
 		Example: `  # Archive a study to current directory
   orthanc studies archive abc123
 
@@ -29,62 +43,113 @@ func NewArchiveCommand() *cobra.Command {
   orthanc studies archive abc123 --output /path/to/study.zip
 
   # Archive a study to a specific directory
-  orthanc studies archive abc123 --output /path/to/directory/`,
-		Args: cobra.ExactArgs(1),
+  orthanc studies archive abc123 --output /path/to/directory/
+
+  # Transcode to Explicit VR Little Endian for viewers without JPEG2000 support
+  orthanc studies archive abc123 --transcode explicit-little
+
+  # Check that the archive contains every instance of the study
+  orthanc studies archive abc123 --verify
+
+  # Archive several studies into a directory, one ZIP file each
+  orthanc studies list | orthanc studies archive - --output archives/`,
+		Args: cobra.MinimumNArgs(1),
 		RunE: func(c *cobra.Command, args []string) error {
-			return runArchive(args[0], flags)
+			return runArchive(args, flags)
 		},
 	}
 
 	// Add flags
-	command.Flags().StringVarP(&flags.output, "output", "o", "", "Output path (file or directory, defaults to current directory)")
+	command.Flags().StringVarP(&flags.output, "output", "o", "", "Output path (file or directory, defaults to current directory; a directory for several studies)")
+	command.Flags().IntVar(&flags.retries, "retries", download.DefaultRetries, "Number of retries if the download is interrupted")
+	command.Flags().BoolVar(&flags.verify, "verify", false, "Check that the archive contains one DICOM file per instance")
+	command.Flags().IntVar(&flags.parallel, "parallel", parallel.DefaultWorkers, "Number of archives downloaded in parallel")
+	command.Flags().StringVar(&flags.transcode, "transcode", "", "Transcode to a transfer syntax UID or alias (e.g. explicit-little, jpeg2000-lossless, jpeg-baseline)")
 
 	return command
 }
 
-func runArchive(studyID string, flags *ArchiveFlags) error {
+func runArchive(args []string, flags *ArchiveFlags) error {
+	studyIDs, err := batch.ReadIDs(args)
+	if err != nil {
+		return err
+	}
+	output := batch.OutputDir(flags.output, len(studyIDs))
+
 	// Get the Orthanc client
 	client, err := getClient()
 	if err != nil {
 		return fmt.Errorf("failed to create client: %w", err)
 	}
 
-	// Download the study archive
-	fmt.Printf("Downloading study archive: %s\n", studyID)
-	resp, err := client.DownloadStudyArchive(studyID)
-	if err != nil {
-		return fmt.Errorf("failed to download study archive: %w", err)
+	// Resolve the transfer syntax alias to its UID
+	transferSyntax := ""
+	if flags.transcode != "" {
+		transferSyntax, err = client.ResolveTransferSyntax(flags.transcode)
+		if err != nil {
+			return err
+		}
 	}
-	defer resp.Body.Close()
 
-	// Check response status
-	if resp.StatusCode != 200 {
-		return fmt.Errorf("failed to download study archive: HTTP %d", resp.StatusCode)
+	// Progress bars of parallel downloads would overwrite each other
+	var progress io.Writer
+	if len(studyIDs) > 1 {
+		progress = io.Discard
 	}
 
-	// Determine the output path
-	outputPath, err := determineOutputPath(flags.output, studyID)
-	if err != nil {
-		return fmt.Errorf("failed to determine output path: %w", err)
-	}
+	// Download the archives in parallel and report them in order
+	results := make([]*download.Result, len(studyIDs))
+	expectedFiles := make([]int, len(studyIDs))
+	return batch.Run(studyIDs, flags.parallel, func(i int) error {
+		// Resolve DICOM UIDs and prefixed identifiers to the Orthanc ID
+		studyID, err := client.ResolveID(types.ResourceLevelStudy, studyIDs[i])
+		if err != nil {
+			return err
+		}
 
-	// Create the output file
-	outFile, err := os.Create(outputPath)
-	if err != nil {
-		return fmt.Errorf("failed to create output file: %w", err)
-	}
-	defer outFile.Close()
+		// Determine the output path
+		outputPath, err := determineOutputPath(output, studyID)
+		if err != nil {
+			return fmt.Errorf("failed to determine output path: %w", err)
+		}
 
-	// Copy the response body to the file
-	written, err := io.Copy(outFile, resp.Body)
-	if err != nil {
-		return fmt.Errorf("failed to write archive to file: %w", err)
-	}
+		// Count the instances to compare with the archive content
+		if flags.verify {
+			statistics, err := client.GetStudyStatistics(studyID)
+			if err != nil {
+				return fmt.Errorf("failed to get study statistics: %w", err)
+			}
+			expectedFiles[i] = statistics.CountInstances
+		}
 
-	fmt.Printf("Successfully downloaded study archive to: %s\n", outputPath)
-	fmt.Printf("Size: %.2f MB\n", float64(written)/(1024*1024))
+		archivePath, err := client.TranscodedPath("studies/"+studyID+"/archive", transferSyntax)
+		if err != nil {
+			return err
+		}
 
-	return nil
+		// Download the study archive
+		if len(studyIDs) == 1 {
+			fmt.Printf("Downloading study archive: %s\n", studyID)
+		}
+		results[i], err = download.ToFile(outputPath, func(offset int64) (*http.Response, error) {
+			return client.Download(archivePath, offset)
+		}, download.Options{
+			Retries:  flags.retries,
+			Verify:   download.VerifyZip(expectedFiles[i]),
+			Progress: progress,
+		})
+		if err != nil {
+			return fmt.Errorf("failed to download study archive: %w", err)
+		}
+		return nil
+	}, func(i int) error {
+		fmt.Printf("Successfully downloaded study archive to: %s\n", results[i].Path)
+		fmt.Printf("Size: %.2f MB\n", float64(results[i].Size)/(1024*1024))
+		if flags.verify {
+			fmt.Printf("Verified: %d DICOM file(s)\n", expectedFiles[i])
+		}
+		return nil
+	})
 }
 
 // determineOutputPath determines the final output path for the archive
@@ -0,0 +1,443 @@
+package studies
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/proencaj/gorthanc/types"
+	"github.com/proencaj/orthanc-cli/internal/client"
+	"github.com/proencaj/orthanc-cli/internal/clierr"
+	"github.com/proencaj/orthanc-cli/internal/download"
+	"github.com/proencaj/orthanc-cli/internal/parallel"
+	"github.com/spf13/cobra"
+)
+
+// defaultExportLayout is the layout used when --layout is not given
+const defaultExportLayout = "{PatientID}/{StudyDate}_{AccessionNumber}/{SeriesNumber}_{SeriesDescription}/{InstanceNumber}.dcm"
+
+// missingValue replaces empty or missing fields in rendered paths
+const missingValue = "unknown"
+
+// maxNameLength limits the length of each rendered path component, in bytes
+const maxNameLength = 100
+
+// layoutField matches a {Name} or {Name:width} placeholder
+var layoutField = regexp.MustCompile(`\{([A-Za-z]+)(?::([0-9]+))?\}`)
+
+// ExportFlags holds the flags for the export command
+type ExportFlags struct {
+	dir       string
+	layout    string
+	manifest  string
+	parallel  int
+	retries   int
+	transcode string
+	overwrite bool
+	dryRun    bool
+}
+
+// exportResource is a study, series or instance with its main DICOM tags
+type exportResource struct {
+	ID                   string            `json:"ID"`
+	ParentSeries         string            `json:"ParentSeries"`
+	MainDicomTags        map[string]string `json:"MainDicomTags"`
+	PatientMainDicomTags map[string]string `json:"PatientMainDicomTags"`
+}
+
+// exportItem is an instance to export and the outcome of its download
+type exportItem struct {
+	values map[string]string
+	path   string
+	size   int64
+	err    error
+}
+
+// NewExportCommand creates the studies export command
+func NewExportCommand() *cobra.Command {
+	flags := &ExportFlags{}
+
+	command := &cobra.Command{
+		Use:   "export <study-id>",
+		Short: "Export the instances of a study to a folder tree",
+		Long: `Download every instance of a study into a folder tree built from a layout
+template, and write a CSV manifest of the exported files.
+
+The layout is a path relative to --dir in which {Name} is replaced with the
+value of a main DICOM tag of the patient, study, series or instance (for
+example PatientID, StudyDate, AccessionNumber, Modality, SeriesNumber,
+SeriesDescription, InstanceNumber or SOPInstanceUID), or with one of
+OrthancStudyID, OrthancSeriesID and OrthancInstanceID. {Name:N} pads numeric
+values with zeros to N digits.
+
+Values are sanitized for the filesystem, and missing values are written as
+"unknown". When several instances map to the same path, a numeric suffix is
+added to keep them apart. Existing files are only replaced with --overwrite.
+
+Instances are downloaded in parallel; each file is written atomically and
+retried if the download is interrupted.`,
+		Example: `  # Export a study with the default layout
+  orthanc studies export abc123 --dir out
+
+  # Export with a custom layout and zero-padded instance numbers
+  orthanc studies export abc123 --dir out --layout '{PatientID}/{Modality}/{SeriesNumber}/{InstanceNumber:4}.dcm'
+
+  # Show where each instance would be written without downloading anything
+  orthanc studies export abc123 --dir out --dry-run
+
+  # Export uncompressed images using 8 parallel downloads
+  orthanc studies export abc123 --dir out --transcode explicit-little --parallel 8`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(c *cobra.Command, args []string) error {
+			return runExport(args[0], flags)
+		},
+	}
+
+	// Add flags
+	command.Flags().StringVar(&flags.dir, "dir", "", "Output directory")
+	command.Flags().StringVar(&flags.layout, "layout", defaultExportLayout, "Path template of each instance, relative to --dir")
+	command.Flags().StringVar(&flags.manifest, "manifest", "manifest.csv", "Manifest file, relative to --dir (empty to skip)")
+	command.Flags().IntVar(&flags.parallel, "parallel", parallel.DefaultWorkers, "Number of parallel downloads")
+	command.Flags().IntVar(&flags.retries, "retries", download.DefaultRetries, "Number of retries if a download is interrupted")
+	command.Flags().StringVar(&flags.transcode, "transcode", "", "Transcode to a transfer syntax UID or alias (e.g. explicit-little, jpeg2000-lossless, jpeg-baseline)")
+	command.Flags().BoolVar(&flags.overwrite, "overwrite", false, "Replace files that already exist")
+	command.Flags().BoolVar(&flags.dryRun, "dry-run", false, "Print the planned paths without downloading")
+	command.MarkFlagRequired("dir")
+
+	return command
+}
+
+func runExport(studyID string, flags *ExportFlags) error {
+	if err := validateLayout(flags.layout); err != nil {
+		return err
+	}
+	if flags.parallel < 1 {
+		return clierr.Validation("--parallel must be at least 1")
+	}
+
+	// Get the Orthanc client
+	client, err := getClient()
+	if err != nil {
+		return fmt.Errorf("failed to create client: %w", err)
+	}
+
+	// Resolve the transfer syntax alias to its UID
+	transferSyntax := ""
+	if flags.transcode != "" {
+		transferSyntax, err = client.ResolveTransferSyntax(flags.transcode)
+		if err != nil {
+			return err
+		}
+	}
+
+	// Resolve DICOM UIDs and prefixed identifiers to the Orthanc ID
+	studyID, err = client.ResolveID(types.ResourceLevelStudy, studyID)
+	if err != nil {
+		return err
+	}
+
+	// Fetch the tags of the study, its series and its instances
+	var study exportResource
+	if err := client.GetJSON("studies/"+studyID, &study); err != nil {
+		return fmt.Errorf("failed to fetch study: %w", err)
+	}
+	var series []exportResource
+	if err := client.GetJSON("studies/"+studyID+"/series?expand", &series); err != nil {
+		return fmt.Errorf("failed to fetch series: %w", err)
+	}
+	var instances []exportResource
+	if err := client.GetJSON("studies/"+studyID+"/instances?expand", &instances); err != nil {
+		return fmt.Errorf("failed to fetch instances: %w", err)
+	}
+	if len(instances) == 0 {
+		return clierr.NotFound("study %s has no instances", studyID)
+	}
+
+	items := planExport(flags.layout, flags.manifest, &study, series, instances)
+
+	if flags.dryRun {
+		for _, item := range items {
+			fmt.Printf("%s -> %s\n", item.values["OrthancInstanceID"], filepath.Join(flags.dir, item.path))
+		}
+		return nil
+	}
+
+	// Refuse to replace existing files unless asked to
+	if !flags.overwrite {
+		existing := []string{}
+		for _, item := range items {
+			if _, err := os.Stat(filepath.Join(flags.dir, item.path)); err == nil {
+				existing = append(existing, item.path)
+			}
+		}
+		if len(existing) > 0 {
+			return clierr.New(clierr.KindConflict, "%d file(s) already exist in %s (first: %s); use --overwrite to replace them",
+				len(existing), flags.dir, existing[0])
+		}
+	}
+
+	// Download the instances
+	fmt.Printf("Exporting %d instance(s) of study %s to: %s\n", len(items), studyID, flags.dir)
+	showProgress := download.IsTerminal(os.Stderr)
+	var mu sync.Mutex
+	done := 0
+	errs := parallel.ForEach(len(items), flags.parallel, func(i int) error {
+		item := &items[i]
+		item.err = exportInstance(client, item, flags, transferSyntax)
+
+		mu.Lock()
+		defer mu.Unlock()
+		done++
+		if showProgress {
+			fmt.Fprintf(os.Stderr, "\rExported %d/%d instance(s)", done, len(items))
+		}
+		return item.err
+	})
+	if showProgress {
+		fmt.Fprintln(os.Stderr)
+	}
+
+	// Write the manifest, including failed instances
+	if flags.manifest != "" {
+		manifestPath := filepath.Join(flags.dir, flags.manifest)
+		if err := writeManifest(manifestPath, items); err != nil {
+			return err
+		}
+		fmt.Printf("Manifest: %s\n", manifestPath)
+	}
+
+	failed := parallel.Failed(errs)
+	for _, item := range items {
+		if item.err != nil {
+			fmt.Fprintf(os.Stderr, "Failed to export instance %s: %v\n", item.values["OrthancInstanceID"], item.err)
+		}
+	}
+	fmt.Printf("Exported %d of %d instance(s)\n", len(items)-failed, len(items))
+
+	if failed == len(items) {
+		return fmt.Errorf("failed to export study: %w", items[0].err)
+	}
+	if failed > 0 {
+		return clierr.Partial(failed, len(items))
+	}
+
+	return nil
+}
+
+// exportInstance downloads one instance to its planned path
+func exportInstance(c *client.Client, item *exportItem, flags *ExportFlags, transferSyntax string) error {
+	outputPath := filepath.Join(flags.dir, item.path)
+	if err := os.MkdirAll(filepath.Dir(outputPath), 0755); err != nil {
+		return fmt.Errorf("failed to create directory: %w", err)
+	}
+
+	filePath, err := c.TranscodedPath("instances/"+item.values["OrthancInstanceID"]+"/file", transferSyntax)
+	if err != nil {
+		return err
+	}
+
+	result, err := download.ToFile(outputPath, func(offset int64) (*http.Response, error) {
+		return c.Download(filePath, offset)
+	}, download.Options{
+		Retries:  flags.retries,
+		Progress: io.Discard,
+	})
+	if err != nil {
+		return err
+	}
+	item.size = result.Size
+
+	return nil
+}
+
+// planExport renders the path of every instance, in a stable order, adding a
+// numeric suffix to paths that collide
+func planExport(layout, manifest string, study *exportResource, series, instances []exportResource) []exportItem {
+	seriesTags := make(map[string]map[string]string, len(series))
+	for _, s := range series {
+		seriesTags[s.ID] = s.MainDicomTags
+	}
+
+	items := make([]exportItem, 0, len(instances))
+	for _, instance := range instances {
+		values := map[string]string{}
+		for _, tags := range []map[string]string{study.PatientMainDicomTags, study.MainDicomTags, seriesTags[instance.ParentSeries], instance.MainDicomTags} {
+			for name, value := range tags {
+				values[name] = value
+			}
+		}
+		values["OrthancStudyID"] = study.ID
+		values["OrthancSeriesID"] = instance.ParentSeries
+		values["OrthancInstanceID"] = instance.ID
+
+		items = append(items, exportItem{values: values, path: renderLayout(layout, values)})
+	}
+
+	// Sort by series and instance number so that collision suffixes are stable
+	sort.SliceStable(items, func(i, j int) bool {
+		a, b := items[i].values, items[j].values
+		if a["OrthancSeriesID"] != b["OrthancSeriesID"] {
+			return a["OrthancSeriesID"] < b["OrthancSeriesID"]
+		}
+		na, _ := strconv.Atoi(a["InstanceNumber"])
+		nb, _ := strconv.Atoi(b["InstanceNumber"])
+		if na != nb {
+			return na < nb
+		}
+		return a["OrthancInstanceID"] < b["OrthancInstanceID"]
+	})
+
+	// Compare case-insensitively, as some filesystems do
+	taken := map[string]bool{}
+	if manifest != "" {
+		taken[strings.ToLower(filepath.Clean(manifest))] = true
+	}
+	for i := range items {
+		path := items[i].path
+		extension := filepath.Ext(path)
+		base := strings.TrimSuffix(path, extension)
+		for n := 2; taken[strings.ToLower(path)]; n++ {
+			path = fmt.Sprintf("%s_%d%s", base, n, extension)
+		}
+		taken[strings.ToLower(path)] = true
+		items[i].path = path
+	}
+
+	return items
+}
+
+// validateLayout checks that a layout is a relative file path with well-formed fields
+func validateLayout(layout string) error {
+	if layout == "" {
+		return clierr.Validation("--layout must not be empty")
+	}
+	if strings.HasPrefix(layout, "/") || filepath.IsAbs(layout) {
+		return clierr.Validation("--layout must be relative to --dir: %s", layout)
+	}
+
+	for _, component := range strings.Split(layout, "/") {
+		if component == "" {
+			return clierr.Validation("--layout contains an empty path component: %s", layout)
+		}
+		if component == "." || component == ".." {
+			return clierr.Validation("--layout must not contain %q: %s", component, layout)
+		}
+		if rest := layoutField.ReplaceAllString(component, ""); strings.ContainsAny(rest, "{}") {
+			return clierr.Validation("invalid field in --layout (expected {Name} or {Name:width}): %s", component)
+		}
+	}
+
+	return nil
+}
+
+// renderLayout replaces the fields of a layout with sanitized values
+func renderLayout(layout string, values map[string]string) string {
+	components := strings.Split(layout, "/")
+	for i, component := range components {
+		expanded := layoutField.ReplaceAllStringFunc(component, func(field string) string {
+			match := layoutField.FindStringSubmatch(field)
+			value := strings.TrimSpace(values[match[1]])
+			if value == "" {
+				return missingValue
+			}
+			if match[2] != "" {
+				width, _ := strconv.Atoi(match[2])
+				if number, err := strconv.Atoi(value); err == nil {
+					return fmt.Sprintf("%0*d", width, number)
+				}
+			}
+			return value
+		})
+		components[i] = sanitizeName(expanded)
+	}
+	return filepath.Join(components...)
+}
+
+// sanitizeName makes a single path component safe on common filesystems
+func sanitizeName(name string) string {
+	name = strings.Map(func(r rune) rune {
+		if r < 0x20 || r == 0x7f || strings.ContainsRune(`<>:"/\|?*`, r) {
+			return '_'
+		}
+		return r
+	}, name)
+
+	// Trailing dots and spaces are not allowed on Windows
+	name = strings.Trim(name, " .")
+
+	if len(name) > maxNameLength {
+		extension := filepath.Ext(name)
+		if len(extension) > maxNameLength/2 {
+			extension = ""
+		}
+		name = truncateUTF8(strings.TrimSuffix(name, extension), maxNameLength-len(extension)) + extension
+	}
+
+	if name == "" {
+		return missingValue
+	}
+	return name
+}
+
+// truncateUTF8 shortens s to at most n bytes without splitting a character
+func truncateUTF8(s string, n int) string {
+	if len(s) <= n {
+		return s
+	}
+	for n > 0 && !isRuneStart(s[n]) {
+		n--
+	}
+	return s[:n]
+}
+
+// isRuneStart reports whether b is the first byte of a UTF-8 encoded character
+func isRuneStart(b byte) bool {
+	return b&0xC0 != 0x80
+}
+
+// writeManifest writes one CSV row per instance with its path and status
+func writeManifest(path string, items []exportItem) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return fmt.Errorf("failed to create directory: %w", err)
+	}
+
+	file, err := os.Create(path)
+	if err != nil {
+		return fmt.Errorf("failed to create manifest: %w", err)
+	}
+	defer file.Close()
+
+	writer := csv.NewWriter(file)
+	writer.Write([]string{"Path", "Status", "Size", "OrthancInstanceID", "SOPInstanceUID", "SeriesInstanceUID", "StudyInstanceUID", "PatientID", "Error"})
+	for _, item := range items {
+		status, message := "ok", ""
+		if item.err != nil {
+			status, message = "failed", item.err.Error()
+		}
+		writer.Write([]string{
+			filepath.ToSlash(item.path),
+			status,
+			strconv.FormatInt(item.size, 10),
+			item.values["OrthancInstanceID"],
+			item.values["SOPInstanceUID"],
+			item.values["SeriesInstanceUID"],
+			item.values["StudyInstanceUID"],
+			item.values["PatientID"],
+			message,
+		})
+	}
+	writer.Flush()
+	if err := writer.Error(); err != nil {
+		return fmt.Errorf("failed to write manifest: %w", err)
+	}
+
+	return file.Close()
+}
@@ -5,11 +5,14 @@ import (
 	"fmt"
 
 	"github.com/proencaj/gorthanc/types"
+	"github.com/proencaj/orthanc-cli/internal/batch"
+	"github.com/proencaj/orthanc-cli/internal/parallel"
 	"github.com/spf13/cobra"
 )
 
 // GetFlags holds the flags for the get command
 type GetFlags struct {
+	parallel   int
 	jsonOutput bool
 }
 
@@ -18,28 +21,36 @@ func NewGetCommand() *cobra.Command {
 	flags := &GetFlags{}
 
 	command := &cobra.Command{
-		Use:   "get <study-id>",
+		Use:   "get <study-id>...",
 		Short: "Get detailed information about a specific study",
 		Long:  `Retrieve and display detailed information about a study using its Orthanc Study ID.`,
 		Example: `  # Get study information
   orthanc studies get abc123def456ghi789
 
   # Get study information in JSON format
-  orthanc studies get abc123def456ghi789 --json`,
-		Args: cobra.ExactArgs(1),
+  orthanc studies get abc123def456ghi789 --json
+
+  # Get several studies, reading IDs from stdin
+  orthanc studies list | orthanc studies get - --json`,
+		Args: cobra.MinimumNArgs(1),
 		RunE: func(c *cobra.Command, args []string) error {
-			studyID := args[0]
-			return runGet(studyID, flags)
+			return runGet(args, flags)
 		},
 	}
 
 	// Add flags
+	command.Flags().IntVar(&flags.parallel, "parallel", parallel.DefaultWorkers, "Number of resources fetched in parallel")
 	command.Flags().BoolVar(&flags.jsonOutput, "json", false, "Output in JSON format")
 
 	return command
 }
 
-func runGet(studyID string, flags *GetFlags) error {
+func runGet(args []string, flags *GetFlags) error {
+	studyIDs, err := batch.ReadIDs(args)
+	if err != nil {
+		return err
+	}
+
 	// Get the Orthanc client
 	client, err := getClient()
 	if err != nil {
@@ -49,13 +60,23 @@ func runGet(studyID string, flags *GetFlags) error {
 	// Check if JSON output should be used (flag or config)
 	jsonOutput := flags.jsonOutput || shouldUseJSON()
 
-	// Fetch study details
-	study, err := client.GetStudy(studyID)
-	if err != nil {
-		return fmt.Errorf("failed to fetch study: %w", err)
-	}
+	// Fetch the studies in parallel and display them in order
+	studies := make([]*types.Study, len(studyIDs))
+	return batch.Run(studyIDs, flags.parallel, func(i int) error {
+		// Resolve DICOM UIDs and prefixed identifiers to the Orthanc ID
+		studyID, err := client.ResolveID(types.ResourceLevelStudy, studyIDs[i])
+		if err != nil {
+			return err
+		}
 
-	return displayStudy(study, jsonOutput)
+		studies[i], err = client.GetStudy(studyID)
+		if err != nil {
+			return fmt.Errorf("failed to fetch study: %w", err)
+		}
+		return nil
+	}, func(i int) error {
+		return displayStudy(studies[i], jsonOutput)
+	})
 }
 
 func displayStudy(study *types.Study, jsonOutput bool) error {
@@ -5,6 +5,8 @@ import (
 	"fmt"
 
 	"github.com/proencaj/gorthanc/types"
+	"github.com/proencaj/orthanc-cli/internal/client"
+	"github.com/proencaj/orthanc-cli/internal/fanout"
 	"github.com/spf13/cobra"
 )
 
@@ -13,9 +15,16 @@ type ListFlags struct {
 	limit      int
 	since      int
 	expand     bool
+	fanout     fanout.Flags
 	jsonOutput bool
 }
 
+// contextStudy is a study found by a query across contexts
+type contextStudy struct {
+	Context string `json:"Context"`
+	types.Study
+}
+
 // NewListCommand creates the studies list command
 func NewListCommand() *cobra.Command {
 	flags := &ListFlags{}
@@ -35,7 +44,10 @@ func NewListCommand() *cobra.Command {
 
   # Output in JSON format
   orthanc studies list --json
-  orthanc studies list --expand --json`,
+  orthanc studies list --expand --json
+
+  # List the studies of several servers
+  orthanc studies list --contexts north,south --expand`,
 		RunE: func(c *cobra.Command, args []string) error {
 			return runList(flags)
 		},
@@ -45,12 +57,17 @@ func NewListCommand() *cobra.Command {
 	command.Flags().IntVar(&flags.limit, "limit", 100, "Maximum number of studies to return")
 	command.Flags().IntVar(&flags.since, "since", 0, "Start from this index")
 	command.Flags().BoolVar(&flags.expand, "expand", false, "Show full study details")
+	fanout.AddFlags(command, &flags.fanout)
 	command.Flags().BoolVar(&flags.jsonOutput, "json", false, "Output in JSON format")
 
 	return command
 }
 
 func runList(flags *ListFlags) error {
+	if flags.fanout.Enabled() {
+		return runListContexts(flags)
+	}
+
 	// Get the Orthanc client
 	client, err := getClient()
 	if err != nil {
@@ -86,6 +103,97 @@ func runList(flags *ListFlags) error {
 	return displayStudyIDs(studyIDs, jsonOutput)
 }
 
+// runListContexts lists the studies of several contexts concurrently
+func runListContexts(flags *ListFlags) error {
+	// Check if JSON output should be used (flag or config)
+	jsonOutput := flags.jsonOutput || shouldUseJSON()
+
+	params := &types.StudiesQueryParams{
+		Expand: flags.expand,
+		Since:  flags.since,
+		Limit:  flags.limit,
+	}
+
+	results, err := fanout.Run(&flags.fanout, func(c *client.Client) ([]types.Study, error) {
+		if flags.expand {
+			studies, err := c.GetStudiesExpanded(params)
+			if err != nil {
+				return nil, fmt.Errorf("failed to fetch studies: %w", err)
+			}
+			return studies, nil
+		}
+		ids, err := c.GetStudies(params)
+		if err != nil {
+			return nil, fmt.Errorf("failed to fetch studies: %w", err)
+		}
+		studies := make([]types.Study, len(ids))
+		for i, id := range ids {
+			studies[i].ID = id
+		}
+		return studies, nil
+	})
+	if err != nil {
+		return err
+	}
+
+	studies := []contextStudy{}
+	for _, result := range results {
+		for _, study := range result.Value {
+			studies = append(studies, contextStudy{Context: result.Context, Study: study})
+		}
+	}
+
+	if err := displayContextStudies(studies, flags.expand, jsonOutput); err != nil {
+		return err
+	}
+	return fanout.Report(results)
+}
+
+// displayContextStudies prints studies found across contexts
+func displayContextStudies(studies []contextStudy, expand, jsonOutput bool) error {
+	if jsonOutput {
+		var value interface{} = studies
+		if !expand {
+			ids := make([]map[string]string, len(studies))
+			for i, study := range studies {
+				ids[i] = map[string]string{"Context": study.Context, "ID": study.ID}
+			}
+			value = ids
+		}
+		data, err := json.MarshalIndent(value, "", "  ")
+		if err != nil {
+			return fmt.Errorf("failed to marshal JSON: %w", err)
+		}
+		fmt.Println(string(data))
+		return nil
+	}
+
+	if !expand {
+		width := len("CONTEXT")
+		for _, study := range studies {
+			width = max(width, len(study.Context))
+		}
+		fmt.Printf("%-*s  %s\n", width, "CONTEXT", "ID")
+		for _, study := range studies {
+			fmt.Printf("%-*s  %s\n", width, study.Context, study.ID)
+		}
+		return nil
+	}
+
+	for _, study := range studies {
+		fmt.Printf("Context: %s\n", study.Context)
+		fmt.Printf("OrthancStudyID: %s\n", study.ID)
+		fmt.Printf("AccessionNumber: %s\n", study.MainDicomTags.AccessionNumber)
+		fmt.Printf("StudyInstanceUID: %s\n", study.MainDicomTags.StudyInstanceUID)
+		fmt.Printf("StudyDate: %s\n", study.MainDicomTags.StudyDate)
+		fmt.Printf("StudyDescription: %s\n", study.MainDicomTags.StudyDescription)
+		fmt.Printf("PatientName: %s\n", study.MainDicomTags.PatientName)
+		fmt.Printf("\n")
+	}
+
+	return nil
+}
+
 func displayStudyIDs(studyIDs []string, jsonOutput bool) error {
 	if jsonOutput {
 		data, err := json.MarshalIndent(studyIDs, "", "  ")
@@ -53,6 +53,12 @@ func runListSeries(studyID string, flags *ListSeriesFlags) error {
 		return fmt.Errorf("failed to create client: %w", err)
 	}
 
+	// Resolve DICOM UIDs and prefixed identifiers to the Orthanc ID
+	studyID, err = client.ResolveID(types.ResourceLevelStudy, studyID)
+	if err != nil {
+		return err
+	}
+
 	// Check if JSON output should be used (flag or config)
 	jsonOutput := flags.jsonOutput || shouldUseJSON()
 
@@ -41,15 +41,25 @@ func NewStudiesCommand() *cobra.Command {
 	studiesCmd := &cobra.Command{
 		Use:   "studies",
 		Short: "Manage Orthanc studies",
-		Long:  `Query, list, and manage DICOM studies in the Orthanc server.`,
+		Long: `Query, list, and manage DICOM studies in the Orthanc server.
+
+Studies can be given by Orthanc ID, by StudyInstanceUID, or as
+uid:<StudyInstanceUID>, acc:<AccessionNumber> or pid:<PatientID>.
+Identifiers that match several resources are rejected as ambiguous.
+
+Commands that take studies also accept several IDs, or - to read them from
+stdin one per line, and process them in parallel (see --parallel). If some of
+them fail, the others are still processed and the exit code is 10.`,
 	}
 
 	// Add subcommands
 	studiesCmd.AddCommand(NewListCommand())
 	studiesCmd.AddCommand(NewGetCommand())
+	studiesCmd.AddCommand(NewStatisticsCommand())
 	studiesCmd.AddCommand(NewRemoveCommand())
 	studiesCmd.AddCommand(NewAnonymizeCommand())
 	studiesCmd.AddCommand(NewArchiveCommand())
+	studiesCmd.AddCommand(NewExportCommand())
 	studiesCmd.AddCommand(NewListSeriesCommand())
 	studiesCmd.AddCommand(NewListInstancesCommand())
 
@@ -4,8 +4,10 @@ import (
 	"encoding/json"
 	"fmt"
 
+	"github.com/proencaj/gorthanc/types"
 	"github.com/proencaj/orthanc-cli/internal/client"
 	"github.com/proencaj/orthanc-cli/internal/config"
+	"github.com/proencaj/orthanc-cli/internal/fanout"
 	"github.com/spf13/cobra"
 )
 
@@ -41,9 +43,16 @@ func shouldUseJSON() bool {
 
 // SystemFlags holds the flags for the system command
 type SystemFlags struct {
+	fanout     fanout.Flags
 	jsonOutput bool
 }
 
+// contextSystem is the system information of a context, in a query across contexts
+type contextSystem struct {
+	Context string `json:"Context"`
+	*types.SystemInfo
+}
+
 // NewSystemCommand creates the system command
 func NewSystemCommand() *cobra.Command {
 	flags := &SystemFlags{}
@@ -56,7 +65,10 @@ func NewSystemCommand() *cobra.Command {
   orthanc system
 
   # Get system information in JSON format
-  orthanc system --json`,
+  orthanc system --json
+
+  # Compare the versions of every configured server
+  orthanc system --all-contexts`,
 		Args: cobra.NoArgs,
 		RunE: func(c *cobra.Command, args []string) error {
 			return runSystem(flags)
@@ -64,12 +76,17 @@ func NewSystemCommand() *cobra.Command {
 	}
 
 	// Add flags
+	fanout.AddFlags(command, &flags.fanout)
 	command.Flags().BoolVar(&flags.jsonOutput, "json", false, "Output in JSON format")
 
 	return command
 }
 
 func runSystem(flags *SystemFlags) error {
+	if flags.fanout.Enabled() {
+		return runSystemContexts(flags)
+	}
+
 	// Get the Orthanc client
 	client, err := getClient()
 	if err != nil {
@@ -144,3 +161,47 @@ func runSystem(flags *SystemFlags) error {
 
 	return nil
 }
+
+// runSystemContexts shows the system information of several contexts as a table
+func runSystemContexts(flags *SystemFlags) error {
+	// Check if JSON output should be used (flag or config)
+	jsonOutput := flags.jsonOutput || shouldUseJSON()
+
+	results, err := fanout.Run(&flags.fanout, func(c *client.Client) (*types.SystemInfo, error) {
+		systemInfo, err := c.GetSystem()
+		if err != nil {
+			return nil, fmt.Errorf("failed to get system information: %w", err)
+		}
+		return systemInfo, nil
+	})
+	if err != nil {
+		return err
+	}
+
+	systems := []contextSystem{}
+	for _, result := range results {
+		if result.Err == nil {
+			systems = append(systems, contextSystem{Context: result.Context, SystemInfo: result.Value})
+		}
+	}
+
+	if jsonOutput {
+		data, err := json.MarshalIndent(systems, "", "  ")
+		if err != nil {
+			return fmt.Errorf("failed to marshal JSON: %w", err)
+		}
+		fmt.Println(string(data))
+	} else {
+		width := len("CONTEXT")
+		for _, system := range systems {
+			width = max(width, len(system.Context))
+		}
+		fmt.Printf("%-*s  %-16s  %-10s  %-4s  %-16s  %s\n", width, "CONTEXT", "NAME", "VERSION", "API", "DICOM AET", "DICOM PORT")
+		for _, system := range systems {
+			fmt.Printf("%-*s  %-16s  %-10s  %-4d  %-16s  %d\n", width, system.Context, system.Name,
+				system.Version, system.ApiVersion, system.DicomAet, system.DicomPort)
+		}
+	}
+
+	return fanout.Report(results)
+}
@@ -0,0 +1,241 @@
+package tools
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+
+	"github.com/proencaj/orthanc-cli/internal/client"
+	"github.com/proencaj/orthanc-cli/internal/clierr"
+	"github.com/proencaj/orthanc-cli/internal/guard"
+	"github.com/proencaj/orthanc-cli/internal/helpers"
+	"github.com/spf13/cobra"
+)
+
+// SOP classes used for encapsulated content
+const (
+	sopClassSecondaryCapture = "1.2.840.10008.5.1.4.1.1.7"
+	sopClassEncapsulatedPDF  = "1.2.840.10008.5.1.4.1.1.104.1"
+)
+
+// CreateDicomFlags holds the flags for the create-dicom command
+type CreateDicomFlags struct {
+	tagsFile   string
+	tags       []string
+	parent     string
+	force      bool
+	jsonOutput bool
+}
+
+// createDicomRequest is the body of /tools/create-dicom
+type createDicomRequest struct {
+	Tags    map[string]interface{} `json:"Tags"`
+	Content string                 `json:"Content,omitempty"`
+	Parent  string                 `json:"Parent,omitempty"`
+	Force   bool                   `json:"Force,omitempty"`
+}
+
+// createDicomResponse is the answer of /tools/create-dicom
+type createDicomResponse struct {
+	ID   string `json:"ID"`
+	Path string `json:"Path"`
+}
+
+// NewCreateDicomCommand creates the tools create-dicom command
+func NewCreateDicomCommand() *cobra.Command {
+	flags := &CreateDicomFlags{}
+
+	command := &cobra.Command{
+		Use:   "create-dicom [content-file]",
+		Short: "Create a DICOM instance from tags and an image or PDF",
+		Long: `Create a new DICOM instance on the Orthanc server from a set of tags and,
+optionally, a PNG/JPEG image or a PDF document.
+
+Images are encapsulated as Secondary Capture and PDF documents as
+Encapsulated PDF, unless the tags set SOPClassUID explicitly. Instances added
+to an existing series keep the SOP class and modality chosen by Orthanc and
+the series.
+
+Tags are read from a JSON or YAML file with --tags (a mapping of tag names
+to values) and can be overridden with --tag Name=Value.
+
+With --parent the new instance is attached to an existing patient, study or
+series and inherits its patient and study tags. The parent can be given by
+Orthanc ID or by DICOM identifier (e.g. a StudyInstanceUID or acc:<number>).
+
+The ID of the new instance is printed on success.`,
+		Example: `  # Attach a scanned referral letter to an existing study
+  orthanc tools create-dicom letter.pdf --parent acc:A12345 --tag SeriesDescription="Referral letter"
+
+  # Create a Secondary Capture from a photo and a tag file
+  orthanc tools create-dicom photo.jpg --tags tags.yaml
+
+  # Create an instance without pixel data
+  orthanc tools create-dicom --tags tags.json`,
+		Annotations: map[string]string{guard.Annotation: guard.Mutating},
+		Args:        cobra.MaximumNArgs(1),
+		RunE: func(c *cobra.Command, args []string) error {
+			contentFile := ""
+			if len(args) == 1 {
+				contentFile = args[0]
+			}
+			return runCreateDicom(contentFile, flags)
+		},
+	}
+
+	// Add flags
+	command.Flags().StringVar(&flags.tagsFile, "tags", "", "JSON or YAML file with the DICOM tags")
+	command.Flags().StringArrayVar(&flags.tags, "tag", nil, "DICOM tag as Name=Value, overrides --tags (can be repeated)")
+	command.Flags().StringVar(&flags.parent, "parent", "", "Patient, study or series to attach the instance to")
+	command.Flags().BoolVar(&flags.force, "force", false, "Allow setting UIDs and other tags Orthanc normally generates")
+	command.Flags().BoolVar(&flags.jsonOutput, "json", false, "Output in JSON format")
+
+	return command
+}
+
+func runCreateDicom(contentFile string, flags *CreateDicomFlags) error {
+	tags, err := loadCreateDicomTags(flags.tagsFile, flags.tags)
+	if err != nil {
+		return err
+	}
+
+	if contentFile == "" && len(tags) == 0 && flags.parent == "" {
+		return clierr.Validation("nothing to create, provide a content file, --tags, --tag or --parent")
+	}
+
+	request := &createDicomRequest{
+		Tags:  tags,
+		Force: flags.force,
+	}
+
+	mimeType := ""
+	if contentFile != "" {
+		request.Content, mimeType, err = readCreateDicomContent(contentFile)
+		if err != nil {
+			return err
+		}
+	}
+
+	// Get the Orthanc client
+	client, err := getClient()
+	if err != nil {
+		return fmt.Errorf("failed to create client: %w", err)
+	}
+
+	underSeries := false
+	if flags.parent != "" {
+		request.Parent, err = client.ResolveID("", flags.parent)
+		if err != nil {
+			return err
+		}
+		underSeries, err = isSeries(client, request.Parent)
+		if err != nil {
+			return err
+		}
+	}
+
+	// Instances of a series inherit its tags, which Orthanc refuses to see
+	// overridden
+	if contentFile != "" && !underSeries {
+		if _, ok := tags["SOPClassUID"]; !ok {
+			if mimeType == "application/pdf" {
+				tags["SOPClassUID"] = sopClassEncapsulatedPDF
+			} else {
+				tags["SOPClassUID"] = sopClassSecondaryCapture
+			}
+		}
+		if _, ok := tags["Modality"]; !ok {
+			if mimeType == "application/pdf" {
+				tags["Modality"] = "DOC"
+			} else {
+				tags["Modality"] = "OT"
+			}
+		}
+	}
+
+	var response createDicomResponse
+	if err := client.PostJSON("tools/create-dicom", request, &response); err != nil {
+		return fmt.Errorf("failed to create DICOM instance: %w", err)
+	}
+
+	if flags.jsonOutput || shouldUseJSON() {
+		data, err := json.MarshalIndent(response, "", "  ")
+		if err != nil {
+			return fmt.Errorf("failed to marshal JSON: %w", err)
+		}
+		fmt.Println(string(data))
+		return nil
+	}
+
+	fmt.Println(response.ID)
+	return nil
+}
+
+// isSeries reports whether the resource with the given Orthanc ID is a series
+func isSeries(c *client.Client, id string) (bool, error) {
+	err := c.GetJSON("series/"+url.PathEscape(id), nil)
+	switch {
+	case err == nil:
+		return true, nil
+	case clierr.KindOf(err) == clierr.KindNotFound:
+		return false, nil
+	}
+	return false, fmt.Errorf("failed to fetch parent: %w", err)
+}
+
+// loadCreateDicomTags reads the tag file and applies Name=Value overrides
+func loadCreateDicomTags(path string, overrides []string) (map[string]interface{}, error) {
+	tags := map[string]interface{}{}
+
+	if path != "" {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return nil, clierr.Validation("failed to read tags file: %v", err)
+		}
+
+		// JSON documents are valid YAML. Scalars are kept as written, since
+		// Orthanc expects tag values as strings.
+		document, err := helpers.ParseYAML(data)
+		if err != nil {
+			return nil, clierr.Validation("failed to parse tags file %s: %v", path, err)
+		}
+		switch value := document.(type) {
+		case nil:
+		case map[string]interface{}:
+			tags = value
+		default:
+			return nil, clierr.Validation("failed to parse tags file %s: expected a mapping of tag names to values", path)
+		}
+	}
+
+	for _, override := range overrides {
+		name, value, ok := strings.Cut(override, "=")
+		if !ok || name == "" {
+			return nil, clierr.Validation("invalid --tag '%s', expected Name=Value", override)
+		}
+		tags[name] = value
+	}
+
+	return tags, nil
+}
+
+// readCreateDicomContent reads an image or PDF and returns it as a data URI
+func readCreateDicomContent(path string) (string, string, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return "", "", clierr.Validation("failed to read content file: %v", err)
+	}
+
+	mimeType := http.DetectContentType(data)
+	switch mimeType {
+	case "image/png", "image/jpeg", "application/pdf":
+	default:
+		return "", "", clierr.Validation("unsupported content type '%s' for %s, expected a PNG or JPEG image or a PDF document", mimeType, path)
+	}
+
+	return fmt.Sprintf("data:%s;base64,%s", mimeType, base64.StdEncoding.EncodeToString(data)), mimeType, nil
+}
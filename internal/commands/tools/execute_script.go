@@ -0,0 +1,174 @@
+package tools
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/proencaj/orthanc-cli/internal/clierr"
+	"github.com/proencaj/orthanc-cli/internal/guard"
+	"github.com/spf13/cobra"
+)
+
+// scriptVarPattern matches ${name} placeholders in a script
+var scriptVarPattern = regexp.MustCompile(`\$\{([A-Za-z_][A-Za-z0-9_]*)\}`)
+
+// ExecuteScriptFlags holds the flags for the execute-script command
+type ExecuteScriptFlags struct {
+	vars   []string
+	dryRun bool
+}
+
+// NewExecuteScriptCommand creates the tools execute-script command
+func NewExecuteScriptCommand() *cobra.Command {
+	flags := &ExecuteScriptFlags{}
+
+	command := &cobra.Command{
+		Use:   "execute-script <file.lua|->",
+		Short: "Execute a Lua script on the Orthanc server",
+		Long: `Execute a Lua script on the Orthanc server and print its output.
+
+The script is read from a file, or from standard input when "-" is given.
+Placeholders of the form ${name} are replaced with the values given by --var
+before the script is sent. A placeholder without a matching --var is an error.
+
+Note: Orthanc 1.12 and later refuse to execute scripts unless
+"ExecuteLuaEnabled" is set to true in the server configuration.`,
+		Example: `  # Run a maintenance script
+  orthanc tools execute-script cleanup.lua
+
+  # Template variables into the script
+  orthanc tools execute-script delete-patient.lua --var patient=12345
+
+  # Show the final script without running it
+  orthanc tools execute-script delete-patient.lua --var patient=12345 --dry-run
+
+  # Read the script from standard input
+  echo 'print(os.time())' | orthanc tools execute-script -`,
+		Annotations: map[string]string{guard.Annotation: guard.Destructive},
+		Args:        cobra.ExactArgs(1),
+		RunE: func(c *cobra.Command, args []string) error {
+			return runExecuteScript(args[0], flags)
+		},
+	}
+
+	// Add flags
+	command.Flags().StringArrayVar(&flags.vars, "var", nil, "Template variable as key=value, replaces ${key} in the script (can be repeated)")
+	command.Flags().BoolVar(&flags.dryRun, "dry-run", false, "Print the final script without executing it")
+
+	return command
+}
+
+func runExecuteScript(source string, flags *ExecuteScriptFlags) error {
+	// Read the script
+	script, err := readScript(source)
+	if err != nil {
+		return err
+	}
+
+	vars, err := parseScriptVars(flags.vars)
+	if err != nil {
+		return err
+	}
+
+	script, err = renderScript(script, vars)
+	if err != nil {
+		return err
+	}
+
+	if flags.dryRun {
+		fmt.Print(script)
+		if !strings.HasSuffix(script, "\n") {
+			fmt.Println()
+		}
+		return nil
+	}
+
+	// Get the Orthanc client
+	client, err := getClient()
+	if err != nil {
+		return fmt.Errorf("failed to create client: %w", err)
+	}
+
+	resp, err := client.Do(http.MethodPost, "tools/execute-script", strings.NewReader(script), "text/plain")
+	if err != nil {
+		if clierr.StatusOf(err) == http.StatusForbidden {
+			return clierr.New(clierr.KindForbidden, "the server refused to execute the script, check that \"ExecuteLuaEnabled\" is true in the Orthanc configuration")
+		}
+		return fmt.Errorf("failed to execute script: %w", err)
+	}
+	defer resp.Body.Close()
+
+	output, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return fmt.Errorf("failed to read script output: %w", err)
+	}
+
+	fmt.Print(string(output))
+	if len(output) > 0 && output[len(output)-1] != '\n' {
+		fmt.Println()
+	}
+
+	return nil
+}
+
+// readScript reads a script from a file, or from stdin when source is "-"
+func readScript(source string) (string, error) {
+	var data []byte
+	var err error
+	if source == "-" {
+		data, err = io.ReadAll(os.Stdin)
+	} else {
+		data, err = os.ReadFile(source)
+	}
+	if err != nil {
+		return "", clierr.Validation("failed to read script: %v", err)
+	}
+
+	if strings.TrimSpace(string(data)) == "" {
+		return "", clierr.Validation("script is empty")
+	}
+	return string(data), nil
+}
+
+// parseScriptVars parses key=value pairs given with --var
+func parseScriptVars(pairs []string) (map[string]string, error) {
+	vars := make(map[string]string, len(pairs))
+	for _, pair := range pairs {
+		key, value, ok := strings.Cut(pair, "=")
+		if !ok || key == "" {
+			return nil, clierr.Validation("invalid --var '%s', expected key=value", pair)
+		}
+		vars[key] = value
+	}
+	return vars, nil
+}
+
+// renderScript replaces ${name} placeholders with their values
+func renderScript(script string, vars map[string]string) (string, error) {
+	missing := map[string]bool{}
+	rendered := scriptVarPattern.ReplaceAllStringFunc(script, func(placeholder string) string {
+		name := scriptVarPattern.FindStringSubmatch(placeholder)[1]
+		value, ok := vars[name]
+		if !ok {
+			missing[name] = true
+			return placeholder
+		}
+		return value
+	})
+
+	if len(missing) > 0 {
+		names := make([]string, 0, len(missing))
+		for name := range missing {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		return "", clierr.Validation("missing value for script variable(s): %s (use --var name=value)", strings.Join(names, ", "))
+	}
+
+	return rendered, nil
+}
@@ -3,8 +3,13 @@ package tools
 import (
 	"encoding/json"
 	"fmt"
+	"os"
+	"strings"
 
 	"github.com/proencaj/gorthanc/types"
+	"github.com/proencaj/orthanc-cli/internal/client"
+	"github.com/proencaj/orthanc-cli/internal/clierr"
+	"github.com/proencaj/orthanc-cli/internal/fanout"
 	"github.com/proencaj/orthanc-cli/internal/helpers"
 	"github.com/spf13/cobra"
 )
@@ -19,9 +24,16 @@ type FindFlags struct {
 	requestedTags    []string
 	labels           []string
 	labelsConstraint string
+	fanout           fanout.Flags
 	jsonOutput       bool
 }
 
+// contextFindResult is a resource found by a query across contexts
+type contextFindResult struct {
+	Context string `json:"Context"`
+	types.ToolsFindExpandedResource
+}
+
 // NewFindCommand creates the tools find command
 func NewFindCommand() *cobra.Command {
 	flags := &FindFlags{
@@ -31,7 +43,10 @@ func NewFindCommand() *cobra.Command {
 	command := &cobra.Command{
 		Use:   "find",
 		Short: "Search for DICOM resources in the local Orthanc database",
-		Long:  `Execute a search query to find patients, studies, series, or instances stored in the local Orthanc database.`,
+		Long: `Execute a search query to find patients, studies, series, or instances stored in the local Orthanc database.
+
+On servers older than Orthanc 1.12.0, label filters are applied client-side, and
+requested tags are ignored on servers older than Orthanc 1.11.0.`,
 		Example: `  # Find all studies (no filter)
   orthanc tools find --level Study
 
@@ -64,7 +79,10 @@ func NewFindCommand() *cobra.Command {
     --requested-tag StudyDescription
 
   # Output in JSON format
-  orthanc tools find --level Study --tag PatientID=12345 --json`,
+  orthanc tools find --level Study --tag PatientID=12345 --json
+
+  # Find which servers hold the studies of a patient
+  orthanc tools find --level Study --tag PatientID=12345 --all-contexts --expand`,
 		Args: cobra.NoArgs,
 		RunE: func(c *cobra.Command, args []string) error {
 			return runFind(flags)
@@ -80,18 +98,13 @@ func NewFindCommand() *cobra.Command {
 	command.Flags().StringSliceVar(&flags.requestedTags, "requested-tag", nil, "Specific DICOM tags to include in response (Orthanc 1.11.0+)")
 	command.Flags().StringSliceVar(&flags.labels, "label", nil, "Filter resources by labels (Orthanc 1.12.0+)")
 	command.Flags().StringVar(&flags.labelsConstraint, "labels-constraint", "", "How to apply label filters: All, Any, None (Orthanc 1.12.0+)")
+	fanout.AddFlags(command, &flags.fanout)
 	command.Flags().BoolVar(&flags.jsonOutput, "json", false, "Output in JSON format")
 
 	return command
 }
 
 func runFind(flags *FindFlags) error {
-	// Get the Orthanc client
-	client, err := getClient()
-	if err != nil {
-		return fmt.Errorf("failed to create client: %w", err)
-	}
-
 	// Check if JSON output should be used (flag or config)
 	jsonOutput := flags.jsonOutput || shouldUseJSON()
 
@@ -103,7 +116,12 @@ func runFind(flags *FindFlags) error {
 		"Instance": true,
 	}
 	if !validLevels[flags.level] {
-		return fmt.Errorf("invalid level '%s', must be one of: Patient, Study, Series, Instance", flags.level)
+		return clierr.Validation("invalid level '%s', must be one of: Patient, Study, Series, Instance", flags.level)
+	}
+
+	// Orthanc requires Query to be an object, even when no tag filter is given
+	if flags.tags == nil {
+		flags.tags = make(map[string]string)
 	}
 
 	// Build the find request
@@ -139,7 +157,11 @@ func runFind(flags *FindFlags) error {
 
 	// Display query information
 	if !jsonOutput {
-		fmt.Printf("Searching local Orthanc database\n")
+		if flags.fanout.Enabled() {
+			fmt.Printf("Searching the Orthanc databases of several contexts\n")
+		} else {
+			fmt.Printf("Searching local Orthanc database\n")
+		}
 		fmt.Printf("Level: %s\n", flags.level)
 		fmt.Println("Query tags:")
 		for key, value := range flags.tags {
@@ -163,7 +185,36 @@ func runFind(flags *FindFlags) error {
 		fmt.Println()
 	}
 
+	if flags.fanout.Enabled() {
+		return runFindContexts(request, flags, jsonOutput)
+	}
+
+	// Get the Orthanc client
+	client, err := getClient()
+	if err != nil {
+		return fmt.Errorf("failed to create client: %w", err)
+	}
+
+	// Degrade gracefully on servers that predate labels or requested tags
+	fallback := applyFindFallbacks(client, request)
+
 	// Perform the search
+	if fallback != nil {
+		results, err := client.FindExpanded(request)
+		if err != nil {
+			return fmt.Errorf("search failed: %w", err)
+		}
+		results = fallback.filter(results)
+		if flags.expand {
+			return displayExpandedResults(results, jsonOutput)
+		}
+		ids := make([]string, 0, len(results))
+		for _, result := range results {
+			ids = append(ids, result.ID)
+		}
+		return displaySimpleResults(ids, jsonOutput)
+	}
+
 	if flags.expand {
 		results, err := client.FindExpanded(request)
 		if err != nil {
@@ -179,6 +230,142 @@ func runFind(flags *FindFlags) error {
 	}
 }
 
+// runFindContexts runs the search against several contexts concurrently
+func runFindContexts(request *types.ToolsFindRequest, flags *FindFlags, jsonOutput bool) error {
+	results, err := fanout.Run(&flags.fanout, func(c *client.Client) ([]types.ToolsFindExpandedResource, error) {
+		// Each server may need its own fallbacks
+		contextRequest := *request
+		fallback := applyFindFallbacks(c, &contextRequest)
+
+		if fallback == nil && !flags.expand {
+			ids, err := c.Find(&contextRequest)
+			if err != nil {
+				return nil, fmt.Errorf("search failed: %w", err)
+			}
+			resources := make([]types.ToolsFindExpandedResource, len(ids))
+			for i, id := range ids {
+				resources[i].ID = id
+			}
+			return resources, nil
+		}
+
+		resources, err := c.FindExpanded(&contextRequest)
+		if err != nil {
+			return nil, fmt.Errorf("search failed: %w", err)
+		}
+		if fallback != nil {
+			resources = fallback.filter(resources)
+		}
+		return resources, nil
+	})
+	if err != nil {
+		return err
+	}
+
+	found := []contextFindResult{}
+	for _, result := range results {
+		for _, resource := range result.Value {
+			found = append(found, contextFindResult{Context: result.Context, ToolsFindExpandedResource: resource})
+		}
+	}
+
+	if err := displayContextResults(found, flags.expand, jsonOutput); err != nil {
+		return err
+	}
+	return fanout.Report(results)
+}
+
+// labelFallback filters and paginates find results client-side when the
+// server cannot filter by labels itself
+type labelFallback struct {
+	labels     []string
+	constraint string
+	since      int
+	limit      int
+}
+
+// applyFindFallbacks removes request fields the server does not support.
+// If label filtering has to be done client-side, it returns the filter to apply.
+func applyFindFallbacks(c *client.Client, request *types.ToolsFindRequest) *labelFallback {
+	caps, err := c.Capabilities()
+	if err != nil {
+		// Unknown server capabilities: send the request as-is
+		return nil
+	}
+
+	if len(request.RequestedTags) > 0 && !caps.Supports(client.FeatureRequestedTags) {
+		fmt.Fprintf(os.Stderr, "Warning: Orthanc %s does not support requested tags (1.11.0+), ignoring --requested-tag\n", caps.Version)
+		request.RequestedTags = nil
+	}
+
+	if len(request.Labels) == 0 || caps.Supports(client.FeatureLabels) {
+		return nil
+	}
+
+	fmt.Fprintf(os.Stderr, "Warning: Orthanc %s does not support label filters (1.12.0+), filtering labels client-side\n", caps.Version)
+	fallback := &labelFallback{
+		labels:     request.Labels,
+		constraint: request.LabelsConstraint,
+	}
+	if request.Since != nil {
+		fallback.since = *request.Since
+	}
+	if request.Limit != nil {
+		fallback.limit = *request.Limit
+	}
+
+	// Pagination must happen after filtering, so fetch everything
+	request.Labels = nil
+	request.LabelsConstraint = ""
+	request.Since = nil
+	request.Limit = nil
+
+	return fallback
+}
+
+// filter keeps the resources matching the label constraint, then applies since/limit
+func (f *labelFallback) filter(results []types.ToolsFindExpandedResource) []types.ToolsFindExpandedResource {
+	matched := make([]types.ToolsFindExpandedResource, 0, len(results))
+	for _, result := range results {
+		if matchesLabels(result.Labels, f.labels, f.constraint) {
+			matched = append(matched, result)
+		}
+	}
+
+	if f.since >= len(matched) {
+		return []types.ToolsFindExpandedResource{}
+	}
+	matched = matched[f.since:]
+	if f.limit > 0 && f.limit < len(matched) {
+		matched = matched[:f.limit]
+	}
+	return matched
+}
+
+// matchesLabels applies Orthanc's label constraint semantics (All is the default)
+func matchesLabels(have []string, want []string, constraint string) bool {
+	present := make(map[string]bool, len(have))
+	for _, label := range have {
+		present[label] = true
+	}
+
+	count := 0
+	for _, label := range want {
+		if present[label] {
+			count++
+		}
+	}
+
+	switch strings.ToLower(constraint) {
+	case "any":
+		return count > 0
+	case "none":
+		return count == 0
+	default:
+		return count == len(want)
+	}
+}
+
 func displaySimpleResults(results []string, jsonOutput bool) error {
 	if jsonOutput {
 		data, err := json.MarshalIndent(results, "", "  ")
@@ -203,6 +390,52 @@ func displaySimpleResults(results []string, jsonOutput bool) error {
 	return nil
 }
 
+func displayContextResults(results []contextFindResult, expand, jsonOutput bool) error {
+	if jsonOutput {
+		var value interface{} = results
+		if !expand {
+			ids := make([]map[string]string, len(results))
+			for i, result := range results {
+				ids[i] = map[string]string{"Context": result.Context, "ID": result.ID}
+			}
+			value = ids
+		}
+		data, err := json.MarshalIndent(value, "", "  ")
+		if err != nil {
+			return fmt.Errorf("failed to marshal JSON: %w", err)
+		}
+		fmt.Println(string(data))
+		return nil
+	}
+
+	// Text output
+	if len(results) == 0 {
+		fmt.Println("No results found.")
+		return nil
+	}
+
+	fmt.Printf("Found %d result(s):\n\n", len(results))
+	if !expand {
+		width := len("CONTEXT")
+		for _, result := range results {
+			width = max(width, len(result.Context))
+		}
+		fmt.Printf("%-*s  %s\n", width, "CONTEXT", "ID")
+		for _, result := range results {
+			fmt.Printf("%-*s  %s\n", width, result.Context, result.ID)
+		}
+		return nil
+	}
+
+	for i, result := range results {
+		fmt.Printf("Result %d:\n", i+1)
+		fmt.Printf("  Context: %s\n", result.Context)
+		printExpandedResult(&result.ToolsFindExpandedResource)
+	}
+
+	return nil
+}
+
 func displayExpandedResults(results []types.ToolsFindExpandedResource, jsonOutput bool) error {
 	if jsonOutput {
 		data, err := json.MarshalIndent(results, "", "  ")
@@ -223,40 +456,45 @@ func displayExpandedResults(results []types.ToolsFindExpandedResource, jsonOutpu
 
 	for i, result := range results {
 		fmt.Printf("Result %d:\n", i+1)
-		fmt.Printf("  ID: %s\n", result.ID)
-		fmt.Printf("  Type: %s\n", result.Type)
+		printExpandedResult(&result)
+	}
 
-		if result.IsStable {
-			fmt.Printf("  Stable: Yes\n")
-		}
+	return nil
+}
 
-		if result.LastUpdate != "" {
-			fmt.Printf("  Last Update: %s\n", result.LastUpdate)
-		}
+// printExpandedResult prints the details of a found resource
+func printExpandedResult(result *types.ToolsFindExpandedResource) {
+	fmt.Printf("  ID: %s\n", result.ID)
+	fmt.Printf("  Type: %s\n", result.Type)
 
-		// Display main DICOM tags
-		if len(result.MainDicomTags) > 0 {
-			fmt.Println("  Main DICOM Tags:")
-			for key, value := range result.MainDicomTags {
-				fmt.Printf("    %s: %v\n", key, value)
-			}
-		}
+	if result.IsStable {
+		fmt.Printf("  Stable: Yes\n")
+	}
 
-		// Display patient DICOM tags if available
-		if len(result.PatientMainDicomTags) > 0 {
-			fmt.Println("  Patient DICOM Tags:")
-			for key, value := range result.PatientMainDicomTags {
-				fmt.Printf("    %s: %v\n", key, value)
-			}
+	if result.LastUpdate != "" {
+		fmt.Printf("  Last Update: %s\n", result.LastUpdate)
+	}
+
+	// Display main DICOM tags
+	if len(result.MainDicomTags) > 0 {
+		fmt.Println("  Main DICOM Tags:")
+		for key, value := range result.MainDicomTags {
+			fmt.Printf("    %s: %v\n", key, value)
 		}
+	}
 
-		// Display labels if available
-		if len(result.Labels) > 0 {
-			fmt.Printf("  Labels: %v\n", result.Labels)
+	// Display patient DICOM tags if available
+	if len(result.PatientMainDicomTags) > 0 {
+		fmt.Println("  Patient DICOM Tags:")
+		for key, value := range result.PatientMainDicomTags {
+			fmt.Printf("    %s: %v\n", key, value)
 		}
+	}
 
-		fmt.Println()
+	// Display labels if available
+	if len(result.Labels) > 0 {
+		fmt.Printf("  Labels: %v\n", result.Labels)
 	}
 
-	return nil
+	fmt.Println()
 }
@@ -1,12 +1,27 @@
 package tools
 
 import (
+	"context"
+	"encoding/json"
 	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"os/signal"
+	"strings"
+	"syscall"
+	"time"
 
 	"github.com/proencaj/gorthanc/types"
+	"github.com/proencaj/orthanc-cli/internal/client"
+	"github.com/proencaj/orthanc-cli/internal/clierr"
+	"github.com/proencaj/orthanc-cli/internal/guard"
 	"github.com/spf13/cobra"
 )
 
+// logCategories lists the log categories known to Orthanc (1.9.0 and later)
+var logCategories = []string{"generic", "http", "dicom", "lua", "plugins", "sqlite", "jobs"}
+
 // NewLogLevelCommand creates the tools log-level command with get and set subcommands
 func NewLogLevelCommand() *cobra.Command {
 	command := &cobra.Command{
@@ -18,6 +33,7 @@ func NewLogLevelCommand() *cobra.Command {
 	// Add subcommands
 	command.AddCommand(NewLogLevelGetCommand())
 	command.AddCommand(NewLogLevelSetCommand())
+	command.AddCommand(NewLogLevelCategoriesCommand())
 
 	return command
 }
@@ -48,28 +64,90 @@ func runLogLevelGet() error {
 
 	// Get the log level
 	level, err := client.GetLogLevel()
-	fmt.Println(level)
 	if err != nil {
 		return fmt.Errorf("failed to get log level: %w", err)
 	}
 
 	fmt.Printf("Current log level: %s\n", level)
+	printLogLevelDescription(level)
 
-	// Provide context about the log level
-	switch level {
-	case types.LogLevelDefault:
-		fmt.Println("  (Shows only WARNING and ERROR messages)")
-	case types.LogLevelVerbose:
-		fmt.Println("  (Includes INFO level messages)")
-	case types.LogLevelTrace:
-		fmt.Println("  (Includes detailed TRACE level messages for debugging)")
+	return nil
+}
+
+// LogLevelCategoriesFlags holds the flags for the log-level categories command
+type LogLevelCategoriesFlags struct {
+	jsonOutput bool
+}
+
+// NewLogLevelCategoriesCommand creates the log-level categories command
+func NewLogLevelCategoriesCommand() *cobra.Command {
+	flags := &LogLevelCategoriesFlags{}
+
+	command := &cobra.Command{
+		Use:   "categories",
+		Short: "List log categories and their current levels",
+		Long: `List the log categories of the Orthanc server with their current log level.
+
+Per-category log levels require Orthanc 1.9.0 or later.`,
+		Example: `  # List log categories
+  orthanc tools log-level categories
+
+  # Output as JSON
+  orthanc tools log-level categories --json`,
+		Args: cobra.NoArgs,
+		RunE: func(c *cobra.Command, args []string) error {
+			return runLogLevelCategories(flags)
+		},
+	}
+
+	// Add flags
+	command.Flags().BoolVar(&flags.jsonOutput, "json", false, "Output in JSON format")
+
+	return command
+}
+
+func runLogLevelCategories(flags *LogLevelCategoriesFlags) error {
+	// Get the Orthanc client
+	client, err := getClient()
+	if err != nil {
+		return fmt.Errorf("failed to create client: %w", err)
+	}
+
+	if err := requireLogCategories(client); err != nil {
+		return err
+	}
+
+	levels, err := getCategoryLogLevels(client, logCategories)
+	if err != nil {
+		return err
+	}
+
+	if flags.jsonOutput || shouldUseJSON() {
+		data, err := json.MarshalIndent(levels, "", "  ")
+		if err != nil {
+			return fmt.Errorf("failed to marshal JSON: %w", err)
+		}
+		fmt.Println(string(data))
+		return nil
+	}
+
+	for _, category := range logCategories {
+		fmt.Printf("%-10s %s\n", category, levels[category])
 	}
 
 	return nil
 }
 
+// LogLevelSetFlags holds the flags for the log-level set command
+type LogLevelSetFlags struct {
+	categories []string
+	duration   time.Duration
+}
+
 // NewLogLevelSetCommand creates the log-level set command
 func NewLogLevelSetCommand() *cobra.Command {
+	flags := &LogLevelSetFlags{}
+
 	command := &cobra.Command{
 		Use:   "set <level>",
 		Short: "Set the log level",
@@ -80,26 +158,42 @@ Valid log levels:
   - verbose: Adds INFO level messages
   - trace:   Includes detailed TRACE level messages for debugging
 
-Note: This resets all category-specific log levels.`,
+Without --category the global log level is changed, which resets all
+category-specific log levels. With --category only the given categories
+are changed (see "orthanc tools log-level categories").
+
+With --for the command stays in the foreground and restores the previous
+log levels once the duration has elapsed, or when interrupted with Ctrl-C.`,
 		Example: `  # Set log level to verbose
   orthanc tools log-level set verbose
 
   # Set log level to trace for debugging
   orthanc tools log-level set trace
 
+  # Trace DICOM traffic only
+  orthanc tools log-level set trace --category dicom
+
+  # Trace DICOM and HTTP for 10 minutes, then restore the previous levels
+  orthanc tools log-level set trace --category dicom,http --for 10m
+
   # Set log level back to default
   orthanc tools log-level set default`,
-		Args: cobra.ExactArgs(1),
-		ValidArgs: []string{"default", "verbose", "trace"},
+		Annotations: map[string]string{guard.Annotation: guard.Mutating},
+		Args:        cobra.ExactArgs(1),
+		ValidArgs:   []string{"default", "verbose", "trace"},
 		RunE: func(c *cobra.Command, args []string) error {
-			return runLogLevelSet(args[0])
+			return runLogLevelSet(args[0], flags)
 		},
 	}
 
+	// Add flags
+	command.Flags().StringSliceVar(&flags.categories, "category", nil, "Log category to change (can be repeated or comma-separated)")
+	command.Flags().DurationVar(&flags.duration, "for", 0, "Restore the previous log levels after this duration (e.g. 10m)")
+
 	return command
 }
 
-func runLogLevelSet(levelStr string) error {
+func runLogLevelSet(levelStr string, flags *LogLevelSetFlags) error {
 	// Validate log level
 	var level types.LogLevel
 	switch levelStr {
@@ -110,7 +204,17 @@ func runLogLevelSet(levelStr string) error {
 	case "trace":
 		level = types.LogLevelTrace
 	default:
-		return fmt.Errorf("invalid log level '%s', must be one of: default, verbose, trace", levelStr)
+		return clierr.Validation("invalid log level '%s', must be one of: default, verbose, trace", levelStr)
+	}
+
+	for _, category := range flags.categories {
+		if !isLogCategory(category) {
+			return clierr.Validation("invalid log category '%s', must be one of: %s", category, strings.Join(logCategories, ", "))
+		}
+	}
+
+	if flags.duration < 0 {
+		return clierr.Validation("--for must be a positive duration")
 	}
 
 	// Get the Orthanc client
@@ -119,15 +223,197 @@ func runLogLevelSet(levelStr string) error {
 		return fmt.Errorf("failed to create client: %w", err)
 	}
 
-	// Set the log level
-	err = client.SetLogLevel(level)
-	if err != nil {
-		return fmt.Errorf("failed to set log level: %w", err)
+	if len(flags.categories) > 0 {
+		if err := requireLogCategories(client); err != nil {
+			return err
+		}
+	}
+
+	// Remember the current levels so they can be restored, after the duration
+	// or when setting one of several categories fails
+	var snapshot *logLevelSnapshot
+	if flags.duration > 0 || len(flags.categories) > 1 {
+		snapshot, err = takeLogLevelSnapshot(client, flags.categories)
+		if err != nil {
+			return err
+		}
 	}
 
-	fmt.Printf("Log level set to: %s\n", level)
+	if len(flags.categories) > 0 {
+		for i, category := range flags.categories {
+			if err := setCategoryLogLevel(client, category, string(level)); err != nil {
+				if i > 0 {
+					if restoreErr := snapshot.restore(client); restoreErr != nil {
+						fmt.Fprintf(os.Stderr, "Warning: %v\n", restoreErr)
+					}
+				}
+				return err
+			}
+		}
+		fmt.Printf("Log level of %s set to: %s\n", strings.Join(flags.categories, ", "), level)
+	} else {
+		// Set the log level
+		err = client.SetLogLevel(level)
+		if err != nil {
+			return fmt.Errorf("failed to set log level: %w", err)
+		}
+		fmt.Printf("Log level set to: %s\n", level)
+	}
 
 	// Provide context about the new log level
+	printLogLevelDescription(level)
+
+	if flags.duration > 0 {
+		return waitAndRestore(client, snapshot, flags.duration)
+	}
+
+	fmt.Println("\nNote: This change is temporary and will be reset if the server restarts.")
+	fmt.Println("To make it permanent, update the configuration file and restart the server.")
+
+	return nil
+}
+
+// logLevelSnapshot holds log levels to restore
+type logLevelSnapshot struct {
+	// global is the global level, empty if only categories were changed
+	global types.LogLevel
+	// categories maps category names to their level
+	categories map[string]string
+}
+
+// takeLogLevelSnapshot records the levels that setting the given categories
+// (or the global level when none are given) would change
+func takeLogLevelSnapshot(c *client.Client, categories []string) (*logLevelSnapshot, error) {
+	snapshot := &logLevelSnapshot{}
+
+	if len(categories) == 0 {
+		level, err := c.GetLogLevel()
+		if err != nil {
+			return nil, fmt.Errorf("failed to get log level: %w", err)
+		}
+		snapshot.global = level
+
+		// The global level resets every category, so remember them too when the server has them
+		if requireLogCategories(c) != nil {
+			return snapshot, nil
+		}
+		categories = logCategories
+	}
+
+	levels, err := getCategoryLogLevels(c, categories)
+	if err != nil {
+		return nil, err
+	}
+	snapshot.categories = levels
+
+	return snapshot, nil
+}
+
+// restore puts the recorded log levels back, global level first
+func (s *logLevelSnapshot) restore(c *client.Client) error {
+	if s.global != "" {
+		if err := c.SetLogLevel(s.global); err != nil {
+			return fmt.Errorf("failed to restore log level: %w", err)
+		}
+	}
+
+	for _, category := range logCategories {
+		level, ok := s.categories[category]
+		if !ok {
+			continue
+		}
+		if err := setCategoryLogLevel(c, category, level); err != nil {
+			return fmt.Errorf("failed to restore log level: %w", err)
+		}
+	}
+
+	return nil
+}
+
+// waitAndRestore blocks until the duration has elapsed or the command is
+// interrupted, hung up or terminated, then restores the previous log levels
+func waitAndRestore(c *client.Client, snapshot *logLevelSnapshot, duration time.Duration) error {
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM, syscall.SIGHUP)
+	defer stop()
+
+	fmt.Printf("\nPrevious log levels will be restored at %s (press Ctrl-C to restore now)...\n",
+		time.Now().Add(duration).Format("15:04:05"))
+
+	timer := time.NewTimer(duration)
+	defer timer.Stop()
+
+	select {
+	case <-timer.C:
+	case <-ctx.Done():
+		fmt.Println()
+	}
+
+	if err := snapshot.restore(c); err != nil {
+		return err
+	}
+
+	if snapshot.global != "" {
+		fmt.Printf("Log level restored to: %s\n", snapshot.global)
+	}
+	for _, category := range logCategories {
+		if level, ok := snapshot.categories[category]; ok && snapshot.global == "" {
+			fmt.Printf("Log level of %s restored to: %s\n", category, level)
+		}
+	}
+
+	return nil
+}
+
+// getCategoryLogLevels reads the log level of each category
+func getCategoryLogLevels(c *client.Client, categories []string) (map[string]string, error) {
+	levels := make(map[string]string, len(categories))
+	for _, category := range categories {
+		level, err := getCategoryLogLevel(c, category)
+		if err != nil {
+			return nil, err
+		}
+		levels[category] = level
+	}
+	return levels, nil
+}
+
+// getCategoryLogLevel reads the log level of a category
+func getCategoryLogLevel(c *client.Client, category string) (string, error) {
+	resp, err := c.Do(http.MethodGet, "tools/log-level-"+category, nil, "")
+	if err != nil {
+		return "", fmt.Errorf("failed to get log level of %s: %w", category, err)
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return "", fmt.Errorf("failed to read response body: %w", err)
+	}
+	return strings.TrimSpace(string(body)), nil
+}
+
+// setCategoryLogLevel changes the log level of a category
+func setCategoryLogLevel(c *client.Client, category, level string) error {
+	resp, err := c.Do(http.MethodPut, "tools/log-level-"+category, strings.NewReader(level), "text/plain")
+	if err != nil {
+		return fmt.Errorf("failed to set log level of %s: %w", category, err)
+	}
+	resp.Body.Close()
+	return nil
+}
+
+// isLogCategory reports whether name is a known log category
+func isLogCategory(name string) bool {
+	for _, category := range logCategories {
+		if category == name {
+			return true
+		}
+	}
+	return false
+}
+
+// printLogLevelDescription explains what a log level shows
+func printLogLevelDescription(level types.LogLevel) {
 	switch level {
 	case types.LogLevelDefault:
 		fmt.Println("  (Shows only WARNING and ERROR messages)")
@@ -136,9 +422,9 @@ func runLogLevelSet(levelStr string) error {
 	case types.LogLevelTrace:
 		fmt.Println("  (Includes detailed TRACE level messages for debugging)")
 	}
+}
 
-	fmt.Println("\nNote: This change is temporary and will be reset if the server restarts.")
-	fmt.Println("To make it permanent, update the configuration file and restart the server.")
-
-	return nil
+// requireLogCategories fails early on servers without per-category log levels
+func requireLogCategories(c *client.Client) error {
+	return c.RequireFeature(client.FeatureLogCategories)
 }
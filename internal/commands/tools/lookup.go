@@ -0,0 +1,146 @@
+package tools
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/proencaj/gorthanc/types"
+	"github.com/proencaj/orthanc-cli/internal/client"
+	"github.com/proencaj/orthanc-cli/internal/clierr"
+	"github.com/spf13/cobra"
+)
+
+// LookupFlags holds the flags for the lookup command
+type LookupFlags struct {
+	level      string
+	jsonOutput bool
+}
+
+// NewLookupCommand creates the tools lookup command
+func NewLookupCommand() *cobra.Command {
+	flags := &LookupFlags{}
+
+	command := &cobra.Command{
+		Use:   "lookup <value>",
+		Short: "Find the Orthanc ID of a DICOM identifier",
+		Long: `Map a DICOM identifier to Orthanc IDs.
+
+A plain value is looked up as a PatientID, StudyInstanceUID, SeriesInstanceUID
+or SOPInstanceUID. The prefixes acc:<AccessionNumber> and pid:<PatientID>
+search the corresponding tag instead.
+
+This is the same resolution that per-resource commands such as
+"studies get" apply to their arguments.`,
+		Example: `  # Look up a StudyInstanceUID
+  orthanc tools lookup 1.2.840.113619.2.55.3.604688119.969.1268071029.320
+
+  # Look up a study by accession number
+  orthanc tools lookup acc:A12345
+
+  # Look up the studies of a patient
+  orthanc tools lookup pid:12345 --level Study
+
+  # Output as JSON
+  orthanc tools lookup 1.2.3.4 --json`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(c *cobra.Command, args []string) error {
+			return runLookup(args[0], flags)
+		},
+	}
+
+	// Add flags
+	command.Flags().StringVarP(&flags.level, "level", "l", "", "Only return resources at this level (Patient, Study, Series, Instance)")
+	command.Flags().BoolVar(&flags.jsonOutput, "json", false, "Output in JSON format")
+
+	return command
+}
+
+func runLookup(value string, flags *LookupFlags) error {
+	level := types.ResourceLevel(flags.level)
+	switch level {
+	case "", types.ResourceLevelPatient, types.ResourceLevelStudy, types.ResourceLevelSeries, types.ResourceLevelInstance:
+	default:
+		return clierr.Validation("invalid level '%s', must be one of: Patient, Study, Series, Instance", flags.level)
+	}
+
+	// Get the Orthanc client
+	client, err := getClient()
+	if err != nil {
+		return fmt.Errorf("failed to create client: %w", err)
+	}
+
+	results, err := lookupResources(client, level, value)
+	if err != nil {
+		return err
+	}
+
+	if len(results) == 0 {
+		return clierr.NotFound("no resource matches '%s'", value)
+	}
+
+	if flags.jsonOutput || shouldUseJSON() {
+		data, err := json.MarshalIndent(results, "", "  ")
+		if err != nil {
+			return fmt.Errorf("failed to marshal JSON: %w", err)
+		}
+		fmt.Println(string(data))
+		return nil
+	}
+
+	for _, result := range results {
+		fmt.Printf("%-8s %s\n", result.Type, result.ID)
+	}
+
+	return nil
+}
+
+// lookupResources finds the resources matching a plain or prefixed identifier
+func lookupResources(c *client.Client, level types.ResourceLevel, value string) ([]client.LookupResult, error) {
+	tag, query := "", value
+	if rest, ok := strings.CutPrefix(value, client.PrefixAccession); ok {
+		tag, query = "AccessionNumber", rest
+		if level == "" {
+			level = types.ResourceLevelStudy
+		}
+	} else if rest, ok := strings.CutPrefix(value, client.PrefixPatientID); ok {
+		tag, query = "PatientID", rest
+		if level == "" {
+			level = types.ResourceLevelPatient
+		}
+	} else if rest, ok := strings.CutPrefix(value, client.PrefixUID); ok {
+		query = rest
+	}
+
+	if query == "" {
+		return nil, clierr.Validation("empty identifier '%s'", value)
+	}
+
+	if tag != "" {
+		ids, err := c.Find(&types.ToolsFindRequest{
+			Level: level,
+			Query: map[string]string{tag: query},
+		})
+		if err != nil {
+			return nil, fmt.Errorf("failed to find resources: %w", err)
+		}
+		results := make([]client.LookupResult, 0, len(ids))
+		for _, id := range ids {
+			results = append(results, client.LookupResult{ID: id, Type: string(level)})
+		}
+		return results, nil
+	}
+
+	found, err := c.Lookup(query)
+	if err != nil {
+		return nil, fmt.Errorf("failed to look up '%s': %w", value, err)
+	}
+
+	results := []client.LookupResult{}
+	for _, result := range found {
+		if level == "" || result.Type == string(level) {
+			results = append(results, result)
+		}
+	}
+	return results, nil
+}
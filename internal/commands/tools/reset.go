@@ -3,6 +3,7 @@ package tools
 import (
 	"fmt"
 
+	"github.com/proencaj/orthanc-cli/internal/guard"
 	"github.com/spf13/cobra"
 )
 
@@ -28,6 +29,10 @@ WARNING: This will temporarily interrupt all ongoing operations.`,
 
   # Force reset without confirmation
   orthanc tools reset --force`,
+		Annotations: map[string]string{
+			guard.Annotation:  guard.Destructive,
+			guard.ConfirmFlag: "force",
+		},
 		Args: cobra.NoArgs,
 		RunE: func(c *cobra.Command, args []string) error {
 			return runReset(flags)
@@ -47,8 +52,8 @@ func runReset(flags *ResetFlags) error {
 		return fmt.Errorf("failed to create client: %w", err)
 	}
 
-	// Confirmation prompt unless force is used
-	if !flags.force {
+	// Confirmation prompt unless forced or already confirmed for a protected context
+	if !flags.force && !guard.Confirmed() {
 		fmt.Println("WARNING: This will perform a hot restart of the Orthanc server.")
 		fmt.Println("All ongoing operations will be temporarily interrupted.")
 		fmt.Print("Are you sure you want to continue? (yes/no): ")
@@ -3,6 +3,7 @@ package tools
 import (
 	"fmt"
 
+	"github.com/proencaj/orthanc-cli/internal/guard"
 	"github.com/spf13/cobra"
 )
 
@@ -27,6 +28,10 @@ You will need to manually restart the Orthanc process after shutdown.`,
 
   # Force shutdown without confirmation
   orthanc tools shutdown --force`,
+		Annotations: map[string]string{
+			guard.Annotation:  guard.Destructive,
+			guard.ConfirmFlag: "force",
+		},
 		Args: cobra.NoArgs,
 		RunE: func(c *cobra.Command, args []string) error {
 			return runShutdown(flags)
@@ -46,8 +51,8 @@ func runShutdown(flags *ShutdownFlags) error {
 		return fmt.Errorf("failed to create client: %w", err)
 	}
 
-	// Confirmation prompt unless force is used
-	if !flags.force {
+	// Confirmation prompt unless forced or already confirmed for a protected context
+	if !flags.force && !guard.Confirmed() {
 		fmt.Println("WARNING: This will shut down the Orthanc server completely.")
 		fmt.Println("You will need to manually restart the Orthanc process.")
 		fmt.Print("Are you sure you want to continue? (yes/no): ")
@@ -41,14 +41,17 @@ func NewToolsCommand() *cobra.Command {
 	toolsCmd := &cobra.Command{
 		Use:   "tools",
 		Short: "Orthanc server tools and utilities",
-		Long:  `Access various Orthanc server tools including search, reset, and configuration utilities.`,
+		Long:  `Access various Orthanc server tools including search, Lua scripting, reset, and configuration utilities.`,
 	}
 
 	// Add subcommands
 	toolsCmd.AddCommand(NewFindCommand())
+	toolsCmd.AddCommand(NewLookupCommand())
 	toolsCmd.AddCommand(NewResetCommand())
 	toolsCmd.AddCommand(NewShutdownCommand())
 	toolsCmd.AddCommand(NewLogLevelCommand())
+	toolsCmd.AddCommand(NewExecuteScriptCommand())
+	toolsCmd.AddCommand(NewCreateDicomCommand())
 
 	return toolsCmd
 }
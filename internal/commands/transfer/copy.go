@@ -0,0 +1,290 @@
+package transfer
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+	"strings"
+	"sync"
+
+	"github.com/proencaj/gorthanc/types"
+	"github.com/proencaj/orthanc-cli/internal/batch"
+	"github.com/proencaj/orthanc-cli/internal/client"
+	"github.com/proencaj/orthanc-cli/internal/clierr"
+	"github.com/proencaj/orthanc-cli/internal/download"
+	"github.com/proencaj/orthanc-cli/internal/guard"
+	"github.com/proencaj/orthanc-cli/internal/parallel"
+	"github.com/spf13/cobra"
+)
+
+// levels are the valid values of --level
+var levels = map[types.ResourceLevel]bool{
+	types.ResourceLevelPatient:  true,
+	types.ResourceLevelStudy:    true,
+	types.ResourceLevelSeries:   true,
+	types.ResourceLevelInstance: true,
+}
+
+// CopyFlags holds the flags for the copy command
+type CopyFlags struct {
+	from       string
+	to         string
+	level      string
+	parallel   int
+	move       bool
+	jsonOutput bool
+}
+
+// copyInstance is an instance to copy, with the UID checked on arrival
+type copyInstance struct {
+	ID            string `json:"ID"`
+	MainDicomTags struct {
+		SOPInstanceUID string `json:"SOPInstanceUID"`
+	} `json:"MainDicomTags"`
+}
+
+// copyReport is the JSON output for a copied resource
+type copyReport struct {
+	ID             string `json:"ID"`
+	Level          string `json:"Level"`
+	From           string `json:"From"`
+	To             string `json:"To"`
+	CountInstances int    `json:"CountInstances"`
+	Copied         int    `json:"Copied"`
+	AlreadyStored  int    `json:"AlreadyStored"`
+	Moved          bool   `json:"Moved"`
+}
+
+// NewCopyCommand creates the copy command
+func NewCopyCommand() *cobra.Command {
+	flags := &CopyFlags{}
+
+	command := &cobra.Command{
+		Use:   "copy <resource-id>...",
+		Short: "Copy resources from one context to another",
+		Long: `Copy patients, studies, series or instances from the Orthanc server of one
+context to the server of another, instance by instance.
+
+Each DICOM file is streamed from the source straight into the destination,
+without touching the local disk, and its SOPInstanceUID is checked on the
+destination once stored. With --move, a resource is deleted from the source
+only after all of its instances have been copied and checked.
+
+The source defaults to the current context. The read-only and protected
+settings of both contexts apply.`,
+		Example: `  # Copy a study from the current context to the archive context
+  orthanc copy --to archive 1a2b3c4d-5e6f7a8b-9c0d1e2f-3a4b5c6d-7e8f9a0b
+
+  # Move studies listed in a file from staging to production
+  orthanc copy --from staging --to production --move - < studies.txt
+
+  # Copy a series with 8 concurrent transfers
+  orthanc copy --to archive --level Series --parallel 8 5e6f7a8b-9c0d1e2f-3a4b5c6d-7e8f9a0b-1a2b3c4d`,
+		Annotations: map[string]string{guard.Annotation: guard.CrossContext},
+		Args:        cobra.MinimumNArgs(1),
+		RunE: func(c *cobra.Command, args []string) error {
+			return runCopy(c, args, flags)
+		},
+	}
+
+	// Add flags
+	command.Flags().StringVar(&flags.from, "from", "", "Source context (default: current context)")
+	command.Flags().StringVar(&flags.to, "to", "", "Destination context")
+	command.Flags().StringVar(&flags.level, "level", "Study", "Resource level (Patient, Study, Series, Instance)")
+	command.Flags().IntVar(&flags.parallel, "parallel", parallel.DefaultWorkers, "Number of instances copied in parallel")
+	command.Flags().BoolVar(&flags.move, "move", false, "Delete the resources from the source once copied and verified")
+	command.Flags().BoolVar(&flags.jsonOutput, "json", false, "Output in JSON format")
+	command.MarkFlagRequired("to")
+
+	return command
+}
+
+func runCopy(command *cobra.Command, args []string, flags *CopyFlags) error {
+	ids, err := batch.ReadIDs(args)
+	if err != nil {
+		return err
+	}
+
+	// Validate level
+	level := types.ResourceLevel(flags.level)
+	if !levels[level] {
+		return clierr.Validation("invalid level '%s', must be one of: Patient, Study, Series, Instance", flags.level)
+	}
+	if flags.parallel < 1 {
+		return clierr.Validation("--parallel must be at least 1")
+	}
+
+	// Check if JSON output should be used (flag or config)
+	jsonOutput := flags.jsonOutput || shouldUseJSON()
+
+	// Get the Orthanc clients of both contexts
+	src, dst, err := getClients(flags.from, flags.to)
+	if err != nil {
+		return err
+	}
+	recordTransfer(src, dst)
+
+	// Enforce the read-only and protected settings of both contexts
+	sourceEffect := ""
+	if flags.move {
+		sourceEffect = guard.Destructive
+	}
+	if err := guard.CheckContext(command, src.GetConfig(), src.ContextName(), sourceEffect); err != nil {
+		return err
+	}
+	if err := guard.CheckContext(command, dst.GetConfig(), dst.ContextName(), guard.Mutating); err != nil {
+		return err
+	}
+
+	// Resources are copied one at a time, their instances in parallel
+	reports := make([]*copyReport, len(ids))
+	return batch.Run(ids, 1, func(i int) error {
+		report, err := copyResource(src, dst, level, ids[i], flags)
+		reports[i] = report
+		return err
+	}, func(i int) error {
+		report := reports[i]
+		if jsonOutput {
+			data, err := json.MarshalIndent(report, "", "  ")
+			if err != nil {
+				return fmt.Errorf("failed to marshal JSON: %w", err)
+			}
+			fmt.Println(string(data))
+			return nil
+		}
+		verb := "copied"
+		if report.Moved {
+			verb = "moved"
+		}
+		fmt.Printf("Successfully %s %s %s from '%s' to '%s'\n", verb, strings.ToLower(report.Level), report.ID, report.From, report.To)
+		fmt.Printf("Instances: %d, copied: %d, already stored: %d\n", report.CountInstances, report.Copied, report.AlreadyStored)
+		return nil
+	})
+}
+
+// copyResource copies the instances of a resource and, with --move, deletes
+// it from the source once every instance is verified
+func copyResource(src, dst *client.Client, level types.ResourceLevel, id string, flags *CopyFlags) (*copyReport, error) {
+	// Resolve DICOM UIDs and prefixed identifiers to the Orthanc ID
+	id, err := src.ResolveID(level, id)
+	if err != nil {
+		return nil, err
+	}
+
+	instances, err := listInstances(src, level, id)
+	if err != nil {
+		return nil, err
+	}
+	if len(instances) == 0 {
+		return nil, clierr.NotFound("%s %s has no instances", strings.ToLower(string(level)), id)
+	}
+
+	showProgress := download.IsTerminal(os.Stderr)
+	var mu sync.Mutex
+	done := 0
+	statuses := make([]string, len(instances))
+	errs := parallel.ForEach(len(instances), flags.parallel, func(i int) error {
+		var err error
+		statuses[i], err = transferInstance(src, dst, &instances[i])
+
+		mu.Lock()
+		defer mu.Unlock()
+		done++
+		if showProgress {
+			fmt.Fprintf(os.Stderr, "\rCopied %d/%d instance(s)", done, len(instances))
+		}
+		return err
+	})
+	if showProgress {
+		fmt.Fprintln(os.Stderr)
+	}
+
+	report := &copyReport{
+		ID:             id,
+		Level:          string(level),
+		From:           src.ContextName(),
+		To:             dst.ContextName(),
+		CountInstances: len(instances),
+	}
+	for _, status := range statuses {
+		switch status {
+		case "":
+		case "AlreadyStored":
+			report.AlreadyStored++
+		default:
+			report.Copied++
+		}
+	}
+
+	// Never delete the source unless every instance arrived
+	if failed := parallel.Failed(errs); failed > 0 {
+		for i, err := range errs {
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "Failed to copy instance %s: %v\n", instances[i].ID, err)
+			}
+		}
+		if flags.move {
+			return nil, fmt.Errorf("failed to copy %d of %d instance(s) of %s, source kept", failed, len(instances), id)
+		}
+		return nil, fmt.Errorf("failed to copy %d of %d instance(s) of %s", failed, len(instances), id)
+	}
+
+	if flags.move {
+		if err := src.DeleteResource(level, id); err != nil {
+			return nil, fmt.Errorf("copied %s but failed to delete it from the source: %w", id, err)
+		}
+		report.Moved = true
+	}
+
+	return report, nil
+}
+
+// listInstances returns the instances of a resource with their SOPInstanceUID
+func listInstances(c *client.Client, level types.ResourceLevel, id string) ([]copyInstance, error) {
+	if level == types.ResourceLevelInstance {
+		var instance copyInstance
+		if err := c.GetJSON("instances/"+id, &instance); err != nil {
+			return nil, fmt.Errorf("failed to fetch instance: %w", err)
+		}
+		return []copyInstance{instance}, nil
+	}
+
+	var instances []copyInstance
+	if err := c.GetJSON(client.ResourcePath(level, id)+"/instances?expand", &instances); err != nil {
+		return nil, fmt.Errorf("failed to fetch instances: %w", err)
+	}
+	return instances, nil
+}
+
+// transferInstance streams one DICOM file from src to dst, checks its
+// SOPInstanceUID on the destination and returns the upload status
+func transferInstance(src, dst *client.Client, instance *copyInstance) (string, error) {
+	resp, err := src.Stream(http.MethodGet, "instances/"+instance.ID+"/file", nil, "")
+	if err != nil {
+		return "", fmt.Errorf("failed to download: %w", err)
+	}
+	defer resp.Body.Close()
+
+	upload, err := dst.Upload(http.MethodPost, "instances", resp.Body, resp.ContentLength, "application/dicom")
+	if err != nil {
+		return "", fmt.Errorf("failed to upload: %w", err)
+	}
+	defer upload.Body.Close()
+
+	var result types.UploadDicomFileResponse
+	if err := json.NewDecoder(upload.Body).Decode(&result); err != nil {
+		return "", fmt.Errorf("failed to decode upload response: %w", err)
+	}
+
+	var stored copyInstance
+	if err := dst.GetJSON("instances/"+result.ID, &stored); err != nil {
+		return "", fmt.Errorf("failed to verify: %w", err)
+	}
+	if stored.MainDicomTags.SOPInstanceUID != instance.MainDicomTags.SOPInstanceUID {
+		return "", fmt.Errorf("SOPInstanceUID mismatch on destination: expected %s, got %s",
+			instance.MainDicomTags.SOPInstanceUID, stored.MainDicomTags.SOPInstanceUID)
+	}
+
+	return result.Status, nil
+}
@@ -0,0 +1,361 @@
+package transfer
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/proencaj/gorthanc/types"
+	"github.com/proencaj/orthanc-cli/internal/client"
+	"github.com/proencaj/orthanc-cli/internal/clierr"
+	"github.com/proencaj/orthanc-cli/internal/helpers"
+	"github.com/proencaj/orthanc-cli/internal/parallel"
+	"github.com/spf13/cobra"
+)
+
+// uidTags are the tags identifying a resource across servers, by level
+var uidTags = map[types.ResourceLevel]string{
+	types.ResourceLevelPatient: "PatientID",
+	types.ResourceLevelStudy:   "StudyInstanceUID",
+	types.ResourceLevelSeries:  "SeriesInstanceUID",
+}
+
+// DiffFlags holds the flags for the diff command
+type DiffFlags struct {
+	level      string
+	tags       map[string]string
+	labels     []string
+	md5        bool
+	parallel   int
+	exitCode   bool
+	jsonOutput bool
+}
+
+// inventoryResource is a resource of one side of a diff
+type inventoryResource struct {
+	UID            string `json:"UID"`
+	ID             string `json:"ID"`
+	CountInstances int    `json:"CountInstances,omitempty"`
+	// digest lists the SOPInstanceUID and MD5 of every instance, with --md5
+	digest string
+}
+
+// diffMismatch is a resource present on both sides with different content
+type diffMismatch struct {
+	UID    string             `json:"UID"`
+	Reason string             `json:"Reason"`
+	A      *inventoryResource `json:"A"`
+	B      *inventoryResource `json:"B"`
+}
+
+// diffReport is the JSON output of the diff command
+type diffReport struct {
+	A          string               `json:"A"`
+	B          string               `json:"B"`
+	Level      string               `json:"Level"`
+	CountA     int                  `json:"CountA"`
+	CountB     int                  `json:"CountB"`
+	Matching   int                  `json:"Matching"`
+	OnlyInA    []*inventoryResource `json:"OnlyInA"`
+	OnlyInB    []*inventoryResource `json:"OnlyInB"`
+	Mismatched []*diffMismatch      `json:"Mismatched"`
+}
+
+// differs reports whether the two sides of a diff differ
+func (r *diffReport) differs() bool {
+	return len(r.OnlyInA) > 0 || len(r.OnlyInB) > 0 || len(r.Mismatched) > 0
+}
+
+// NewDiffCommand creates the diff command
+func NewDiffCommand() *cobra.Command {
+	flags := &DiffFlags{}
+
+	command := &cobra.Command{
+		Use:   "diff <context-a> <context-b>",
+		Short: "Compare the resources stored by two contexts",
+		Long: `Compare the patients, studies or series stored by the Orthanc servers of two
+contexts, matched by PatientID, StudyInstanceUID or SeriesInstanceUID.
+
+Resources are reported as only in A, only in B, or mismatched when both
+servers have them with a different number of instances. With --md5, resources
+with the same number of instances are also compared instance by instance, by
+SOPInstanceUID and MD5 of the stored file; this sends one request per
+instance.
+
+Use --tag and --label to compare only part of the servers, as with tools find.`,
+		Example: `  # Find the studies missing from the replacement of a node
+  orthanc diff old-node new-node
+
+  # Compare the series of 2024, including file checksums
+  orthanc diff old-node new-node --level Series --tag StudyDate=20240101-20241231 --md5
+
+  # Fail in a script if the contexts differ
+  orthanc diff old-node new-node --exit-code --json > diff.json`,
+		Args: cobra.ExactArgs(2),
+		RunE: func(c *cobra.Command, args []string) error {
+			return runDiff(args[0], args[1], flags)
+		},
+	}
+
+	// Add flags
+	addInventoryFlags(command, &flags.level, &flags.tags, &flags.labels, &flags.md5, &flags.parallel)
+	command.Flags().BoolVar(&flags.exitCode, "exit-code", false, "Exit with status 1 if the contexts differ")
+	command.Flags().BoolVar(&flags.jsonOutput, "json", false, "Output in JSON format")
+
+	return command
+}
+
+// addInventoryFlags adds the flags selecting and comparing resources, shared by diff and sync
+func addInventoryFlags(command *cobra.Command, level *string, tags *map[string]string, labels *[]string, md5 *bool, workers *int) {
+	command.Flags().StringVar(level, "level", "Study", "Resource level (Patient, Study, Series)")
+	command.Flags().StringToStringVar(tags, "tag", nil, "Only compare resources matching a DICOM tag (can be specified multiple times)")
+	command.Flags().StringSliceVar(labels, "label", nil, "Only compare resources with these labels (Orthanc 1.12.0+)")
+	command.Flags().BoolVar(md5, "md5", false, "Also compare the MD5 of every instance (slow)")
+	command.Flags().IntVar(workers, "parallel", parallel.DefaultWorkers, "Number of parallel requests per server")
+}
+
+func runDiff(contextA, contextB string, flags *DiffFlags) error {
+	// Check if JSON output should be used (flag or config)
+	jsonOutput := flags.jsonOutput || shouldUseJSON()
+
+	a, b, err := getClients(contextA, contextB)
+	if err != nil {
+		return err
+	}
+
+	report, err := compareContexts(a, b, flags.level, flags.tags, flags.labels, flags.md5, flags.parallel)
+	if err != nil {
+		return err
+	}
+
+	if jsonOutput {
+		data, err := json.MarshalIndent(report, "", "  ")
+		if err != nil {
+			return fmt.Errorf("failed to marshal JSON: %w", err)
+		}
+		fmt.Println(string(data))
+	} else {
+		printDiff(report)
+	}
+
+	if flags.exitCode && report.differs() {
+		return clierr.New(clierr.KindGeneric, "contexts '%s' and '%s' differ", report.A, report.B)
+	}
+	return nil
+}
+
+// compareContexts compares the resources of a level stored by two servers
+func compareContexts(a, b *client.Client, levelName string, tags map[string]string, labels []string, md5 bool, workers int) (*diffReport, error) {
+	// Validate level
+	level := types.ResourceLevel(levelName)
+	if _, ok := uidTags[level]; !ok {
+		return nil, clierr.Validation("invalid level '%s', must be one of: Patient, Study, Series", levelName)
+	}
+	if workers < 1 {
+		return nil, clierr.Validation("--parallel must be at least 1")
+	}
+
+	// Orthanc requires Query to be an object, even when no tag filter is given
+	if tags == nil {
+		tags = make(map[string]string)
+	}
+	request := &types.ToolsFindRequest{
+		Level:  level,
+		Query:  tags,
+		Expand: helpers.BoolPtr(true),
+		Labels: labels,
+	}
+
+	inventoryA, err := fetchInventory(a, request)
+	if err != nil {
+		return nil, err
+	}
+	inventoryB, err := fetchInventory(b, request)
+	if err != nil {
+		return nil, err
+	}
+
+	report := &diffReport{
+		A:          a.ContextName(),
+		B:          b.ContextName(),
+		Level:      levelName,
+		CountA:     len(inventoryA),
+		CountB:     len(inventoryB),
+		OnlyInA:    []*inventoryResource{},
+		OnlyInB:    []*inventoryResource{},
+		Mismatched: []*diffMismatch{},
+	}
+
+	var common []*diffMismatch
+	for uid, resource := range inventoryA {
+		if other, ok := inventoryB[uid]; ok {
+			common = append(common, &diffMismatch{UID: uid, A: resource, B: other})
+		} else {
+			report.OnlyInA = append(report.OnlyInA, resource)
+		}
+	}
+	for uid, resource := range inventoryB {
+		if _, ok := inventoryA[uid]; !ok {
+			report.OnlyInB = append(report.OnlyInB, resource)
+		}
+	}
+
+	// Count the instances of the resources on both sides
+	errs := parallel.ForEach(len(common), workers, func(i int) error {
+		if err := describeResource(a, level, common[i].A, md5); err != nil {
+			return err
+		}
+		return describeResource(b, level, common[i].B, md5)
+	})
+	for _, err := range errs {
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	for _, pair := range common {
+		switch {
+		case pair.A.CountInstances != pair.B.CountInstances:
+			pair.Reason = "instances"
+		case pair.A.digest != pair.B.digest:
+			pair.Reason = "md5"
+		default:
+			report.Matching++
+			continue
+		}
+		report.Mismatched = append(report.Mismatched, pair)
+	}
+
+	sortResources(report.OnlyInA)
+	sortResources(report.OnlyInB)
+	sort.Slice(report.Mismatched, func(i, j int) bool {
+		return report.Mismatched[i].UID < report.Mismatched[j].UID
+	})
+
+	return report, nil
+}
+
+// fetchInventory returns the resources matching request, by UID
+func fetchInventory(c *client.Client, request *types.ToolsFindRequest) (map[string]*inventoryResource, error) {
+	if len(request.Labels) > 0 {
+		if err := c.RequireFeature(client.FeatureLabels); err != nil {
+			return nil, err
+		}
+	}
+
+	results, err := c.FindExpanded(request)
+	if err != nil {
+		return nil, fmt.Errorf("search on context '%s' failed: %w", c.ContextName(), err)
+	}
+
+	tag := uidTags[request.Level]
+	inventory := make(map[string]*inventoryResource, len(results))
+	for _, result := range results {
+		uid := strings.TrimSpace(fmt.Sprint(result.MainDicomTags[tag]))
+		if result.MainDicomTags[tag] == nil || uid == "" {
+			fmt.Fprintf(os.Stderr, "Warning: %s on context '%s' has no %s, skipping\n", result.ID, c.ContextName(), tag)
+			continue
+		}
+		if existing, ok := inventory[uid]; ok {
+			fmt.Fprintf(os.Stderr, "Warning: %s %s is shared by %s and %s on context '%s', comparing the first\n",
+				tag, uid, existing.ID, result.ID, c.ContextName())
+			continue
+		}
+		inventory[uid] = &inventoryResource{UID: uid, ID: result.ID}
+	}
+	return inventory, nil
+}
+
+// describeResource fills in the instance count of a resource and, with md5,
+// the digest of its instances
+func describeResource(c *client.Client, level types.ResourceLevel, resource *inventoryResource, md5 bool) error {
+	if !md5 {
+		statistics, err := c.GetResourceStatistics(level, resource.ID)
+		if err != nil {
+			return fmt.Errorf("failed to get statistics of %s on context '%s': %w", resource.ID, c.ContextName(), err)
+		}
+		resource.CountInstances = statistics.CountInstances
+		return nil
+	}
+
+	instances, err := listInstances(c, level, resource.ID)
+	if err != nil {
+		return fmt.Errorf("failed to list instances of %s on context '%s': %w", resource.ID, c.ContextName(), err)
+	}
+
+	lines := make([]string, len(instances))
+	for i, instance := range instances {
+		sum, err := instanceMD5(c, instance.ID)
+		if err != nil {
+			return fmt.Errorf("failed to get MD5 of instance %s on context '%s': %w", instance.ID, c.ContextName(), err)
+		}
+		lines[i] = instance.MainDicomTags.SOPInstanceUID + "=" + sum
+	}
+	sort.Strings(lines)
+
+	resource.CountInstances = len(instances)
+	resource.digest = strings.Join(lines, "\n")
+	return nil
+}
+
+// instanceMD5 returns the MD5 of the DICOM file of an instance, as stored by Orthanc
+func instanceMD5(c *client.Client, id string) (string, error) {
+	resp, err := c.Do(http.MethodGet, "instances/"+id+"/attachments/dicom/md5", nil, "")
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return "", err
+	}
+	return strings.Trim(strings.TrimSpace(string(data)), `"`), nil
+}
+
+// sortResources orders resources by UID
+func sortResources(resources []*inventoryResource) {
+	sort.Slice(resources, func(i, j int) bool {
+		return resources[i].UID < resources[j].UID
+	})
+}
+
+// printDiff prints a diff report as text
+func printDiff(report *diffReport) {
+	tag := uidTags[types.ResourceLevel(report.Level)]
+	plural := client.PluralLevelName(types.ResourceLevel(report.Level))
+
+	fmt.Printf("Comparing %s of '%s' (%d) and '%s' (%d)\n", plural, report.A, report.CountA, report.B, report.CountB)
+	labelA, labelB := "Only in '"+report.A+"':", "Only in '"+report.B+"':"
+	width := max(len(labelA), len(labelB), len("Mismatched:"))
+	fmt.Printf("%-*s %d\n", width, "Matching:", report.Matching)
+	fmt.Printf("%-*s %d\n", width, labelA, len(report.OnlyInA))
+	fmt.Printf("%-*s %d\n", width, labelB, len(report.OnlyInB))
+	fmt.Printf("%-*s %d\n", width, "Mismatched:", len(report.Mismatched))
+
+	for _, side := range []struct {
+		name      string
+		resources []*inventoryResource
+	}{{report.A, report.OnlyInA}, {report.B, report.OnlyInB}} {
+		if len(side.resources) == 0 {
+			continue
+		}
+		fmt.Printf("\nOnly in '%s':\n", side.name)
+		fmt.Printf("  %-64s  %s\n", tag, "ID")
+		for _, resource := range side.resources {
+			fmt.Printf("  %-64s  %s\n", resource.UID, resource.ID)
+		}
+	}
+
+	if len(report.Mismatched) > 0 {
+		fmt.Println("\nMismatched:")
+		fmt.Printf("  %-64s  %-10s  %-10s  %s\n", tag, report.A, report.B, "REASON")
+		for _, mismatch := range report.Mismatched {
+			fmt.Printf("  %-64s  %-10d  %-10d  %s\n", mismatch.UID, mismatch.A.CountInstances, mismatch.B.CountInstances, mismatch.Reason)
+		}
+	}
+}
@@ -0,0 +1,273 @@
+package transfer
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/proencaj/gorthanc/types"
+	"github.com/proencaj/orthanc-cli/internal/batch"
+	"github.com/proencaj/orthanc-cli/internal/client"
+	"github.com/proencaj/orthanc-cli/internal/clierr"
+	"github.com/proencaj/orthanc-cli/internal/guard"
+	"github.com/spf13/cobra"
+)
+
+// Methods of sending resources with sync
+const (
+	methodCopy     = "copy"
+	methodPeer     = "peer"
+	methodDicomWeb = "dicomweb"
+)
+
+// SyncFlags holds the flags for the sync command
+type SyncFlags struct {
+	level      string
+	tags       map[string]string
+	labels     []string
+	md5        bool
+	parallel   int
+	method     string
+	peer       string
+	server     string
+	mismatched bool
+	jobTimeout time.Duration
+	dryRun     bool
+	jsonOutput bool
+}
+
+// syncReport is the JSON output for a synced resource
+type syncReport struct {
+	UID           string `json:"UID"`
+	ID            string `json:"ID"`
+	Level         string `json:"Level"`
+	Reason        string `json:"Reason"`
+	Method        string `json:"Method"`
+	From          string `json:"From"`
+	To            string `json:"To"`
+	Copied        int    `json:"Copied,omitempty"`
+	AlreadyStored int    `json:"AlreadyStored,omitempty"`
+}
+
+// NewSyncCommand creates the sync command
+func NewSyncCommand() *cobra.Command {
+	flags := &SyncFlags{}
+
+	command := &cobra.Command{
+		Use:   "sync <source-context> <destination-context>",
+		Short: "Send the resources missing from one context to another",
+		Long: `Compare two contexts like diff does, then send the resources only found on the
+source to the destination. With --mismatched, resources found on both sides
+with different content are sent again too; instances the destination already
+stores are not replaced.
+
+Resources are sent with one of these methods:
+  copy      stream each instance through this machine (default)
+  peer      ask the source server to send them to an Orthanc peer (--peer)
+  dicomweb  ask the source server to send them with STOW-RS (--server)
+
+The peer and DICOMweb server must be configured on the source server and
+point to the destination. Their transfers run as jobs on the source server,
+each waited for up to --job-timeout.`,
+		Example: `  # Show what would be sent to the replacement of a node
+  orthanc sync old-node new-node --dry-run
+
+  # Send the missing studies, streamed through this machine
+  orthanc sync old-node new-node
+
+  # Let the source server send the missing series to its peer
+  orthanc sync old-node new-node --level Series --method peer --peer new-node
+
+  # Also resend studies with missing instances, over DICOMweb
+  orthanc sync old-node new-node --mismatched --method dicomweb --server new-node`,
+		Annotations: map[string]string{guard.Annotation: guard.CrossContext},
+		Args:        cobra.ExactArgs(2),
+		RunE: func(c *cobra.Command, args []string) error {
+			return runSync(c, args[0], args[1], flags)
+		},
+	}
+
+	// Add flags
+	addInventoryFlags(command, &flags.level, &flags.tags, &flags.labels, &flags.md5, &flags.parallel)
+	command.Flags().StringVar(&flags.method, "method", methodCopy, "How to send resources: copy, peer, dicomweb")
+	command.Flags().StringVar(&flags.peer, "peer", "", "Orthanc peer of the source server to send to, with --method peer")
+	command.Flags().StringVar(&flags.server, "server", "", "DICOMweb server of the source server to send to, with --method dicomweb")
+	command.Flags().DurationVar(&flags.jobTimeout, "job-timeout", time.Hour, "Time to wait for each transfer job with --method peer or dicomweb (0 for no limit)")
+	command.Flags().BoolVar(&flags.mismatched, "mismatched", false, "Also send resources whose content differs")
+	command.Flags().BoolVar(&flags.dryRun, "dry-run", false, "Show the resources to send without sending them")
+	command.Flags().BoolVar(&flags.jsonOutput, "json", false, "Output in JSON format")
+
+	return command
+}
+
+func runSync(command *cobra.Command, sourceContext, destinationContext string, flags *SyncFlags) error {
+	switch flags.method {
+	case methodCopy:
+	case methodPeer:
+		if flags.peer == "" {
+			return clierr.Validation("--peer is required with --method peer")
+		}
+	case methodDicomWeb:
+		if flags.server == "" {
+			return clierr.Validation("--server is required with --method dicomweb")
+		}
+	default:
+		return clierr.Validation("invalid method '%s', must be one of: copy, peer, dicomweb", flags.method)
+	}
+	if flags.jobTimeout < 0 {
+		return clierr.Validation("--job-timeout must not be negative")
+	}
+
+	// Check if JSON output should be used (flag or config)
+	jsonOutput := flags.jsonOutput || shouldUseJSON()
+
+	src, dst, err := getClients(sourceContext, destinationContext)
+	if err != nil {
+		return err
+	}
+	recordTransfer(src, dst)
+
+	// Enforce the settings of the destination, which is the only one changed
+	if !flags.dryRun {
+		if err := guard.CheckContext(command, dst.GetConfig(), dst.ContextName(), guard.Mutating); err != nil {
+			return err
+		}
+		if flags.method == methodDicomWeb {
+			if err := src.RequireFeature(client.FeatureDicomWeb); err != nil {
+				return err
+			}
+		}
+	}
+
+	diff, err := compareContexts(src, dst, flags.level, flags.tags, flags.labels, flags.md5, flags.parallel)
+	if err != nil {
+		return err
+	}
+
+	// Plan the resources to send
+	level := types.ResourceLevel(flags.level)
+	var reports []*syncReport
+	plan := func(resource *inventoryResource, reason string) {
+		reports = append(reports, &syncReport{
+			UID:    resource.UID,
+			ID:     resource.ID,
+			Level:  flags.level,
+			Reason: reason,
+			Method: flags.method,
+			From:   src.ContextName(),
+			To:     dst.ContextName(),
+		})
+	}
+	for _, resource := range diff.OnlyInA {
+		plan(resource, "missing")
+	}
+	if flags.mismatched {
+		for _, mismatch := range diff.Mismatched {
+			plan(mismatch.A, mismatch.Reason)
+		}
+	}
+
+	singular, plural := strings.ToLower(flags.level), client.PluralLevelName(level)
+	if len(reports) == 0 {
+		if !jsonOutput {
+			fmt.Printf("Nothing to sync: '%s' has all the %s of '%s'\n", dst.ContextName(), plural, src.ContextName())
+		} else if flags.dryRun {
+			fmt.Println("[]")
+		}
+		return nil
+	}
+
+	if flags.dryRun {
+		if jsonOutput {
+			data, err := json.MarshalIndent(reports, "", "  ")
+			if err != nil {
+				return fmt.Errorf("failed to marshal JSON: %w", err)
+			}
+			fmt.Println(string(data))
+			return nil
+		}
+		fmt.Printf("Would send %s from '%s' to '%s' with %s:\n", countNoun(len(reports), singular, plural), src.ContextName(), dst.ContextName(), flags.method)
+		for _, report := range reports {
+			fmt.Printf("  %-64s  %s  (%s)\n", report.UID, report.ID, report.Reason)
+		}
+		fmt.Println("\nDry run: nothing was sent.")
+		return nil
+	}
+
+	if !jsonOutput {
+		fmt.Printf("Sending %s from '%s' to '%s' with %s\n", countNoun(len(reports), singular, plural), src.ContextName(), dst.ContextName(), flags.method)
+	}
+
+	// Copied resources are sent one at a time, their instances in parallel;
+	// the other methods send whole resources in parallel
+	workers := flags.parallel
+	if flags.method == methodCopy {
+		workers = 1
+	}
+	ids := make([]string, len(reports))
+	for i, report := range reports {
+		ids[i] = report.ID
+	}
+	return batch.Run(ids, workers, func(i int) error {
+		return sendResource(src, dst, level, reports[i], flags)
+	}, func(i int) error {
+		report := reports[i]
+		if jsonOutput {
+			data, err := json.MarshalIndent(report, "", "  ")
+			if err != nil {
+				return fmt.Errorf("failed to marshal JSON: %w", err)
+			}
+			fmt.Println(string(data))
+			return nil
+		}
+		fmt.Printf("Synced %s %s (%s)\n", strings.ToLower(report.Level), report.UID, report.ID)
+		return nil
+	})
+}
+
+// sendResource sends a resource from src to dst with the method of flags
+func sendResource(src, dst *client.Client, level types.ResourceLevel, report *syncReport, flags *SyncFlags) error {
+	switch flags.method {
+	case methodPeer:
+		if err := runTransferJob(src, "peers/"+url.PathEscape(flags.peer)+"/store", report.ID, flags.jobTimeout); err != nil {
+			return fmt.Errorf("failed to send to peer %s: %w", flags.peer, err)
+		}
+	case methodDicomWeb:
+		if err := runTransferJob(src, "dicom-web/servers/"+url.PathEscape(flags.server)+"/stow", report.ID, flags.jobTimeout); err != nil {
+			return fmt.Errorf("failed to send to DICOMweb server %s: %w", flags.server, err)
+		}
+	default:
+		copied, err := copyResource(src, dst, level, report.ID, &CopyFlags{parallel: flags.parallel})
+		if err != nil {
+			return err
+		}
+		report.Copied = copied.Copied
+		report.AlreadyStored = copied.AlreadyStored
+	}
+	return nil
+}
+
+// runTransferJob asks the source server to send a resource as a job and waits
+// for it, since a synchronous request would outlast the client's timeout
+func runTransferJob(src *client.Client, path, id string, timeout time.Duration) error {
+	body := map[string]interface{}{
+		"Resources":   []string{id},
+		"Synchronous": false,
+	}
+	jobID, err := src.SubmitJob(path, body)
+	if err != nil {
+		return err
+	}
+
+	ctx := context.Background()
+	if timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, timeout)
+		defer cancel()
+	}
+	_, err = src.WaitForJob(ctx, jobID, time.Second, nil)
+	return err
+}
@@ -0,0 +1,107 @@
+package transfer
+
+import (
+	"fmt"
+
+	"github.com/proencaj/orthanc-cli/internal/client"
+	"github.com/proencaj/orthanc-cli/internal/clierr"
+	"github.com/proencaj/orthanc-cli/internal/config"
+)
+
+// clientGetter is a function type that returns an Orthanc client
+var clientGetter func() (*client.Client, error)
+
+// contextClientGetter is a function type that returns an Orthanc client for a named context
+var contextClientGetter func(name string) (*client.Client, error)
+
+// transferRecorder records the source and destination contexts in the audit log
+var transferRecorder func(source, destination string)
+
+// SetClientGetter sets the function to get the Orthanc client
+func SetClientGetter(getter func() (*client.Client, error)) {
+	clientGetter = getter
+}
+
+// SetContextClientGetter sets the function to get the Orthanc client of a named context
+func SetContextClientGetter(getter func(name string) (*client.Client, error)) {
+	contextClientGetter = getter
+}
+
+// SetTransferRecorder sets the function recording the source and destination
+// contexts of a command in the audit log
+func SetTransferRecorder(recorder func(source, destination string)) {
+	transferRecorder = recorder
+}
+
+// recordTransfer records the source and destination contexts of a command
+func recordTransfer(src, dst *client.Client) {
+	if transferRecorder != nil {
+		transferRecorder(src.ContextName(), dst.ContextName())
+	}
+}
+
+// getClient returns the Orthanc client using the configured getter
+func getClient() (*client.Client, error) {
+	if clientGetter != nil {
+		return clientGetter()
+	}
+	// Fallback: try to load config from default location
+	cfg, err := config.LoadConfig("")
+	if err != nil {
+		return nil, err
+	}
+	return client.NewClient(cfg)
+}
+
+// getClientForContext returns the Orthanc client of a named context using the configured getter
+func getClientForContext(name string) (*client.Client, error) {
+	if contextClientGetter != nil {
+		return contextClientGetter(name)
+	}
+	// Fallback: try to load config from default location
+	cfg, err := config.LoadConfig("")
+	if err != nil {
+		return nil, err
+	}
+	return client.NewClientForContext(cfg, name)
+}
+
+// getClients returns the clients of a source and a destination context, which
+// must differ; an empty source name means the current context
+func getClients(from, to string) (*client.Client, *client.Client, error) {
+	var src *client.Client
+	var err error
+	if from == "" {
+		src, err = getClient()
+	} else {
+		src, err = getClientForContext(from)
+	}
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to create source client: %w", err)
+	}
+	dst, err := getClientForContext(to)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to create destination client: %w", err)
+	}
+	if src.ContextName() == dst.ContextName() {
+		return nil, nil, clierr.Validation("source and destination are both context '%s'", dst.ContextName())
+	}
+	return src, dst, nil
+}
+
+// countNoun formats a count with the singular or plural noun
+func countNoun(count int, singular, plural string) string {
+	if count == 1 {
+		return "1 " + singular
+	}
+	return fmt.Sprintf("%d %s", count, plural)
+}
+
+// shouldUseJSON checks if JSON output is enabled in config
+func shouldUseJSON() bool {
+	cfg, err := config.LoadConfig("")
+	if err != nil {
+		return false
+	}
+	return cfg.Output.JSON
+}
@@ -0,0 +1,93 @@
+package trash
+
+import (
+	"fmt"
+	"strings"
+
+	internalTrash "github.com/proencaj/orthanc-cli/internal/trash"
+	"github.com/spf13/cobra"
+)
+
+// summaryTags are the main DICOM tags shown for each entry, by level
+var summaryTags = map[string][]string{
+	"Patient":  {"PatientID", "PatientName"},
+	"Study":    {"PatientID", "StudyDate", "AccessionNumber", "StudyDescription"},
+	"Series":   {"Modality", "SeriesNumber", "SeriesDescription"},
+	"Instance": {"InstanceNumber", "SOPInstanceUID"},
+}
+
+// ListFlags holds the flags for the list command
+type ListFlags struct {
+	context    string
+	jsonOutput bool
+}
+
+// NewListCommand creates the trash list command
+func NewListCommand() *cobra.Command {
+	flags := &ListFlags{}
+
+	command := &cobra.Command{
+		Use:   "list",
+		Short: "List the resources in the local trash",
+		Long:  `List the resources kept in the local trash, most recently deleted first.`,
+		Example: `  # List the trash
+  orthanc trash list
+
+  # List the resources deleted from the production context
+  orthanc trash list --context production`,
+		Args: cobra.NoArgs,
+		RunE: func(c *cobra.Command, args []string) error {
+			return runList(flags)
+		},
+	}
+
+	// Add flags
+	command.Flags().StringVar(&flags.context, "context", "", "Only list resources deleted from this context")
+	command.Flags().BoolVar(&flags.jsonOutput, "json", false, "Output in JSON format")
+
+	return command
+}
+
+func runList(flags *ListFlags) error {
+	// Check if JSON output should be used (flag or config)
+	jsonOutput := flags.jsonOutput || shouldUseJSON()
+
+	entries, err := internalTrash.List()
+	if err != nil {
+		return err
+	}
+
+	selected := []*internalTrash.Entry{}
+	for _, entry := range entries {
+		if flags.context == "" || entry.Context == flags.context {
+			selected = append(selected, entry)
+		}
+	}
+
+	if jsonOutput {
+		return printJSON(selected)
+	}
+
+	if len(selected) == 0 {
+		fmt.Println("The trash is empty.")
+		return nil
+	}
+
+	fmt.Printf("%-34s  %-8s  %-12s  %-16s  %9s  %10s  %s\n", "ENTRY", "LEVEL", "CONTEXT", "DELETED", "INSTANCES", "SIZE (MB)", "TAGS")
+	var total int64
+	for _, entry := range selected {
+		tags := []string{}
+		for _, name := range summaryTags[entry.Level] {
+			if value := entry.Tags[name]; value != "" {
+				tags = append(tags, name+"="+value)
+			}
+		}
+		fmt.Printf("%-34s  %-8s  %-12s  %-16s  %9d  %10.2f  %s\n", entry.ID, entry.Level, entry.Context,
+			entry.DeletedAt.Local().Format("2006-01-02 15:04"), entry.Instances,
+			float64(entry.Size)/(1024*1024), strings.Join(tags, " "))
+		total += entry.Size
+	}
+	fmt.Printf("\nTotal: %s, %.2f MB\n", countEntries(len(selected)), float64(total)/(1024*1024))
+
+	return nil
+}
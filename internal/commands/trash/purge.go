@@ -0,0 +1,146 @@
+package trash
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/proencaj/orthanc-cli/internal/clierr"
+	"github.com/proencaj/orthanc-cli/internal/helpers"
+	internalTrash "github.com/proencaj/orthanc-cli/internal/trash"
+	"github.com/spf13/cobra"
+)
+
+// PurgeFlags holds the flags for the purge command
+type PurgeFlags struct {
+	olderThan string
+	all       bool
+	force     bool
+}
+
+// NewPurgeCommand creates the trash purge command
+func NewPurgeCommand() *cobra.Command {
+	flags := &PurgeFlags{}
+
+	command := &cobra.Command{
+		Use:   "purge [entry-id...]",
+		Short: "Permanently remove entries from the local trash",
+		Long: `Permanently remove entries from the local trash, either the given entries,
+the entries older than --older-than, or all entries with --all.
+
+Purged resources can no longer be restored.`,
+		Example: `  # Remove the entries deleted more than 30 days ago
+  orthanc trash purge --older-than 30d
+
+  # Remove a single entry
+  orthanc trash purge 20261018-142233-study-1a2b3c4d
+
+  # Empty the trash without confirmation
+  orthanc trash purge --all --force`,
+		RunE: func(c *cobra.Command, args []string) error {
+			return runPurge(args, flags)
+		},
+	}
+
+	// Add flags
+	command.Flags().StringVar(&flags.olderThan, "older-than", "", "Remove the entries deleted longer ago than this (e.g. 30d, 12h)")
+	command.Flags().BoolVar(&flags.all, "all", false, "Remove all entries")
+	command.Flags().BoolVarP(&flags.force, "force", "f", false, "Skip confirmation prompt")
+
+	return command
+}
+
+func runPurge(args []string, flags *PurgeFlags) error {
+	selectors := 0
+	for _, set := range []bool{len(args) > 0, flags.olderThan != "", flags.all} {
+		if set {
+			selectors++
+		}
+	}
+	if selectors != 1 {
+		return clierr.Validation("give either entry IDs, --older-than or --all")
+	}
+
+	// Select the entries to remove
+	var entries []*internalTrash.Entry
+	switch {
+	case len(args) > 0:
+		for _, ref := range args {
+			entry, err := internalTrash.Find(ref)
+			if err != nil {
+				return err
+			}
+			entries = append(entries, entry)
+		}
+	default:
+		var cutoff time.Time
+		if flags.olderThan != "" {
+			age, err := helpers.ParseAge(flags.olderThan)
+			if err != nil {
+				return err
+			}
+			cutoff = time.Now().Add(-age)
+		}
+
+		all, err := internalTrash.List()
+		if err != nil {
+			return err
+		}
+		for _, entry := range all {
+			if flags.all || entry.DeletedAt.Before(cutoff) {
+				entries = append(entries, entry)
+			}
+		}
+	}
+
+	if len(entries) == 0 {
+		fmt.Println("Nothing to purge.")
+		return nil
+	}
+
+	var size int64
+	for _, entry := range entries {
+		size += entry.Size
+	}
+
+	// If not using force flag, prompt for confirmation
+	if !flags.force {
+		confirmed, err := confirmPurge(len(entries), size)
+		if err != nil {
+			return fmt.Errorf("failed to get confirmation: %w", err)
+		}
+		if !confirmed {
+			fmt.Println("Operation cancelled")
+			return nil
+		}
+	}
+
+	for _, entry := range entries {
+		if err := internalTrash.Remove(entry); err != nil {
+			return err
+		}
+	}
+
+	fmt.Printf("Purged %s (%.2f MB)\n", countEntries(len(entries)), float64(size)/(1024*1024))
+	return nil
+}
+
+func confirmPurge(count int, size int64) (bool, error) {
+	fmt.Printf("\n⚠️  WARNING: You are about to purge %s (%.2f MB)\n", countEntries(count), float64(size)/(1024*1024))
+	fmt.Println("Purged resources can no longer be restored.")
+	fmt.Print("\nDo you really want to purge them? (yes/no): ")
+
+	reader := bufio.NewReader(os.Stdin)
+	response, err := reader.ReadString('\n')
+	if err != nil {
+		return false, err
+	}
+
+	// Clean up the response
+	response = strings.TrimSpace(strings.ToLower(response))
+
+	// Accept "yes" or "y" as confirmation
+	return response == "yes" || response == "y", nil
+}
@@ -0,0 +1,135 @@
+package trash
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/proencaj/orthanc-cli/internal/batch"
+	"github.com/proencaj/orthanc-cli/internal/guard"
+	"github.com/proencaj/orthanc-cli/internal/parallel"
+	internalTrash "github.com/proencaj/orthanc-cli/internal/trash"
+	"github.com/spf13/cobra"
+)
+
+// RestoreFlags holds the flags for the restore command
+type RestoreFlags struct {
+	keep       bool
+	parallel   int
+	jsonOutput bool
+}
+
+// restoreReport is the JSON output for a restored entry
+type restoreReport struct {
+	Entry      string `json:"Entry"`
+	Level      string `json:"Level"`
+	ResourceID string `json:"ResourceID"`
+	Context    string `json:"Context"`
+	*internalTrash.RestoreResult
+}
+
+// NewRestoreCommand creates the trash restore command
+func NewRestoreCommand() *cobra.Command {
+	flags := &RestoreFlags{}
+
+	command := &cobra.Command{
+		Use:   "restore <entry-id>...",
+		Short: "Upload resources from the local trash back to Orthanc",
+		Long: `Upload resources kept in the local trash to the Orthanc server of the current
+context, and remove them from the trash once every file is stored.
+
+Entries can be given by entry ID, by a unique prefix of one, or by the Orthanc
+ID of the deleted resource. A resource deleted from another context is restored
+to the current context, with a warning.`,
+		Example: `  # Restore a study by the ID of its trash entry
+  orthanc trash restore 20261018-142233-study-1a2b3c4d
+
+  # Restore the most recent copy of a deleted resource by its Orthanc ID
+  orthanc trash restore 1a2b3c4d-5e6f7a8b-9c0d1e2f-3a4b5c6d-7e8f9a0b
+
+  # Restore and keep the copy in the trash
+  orthanc trash restore 20261018-142233-study-1a2b3c4d --keep`,
+		Annotations: map[string]string{guard.Annotation: guard.Mutating},
+		Args:        cobra.MinimumNArgs(1),
+		RunE: func(c *cobra.Command, args []string) error {
+			return runRestore(args, flags)
+		},
+	}
+
+	// Add flags
+	command.Flags().BoolVar(&flags.keep, "keep", false, "Keep the entries in the trash after restoring them")
+	command.Flags().IntVar(&flags.parallel, "parallel", parallel.DefaultWorkers, "Number of DICOM files uploaded in parallel")
+	command.Flags().BoolVar(&flags.jsonOutput, "json", false, "Output in JSON format")
+
+	return command
+}
+
+// NewUndeleteCommand creates the top-level undelete command, a shortcut for trash restore
+func NewUndeleteCommand() *cobra.Command {
+	command := NewRestoreCommand()
+	command.Use = "undelete <entry-id>..."
+	command.Short = "Restore removed resources from the local trash"
+	command.Example = `  # Undo the removal of a study, by its Orthanc ID
+  orthanc undelete 1a2b3c4d-5e6f7a8b-9c0d1e2f-3a4b5c6d-7e8f9a0b
+
+  # Find the entry to restore
+  orthanc trash list`
+
+	return command
+}
+
+func runRestore(args []string, flags *RestoreFlags) error {
+	refs, err := batch.ReadIDs(args)
+	if err != nil {
+		return err
+	}
+
+	// Check if JSON output should be used (flag or config)
+	jsonOutput := flags.jsonOutput || shouldUseJSON()
+
+	// Get the Orthanc client
+	client, err := getClient()
+	if err != nil {
+		return fmt.Errorf("failed to create client: %w", err)
+	}
+
+	// Entries are restored one at a time, their files are uploaded in parallel
+	reports := make([]*restoreReport, len(refs))
+	return batch.Run(refs, 1, func(i int) error {
+		entry, err := internalTrash.Find(refs[i])
+		if err != nil {
+			return err
+		}
+		if entry.Context != client.ContextName() {
+			fmt.Fprintf(os.Stderr, "Warning: %s was deleted from context '%s', restoring to '%s'\n",
+				entry.ID, entry.Context, client.ContextName())
+		}
+
+		result, err := internalTrash.Restore(client, entry, flags.parallel)
+		if err != nil {
+			return fmt.Errorf("failed to restore %s: %w", entry.ID, err)
+		}
+		if !flags.keep {
+			if err := internalTrash.Remove(entry); err != nil {
+				return err
+			}
+		}
+
+		reports[i] = &restoreReport{
+			Entry:         entry.ID,
+			Level:         entry.Level,
+			ResourceID:    entry.ResourceID,
+			Context:       client.ContextName(),
+			RestoreResult: result,
+		}
+		return nil
+	}, func(i int) error {
+		report := reports[i]
+		if jsonOutput {
+			return printJSON(report)
+		}
+		fmt.Printf("Successfully restored %s: %s\n", strings.ToLower(report.Level), report.ResourceID)
+		fmt.Printf("Uploaded: %d file(s), already stored: %d\n", report.Uploaded, report.AlreadyStored)
+		return nil
+	})
+}
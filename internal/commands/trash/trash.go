@@ -0,0 +1,84 @@
+package trash
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/proencaj/orthanc-cli/internal/client"
+	"github.com/proencaj/orthanc-cli/internal/config"
+	"github.com/spf13/cobra"
+)
+
+// clientGetter is a function type that returns an Orthanc client
+var clientGetter func() (*client.Client, error)
+
+// SetClientGetter sets the function to get the Orthanc client
+func SetClientGetter(getter func() (*client.Client, error)) {
+	clientGetter = getter
+}
+
+// getClient returns the Orthanc client using the configured getter
+func getClient() (*client.Client, error) {
+	if clientGetter != nil {
+		return clientGetter()
+	}
+	// Fallback: try to load config from default location
+	cfg, err := config.LoadConfig("")
+	if err != nil {
+		return nil, err
+	}
+	return client.NewClient(cfg)
+}
+
+// shouldUseJSON checks if JSON output is enabled in config
+func shouldUseJSON() bool {
+	cfg, err := config.LoadConfig("")
+	if err != nil {
+		return false
+	}
+	return cfg.Output.JSON
+}
+
+// NewTrashCommand creates the trash command with all subcommands
+func NewTrashCommand() *cobra.Command {
+	trashCmd := &cobra.Command{
+		Use:   "trash",
+		Short: "Manage the local trash of removed resources",
+		Long: `List, restore and purge resources kept in the local trash.
+
+When the trash setting is enabled for a context (orthanc config set trash true),
+the remove commands and 'orthanc delete' download each resource before deleting
+it. The copies are kept in ~/.orthanc-cli/trash (or $ORTHANC_TRASH_DIR) with the
+context, Orthanc ID, main DICOM tags and deletion time of the resource.
+
+Restoring uploads the DICOM files again: the resource gets its original Orthanc
+ID back, but metadata, labels and attachments are not restored.
+
+The trash is never emptied automatically, use 'orthanc trash purge'.`,
+	}
+
+	// Add subcommands
+	trashCmd.AddCommand(NewListCommand())
+	trashCmd.AddCommand(NewRestoreCommand())
+	trashCmd.AddCommand(NewPurgeCommand())
+
+	return trashCmd
+}
+
+// printJSON prints a value as indented JSON
+func printJSON(value interface{}) error {
+	data, err := json.MarshalIndent(value, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal JSON: %w", err)
+	}
+	fmt.Println(string(data))
+	return nil
+}
+
+// countEntries formats a number of trash entries
+func countEntries(count int) string {
+	if count == 1 {
+		return "1 trash entry"
+	}
+	return fmt.Sprintf("%d trash entries", count)
+}
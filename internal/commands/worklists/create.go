@@ -0,0 +1,390 @@
+package worklists
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"github.com/proencaj/orthanc-cli/internal/batch"
+	"github.com/proencaj/orthanc-cli/internal/client"
+	"github.com/proencaj/orthanc-cli/internal/clierr"
+	"github.com/proencaj/orthanc-cli/internal/guard"
+	"github.com/proencaj/orthanc-cli/internal/helpers"
+	"github.com/proencaj/orthanc-cli/internal/parallel"
+	"github.com/spf13/cobra"
+)
+
+// Dates and times in ISO form, converted to the DICOM DA and TM forms
+var (
+	isoDate = regexp.MustCompile(`^(\d{4})-(\d{2})-(\d{2})$`)
+	isoTime = regexp.MustCompile(`^(\d{2}):(\d{2})(?::(\d{2}(?:\.\d+)?))?$`)
+)
+
+// CreateFlags holds the flags for the create command
+type CreateFlags struct {
+	input      string
+	tags       []string
+	dryRun     bool
+	parallel   int
+	jsonOutput bool
+}
+
+// worklistInput is a worklist to create, with where it was read from
+type worklistInput struct {
+	source string
+	tags   map[string]interface{}
+}
+
+// NewCreateCommand creates the worklists create command
+func NewCreateCommand() *cobra.Command {
+	flags := &CreateFlags{}
+
+	command := &cobra.Command{
+		Use:   "create",
+		Short: "Create worklists from tags or a CSV/YAML file",
+		Long: `Create modality worklist entries from --tag Name=Value pairs, or from a CSV,
+YAML or JSON file with --input, one worklist per row or entry.
+
+Tags are named after the standard modality worklist attributes. The attributes
+of the scheduled procedure step (Modality, ScheduledStationAETitle,
+ScheduledStationName, ScheduledProcedureStepLocation,
+ScheduledProcedureStepStartDate, ScheduledProcedureStepStartTime,
+ScheduledPerformingPhysicianName, ScheduledProcedureStepDescription,
+ScheduledProcedureStepID and ScheduledProcedureStepStatus) can be given flat,
+and are moved into the ScheduledProcedureStepSequence. YAML and JSON entries
+can also give the sequence itself.
+
+The first row of a CSV file holds the tag names; empty cells are left out.
+A YAML or JSON file holds a list of worklists or a single one. Dates and times
+are accepted as YYYY-MM-DD and HH:MM[:SS]. --tag values apply to every
+worklist of the file.
+
+Each worklist needs a PatientID or PatientName and a scheduled procedure step.`,
+		Example: `  # Schedule a CT for a patient
+  orthanc worklists create \
+    --tag PatientID=12345 \
+    --tag PatientName="DOE^JOHN" \
+    --tag AccessionNumber=A0001 \
+    --tag Modality=CT \
+    --tag ScheduledStationAETitle=CT1 \
+    --tag ScheduledProcedureStepStartDate=2024-05-02 \
+    --tag ScheduledProcedureStepStartTime=09:30
+
+  # Create the worklists of a CSV export, all for the same station
+  orthanc worklists create --input schedule.csv --tag ScheduledStationAETitle=CT1
+
+  # Show the worklists a YAML file would create, without creating them
+  orthanc worklists create --input schedule.yaml --dry-run`,
+		Annotations: map[string]string{guard.Annotation: guard.Mutating},
+		Args:        cobra.NoArgs,
+		RunE: func(c *cobra.Command, args []string) error {
+			return runCreate(flags)
+		},
+	}
+
+	// Add flags
+	command.Flags().StringVarP(&flags.input, "input", "i", "", "CSV, YAML or JSON file with the worklists")
+	command.Flags().StringArrayVar(&flags.tags, "tag", nil, "DICOM tag as Name=Value, applied to every worklist (can be repeated)")
+	command.Flags().BoolVar(&flags.dryRun, "dry-run", false, "Print the tags of the worklists without creating them")
+	command.Flags().IntVar(&flags.parallel, "parallel", parallel.DefaultWorkers, "Number of worklists created in parallel")
+	command.Flags().BoolVar(&flags.jsonOutput, "json", false, "Output in JSON format")
+
+	return command
+}
+
+func runCreate(flags *CreateFlags) error {
+	inputs, err := loadWorklists(flags.input, flags.tags)
+	if err != nil {
+		return err
+	}
+
+	if flags.dryRun {
+		tags := make([]map[string]interface{}, len(inputs))
+		for i, input := range inputs {
+			tags[i] = input.tags
+		}
+		data, err := json.MarshalIndent(tags, "", "  ")
+		if err != nil {
+			return fmt.Errorf("failed to marshal JSON: %w", err)
+		}
+		fmt.Println(string(data))
+		return nil
+	}
+
+	sources := make([]string, len(inputs))
+	for i, input := range inputs {
+		sources[i] = input.source
+	}
+	created := make([]*client.WorklistCreated, len(inputs))
+
+	// Get the Orthanc client
+	client, err := getClient()
+	if err != nil {
+		return fmt.Errorf("failed to create client: %w", err)
+	}
+
+	// Check if JSON output should be used (flag or config)
+	jsonOutput := flags.jsonOutput || shouldUseJSON()
+
+	err = batch.Run(sources, flags.parallel, func(i int) error {
+		worklist, err := client.CreateWorklist(inputs[i].tags)
+		if err != nil {
+			return fmt.Errorf("failed to create worklist: %w", err)
+		}
+		created[i] = worklist
+		return nil
+	}, func(i int) error {
+		if !jsonOutput {
+			fmt.Printf("Created worklist %s for patient %s\n", created[i].ID, patientLabel(inputs[i].tags))
+		}
+		return nil
+	})
+
+	if jsonOutput {
+		if jsonErr := displayCreated(created); jsonErr != nil {
+			return jsonErr
+		}
+	}
+	return err
+}
+
+// displayCreated prints the worklists created in JSON format, even if some
+// failed to be created
+func displayCreated(created []*client.WorklistCreated) error {
+	succeeded := make([]*client.WorklistCreated, 0, len(created))
+	for _, worklist := range created {
+		if worklist != nil {
+			succeeded = append(succeeded, worklist)
+		}
+	}
+	data, err := json.MarshalIndent(succeeded, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal JSON: %w", err)
+	}
+	fmt.Println(string(data))
+	return nil
+}
+
+// loadWorklists reads the worklists of the input file, or a single worklist
+// from the overrides alone, and maps them to worklist tags
+func loadWorklists(path string, overrides []string) ([]worklistInput, error) {
+	extra := map[string]interface{}{}
+	for _, override := range overrides {
+		name, value, ok := strings.Cut(override, "=")
+		if !ok || name == "" {
+			return nil, clierr.Validation("invalid --tag '%s', expected Name=Value", override)
+		}
+		extra[name] = value
+	}
+
+	var inputs []worklistInput
+	switch {
+	case path != "":
+		var err error
+		inputs, err = readWorklistFile(path)
+		if err != nil {
+			return nil, err
+		}
+		if len(inputs) == 0 {
+			return nil, clierr.Validation("no worklists in %s", path)
+		}
+	case len(extra) > 0:
+		inputs = []worklistInput{{source: "--tag", tags: map[string]interface{}{}}}
+	default:
+		return nil, clierr.Validation("nothing to create, provide --input or --tag")
+	}
+
+	for i := range inputs {
+		for name, value := range extra {
+			inputs[i].tags[name] = value
+		}
+		if err := mapWorklistTags(inputs[i].tags); err != nil {
+			return nil, clierr.Validation("%s: %v", inputs[i].source, err)
+		}
+	}
+	return inputs, nil
+}
+
+// readWorklistFile reads the worklists of a CSV, YAML or JSON file
+func readWorklistFile(path string) ([]worklistInput, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, clierr.Validation("failed to read input file: %v", err)
+	}
+	defer file.Close()
+
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".csv":
+		return readWorklistCSV(file, path)
+	case ".yaml", ".yml", ".json":
+		return readWorklistDocument(file, path)
+	default:
+		return nil, clierr.Validation("unsupported input file %s, expected a .csv, .yaml, .yml or .json file", path)
+	}
+}
+
+// readWorklistCSV reads one worklist per row, the first row naming the tags
+func readWorklistCSV(r io.Reader, path string) ([]worklistInput, error) {
+	reader := csv.NewReader(r)
+	reader.Comment = '#'
+	reader.TrimLeadingSpace = true
+
+	header, err := reader.Read()
+	if errors.Is(err, io.EOF) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, clierr.Validation("failed to parse %s: %v", path, err)
+	}
+	for i := range header {
+		header[i] = strings.TrimSpace(header[i])
+	}
+	// Spreadsheets often save CSV files with a byte order mark
+	header[0] = strings.TrimPrefix(header[0], "\ufeff")
+
+	var inputs []worklistInput
+	for {
+		record, err := reader.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, clierr.Validation("failed to parse %s: %v", path, err)
+		}
+
+		line, _ := reader.FieldPos(0)
+		tags := map[string]interface{}{}
+		for i, value := range record {
+			value = strings.TrimSpace(value)
+			if value != "" && header[i] != "" {
+				tags[header[i]] = value
+			}
+		}
+		inputs = append(inputs, worklistInput{source: fmt.Sprintf("%s line %d", path, line), tags: tags})
+	}
+	return inputs, nil
+}
+
+// readWorklistDocument reads a YAML or JSON list of worklists, or a single one
+func readWorklistDocument(r io.Reader, path string) ([]worklistInput, error) {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return nil, clierr.Validation("failed to read input file: %v", err)
+	}
+
+	// JSON documents are valid YAML
+	document, err := helpers.ParseYAML(data)
+	if err != nil {
+		return nil, clierr.Validation("failed to parse %s: %v", path, err)
+	}
+
+	var entries []interface{}
+	switch value := document.(type) {
+	case nil:
+		return nil, nil
+	case []interface{}:
+		entries = value
+	default:
+		entries = []interface{}{value}
+	}
+
+	inputs := make([]worklistInput, len(entries))
+	for i, entry := range entries {
+		inputs[i].source = fmt.Sprintf("%s entry %d", path, i+1)
+		tags, ok := entry.(map[string]interface{})
+		if !ok {
+			return nil, clierr.Validation("%s: expected a mapping of tag names to values", inputs[i].source)
+		}
+		inputs[i].tags = tags
+	}
+	return inputs, nil
+}
+
+// mapWorklistTags converts the values of a worklist to DICOM strings and moves
+// the flat scheduled procedure step attributes into the step sequence
+func mapWorklistTags(tags map[string]interface{}) error {
+	for name, value := range tags {
+		tags[name] = normalizeTagValue(name, value)
+	}
+
+	step := map[string]interface{}{}
+	for name, value := range tags {
+		if client.WorklistStepTags[name] {
+			step[name] = value
+			delete(tags, name)
+		}
+	}
+
+	steps, _ := tags[client.WorklistStepSequence].([]interface{})
+	if _, ok := tags[client.WorklistStepSequence]; ok && steps == nil {
+		return fmt.Errorf("%s must be a list of items", client.WorklistStepSequence)
+	}
+	if len(step) > 0 {
+		if len(steps) == 0 {
+			steps = []interface{}{map[string]interface{}{}}
+		}
+		first, ok := steps[0].(map[string]interface{})
+		if !ok {
+			return fmt.Errorf("the items of %s must be mappings of tag names to values", client.WorklistStepSequence)
+		}
+		for name, value := range step {
+			first[name] = value
+		}
+		tags[client.WorklistStepSequence] = steps
+	}
+
+	if tagString(tags, "PatientID") == "" && tagString(tags, "PatientName") == "" {
+		return fmt.Errorf("a PatientID or PatientName is required")
+	}
+	if len(steps) == 0 {
+		return fmt.Errorf("a scheduled procedure step is required, set e.g. Modality and ScheduledStationAETitle")
+	}
+	return nil
+}
+
+// normalizeTagValue converts a tag value to the form Orthanc expects: strings,
+// with dates and times in the DICOM form, recursing into sequences
+func normalizeTagValue(name string, value interface{}) interface{} {
+	switch v := value.(type) {
+	case nil:
+		return v
+	case string:
+		switch {
+		case strings.HasSuffix(name, "Date"):
+			return isoDate.ReplaceAllString(v, "$1$2$3")
+		case strings.HasSuffix(name, "Time"):
+			return isoTime.ReplaceAllString(v, "$1$2$3")
+		}
+		return v
+	case []interface{}:
+		for i := range v {
+			v[i] = normalizeTagValue(name, v[i])
+		}
+		return v
+	case map[string]interface{}:
+		for key := range v {
+			v[key] = normalizeTagValue(key, v[key])
+		}
+		return v
+	default:
+		return normalizeTagValue(name, fmt.Sprint(v))
+	}
+}
+
+// patientLabel names the patient of a worklist in messages
+func patientLabel(tags map[string]interface{}) string {
+	id, name := tagString(tags, "PatientID"), tagString(tags, "PatientName")
+	switch {
+	case id == "":
+		return name
+	case name == "":
+		return id
+	}
+	return fmt.Sprintf("%s (%s)", id, name)
+}
@@ -0,0 +1,90 @@
+package worklists
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/proencaj/orthanc-cli/internal/batch"
+	"github.com/proencaj/orthanc-cli/internal/client"
+	"github.com/proencaj/orthanc-cli/internal/parallel"
+	"github.com/spf13/cobra"
+)
+
+// GetFlags holds the flags for the get command
+type GetFlags struct {
+	parallel   int
+	jsonOutput bool
+}
+
+// NewGetCommand creates the worklists get command
+func NewGetCommand() *cobra.Command {
+	flags := &GetFlags{}
+
+	command := &cobra.Command{
+		Use:   "get <worklist-id>...",
+		Short: "Get the tags of a worklist",
+		Long:  `Retrieve and display all the DICOM tags of one or more worklists.`,
+		Example: `  # Get a worklist
+  orthanc worklists get 3f2504e0-4f89-11d3-9a0c-0305e82c3301
+
+  # Output in JSON format
+  orthanc worklists get 3f2504e0-4f89-11d3-9a0c-0305e82c3301 --json`,
+		Args: cobra.MinimumNArgs(1),
+		RunE: func(c *cobra.Command, args []string) error {
+			return runGet(args, flags)
+		},
+	}
+
+	// Add flags
+	command.Flags().IntVar(&flags.parallel, "parallel", parallel.DefaultWorkers, "Number of worklists fetched in parallel")
+	command.Flags().BoolVar(&flags.jsonOutput, "json", false, "Output in JSON format")
+
+	return command
+}
+
+func runGet(args []string, flags *GetFlags) error {
+	ids, err := batch.ReadIDs(args)
+	if err != nil {
+		return err
+	}
+
+	worklists := make([]*client.Worklist, len(ids))
+
+	// Get the Orthanc client
+	client, err := getClient()
+	if err != nil {
+		return fmt.Errorf("failed to create client: %w", err)
+	}
+
+	// Check if JSON output should be used (flag or config)
+	jsonOutput := flags.jsonOutput || shouldUseJSON()
+
+	return batch.Run(ids, flags.parallel, func(i int) error {
+		worklist, err := client.GetWorklist(ids[i])
+		if err != nil {
+			return fmt.Errorf("failed to fetch worklist: %w", err)
+		}
+		worklists[i] = worklist
+		return nil
+	}, func(i int) error {
+		return displayWorklist(worklists[i], jsonOutput)
+	})
+}
+
+func displayWorklist(worklist *client.Worklist, jsonOutput bool) error {
+	if jsonOutput {
+		data, err := json.MarshalIndent(worklist, "", "  ")
+		if err != nil {
+			return fmt.Errorf("failed to marshal JSON: %w", err)
+		}
+		fmt.Println(string(data))
+		return nil
+	}
+
+	// Raw text output
+	fmt.Printf("Worklist: %s\n", worklist.ID)
+	printTags(worklist.Tags, "  ")
+	fmt.Println()
+
+	return nil
+}
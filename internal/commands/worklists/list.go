@@ -0,0 +1,117 @@
+package worklists
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+
+	"github.com/spf13/cobra"
+)
+
+// ListFlags holds the flags for the list command
+type ListFlags struct {
+	jsonOutput bool
+}
+
+// NewListCommand creates the worklists list command
+func NewListCommand() *cobra.Command {
+	flags := &ListFlags{}
+
+	command := &cobra.Command{
+		Use:   "list",
+		Short: "List the worklists of the Orthanc server",
+		Long: `List the worklist entries of the worklists plugin, sorted by scheduled date
+and time, with the patient and the first scheduled procedure step.`,
+		Example: `  # List the worklists
+  orthanc worklists list
+
+  # Output in JSON format, with all the tags
+  orthanc worklists list --json`,
+		Args: cobra.NoArgs,
+		RunE: func(c *cobra.Command, args []string) error {
+			return runList(flags)
+		},
+	}
+
+	// Add flags
+	command.Flags().BoolVar(&flags.jsonOutput, "json", false, "Output in JSON format")
+
+	return command
+}
+
+func runList(flags *ListFlags) error {
+	// Get the Orthanc client
+	client, err := getClient()
+	if err != nil {
+		return fmt.Errorf("failed to create client: %w", err)
+	}
+
+	// Check if JSON output should be used (flag or config)
+	jsonOutput := flags.jsonOutput || shouldUseJSON()
+
+	worklists, err := client.ListWorklists()
+	if err != nil {
+		return fmt.Errorf("failed to fetch worklists: %w", err)
+	}
+
+	sort.SliceStable(worklists, func(i, j int) bool {
+		return scheduled(worklists[i].Tags) < scheduled(worklists[j].Tags)
+	})
+
+	if jsonOutput {
+		data, err := json.MarshalIndent(worklists, "", "  ")
+		if err != nil {
+			return fmt.Errorf("failed to marshal JSON: %w", err)
+		}
+		fmt.Println(string(data))
+		return nil
+	}
+
+	// Raw text output
+	if len(worklists) == 0 {
+		fmt.Println("No worklists found")
+		return nil
+	}
+
+	fmt.Printf("%-36s  %-12s  %-24s  %-14s  %-8s  %-16s  %-16s  %s\n",
+		"ID", "PATIENT ID", "PATIENT NAME", "ACCESSION", "MODALITY", "STATION AET", "SCHEDULED", "DESCRIPTION")
+	for _, worklist := range worklists {
+		description := stepTag(worklist.Tags, "ScheduledProcedureStepDescription")
+		if description == "" {
+			description = tagString(worklist.Tags, "RequestedProcedureDescription")
+		}
+		fmt.Printf("%-36s  %-12s  %-24s  %-14s  %-8s  %-16s  %-16s  %s\n",
+			worklist.ID,
+			tagString(worklist.Tags, "PatientID"),
+			tagString(worklist.Tags, "PatientName"),
+			tagString(worklist.Tags, "AccessionNumber"),
+			stepTag(worklist.Tags, "Modality"),
+			stepTag(worklist.Tags, "ScheduledStationAETitle"),
+			formatScheduled(worklist.Tags),
+			description)
+	}
+
+	return nil
+}
+
+// scheduled returns the scheduled date and time of a worklist, as a sort key
+func scheduled(tags map[string]interface{}) string {
+	return stepTag(tags, "ScheduledProcedureStepStartDate") + stepTag(tags, "ScheduledProcedureStepStartTime")
+}
+
+// formatScheduled returns the scheduled date and time of a worklist as
+// YYYY-MM-DD HH:MM, or the raw DICOM values if they are not in that form
+func formatScheduled(tags map[string]interface{}) string {
+	date := stepTag(tags, "ScheduledProcedureStepStartDate")
+	clock := stepTag(tags, "ScheduledProcedureStepStartTime")
+	if len(date) == 8 {
+		date = date[:4] + "-" + date[4:6] + "-" + date[6:]
+	}
+	if len(clock) >= 4 {
+		clock = clock[:2] + ":" + clock[2:4]
+	}
+	if clock == "" {
+		return date
+	}
+	return date + " " + clock
+}
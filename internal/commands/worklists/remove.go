@@ -0,0 +1,116 @@
+package worklists
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"slices"
+	"strings"
+
+	"github.com/proencaj/orthanc-cli/internal/batch"
+	"github.com/proencaj/orthanc-cli/internal/clierr"
+	"github.com/proencaj/orthanc-cli/internal/guard"
+	"github.com/proencaj/orthanc-cli/internal/parallel"
+	"github.com/spf13/cobra"
+)
+
+// RemoveFlags holds the flags for the remove command
+type RemoveFlags struct {
+	force    bool
+	parallel int
+}
+
+// NewRemoveCommand creates the worklists remove command
+func NewRemoveCommand() *cobra.Command {
+	flags := &RemoveFlags{}
+
+	command := &cobra.Command{
+		Use:   "remove <worklist-id>...",
+		Short: "Remove a worklist from the Orthanc server",
+		Long: `Delete worklist entries, so that modalities no longer get them in their
+worklist C-FIND answers. This operation is irreversible.`,
+		Example: `  # Remove a worklist with confirmation prompt
+  orthanc worklists remove 3f2504e0-4f89-11d3-9a0c-0305e82c3301
+
+  # Remove a worklist without confirmation
+  orthanc worklists remove 3f2504e0-4f89-11d3-9a0c-0305e82c3301 --force
+
+  # Remove several worklists listed in a file, one ID per line
+  orthanc worklists remove - --force < worklists.txt`,
+		Annotations: map[string]string{
+			guard.Annotation:  guard.Destructive,
+			guard.ConfirmFlag: "force",
+		},
+		Args: cobra.MinimumNArgs(1),
+		RunE: func(c *cobra.Command, args []string) error {
+			return runRemove(args, flags)
+		},
+	}
+
+	// Add flags
+	command.Flags().BoolVarP(&flags.force, "force", "f", false, "Skip confirmation prompt")
+	command.Flags().IntVar(&flags.parallel, "parallel", parallel.DefaultWorkers, "Number of worklists deleted in parallel")
+
+	return command
+}
+
+func runRemove(args []string, flags *RemoveFlags) error {
+	ids, err := batch.ReadIDs(args)
+	if err != nil {
+		return err
+	}
+	if !flags.force && slices.Contains(args, batch.Stdin) {
+		return clierr.Validation("--force is required when reading IDs from stdin")
+	}
+
+	// Get the Orthanc client
+	client, err := getClient()
+	if err != nil {
+		return fmt.Errorf("failed to create client: %w", err)
+	}
+
+	// Prompt for confirmation, unless forced or already confirmed for a protected context
+	if !flags.force && !guard.Confirmed() {
+		confirmed, err := confirmRemoval(ids)
+		if err != nil {
+			return fmt.Errorf("failed to get confirmation: %w", err)
+		}
+		if !confirmed {
+			fmt.Println("Operation cancelled")
+			return nil
+		}
+	}
+
+	return batch.Run(ids, flags.parallel, func(i int) error {
+		if err := client.DeleteWorklist(ids[i]); err != nil {
+			return fmt.Errorf("failed to delete worklist: %w", err)
+		}
+		return nil
+	}, func(i int) error {
+		fmt.Printf("Successfully deleted worklist: %s\n", ids[i])
+		return nil
+	})
+}
+
+func confirmRemoval(ids []string) (bool, error) {
+	target, this := fmt.Sprintf("worklist '%s'", ids[0]), "this worklist"
+	if len(ids) > 1 {
+		target, this = fmt.Sprintf("%d worklists", len(ids)), "these worklists"
+	}
+
+	fmt.Printf("\n⚠️  WARNING: You are about to delete %s\n", target)
+	fmt.Println("Modalities will no longer get the scheduled procedures in their worklist queries.")
+	fmt.Printf("\nDo you really want to delete %s? (yes/no): ", this)
+
+	reader := bufio.NewReader(os.Stdin)
+	response, err := reader.ReadString('\n')
+	if err != nil {
+		return false, err
+	}
+
+	// Clean up the response
+	response = strings.TrimSpace(strings.ToLower(response))
+
+	// Accept "yes" or "y" as confirmation
+	return response == "yes" || response == "y", nil
+}
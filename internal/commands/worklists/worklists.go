@@ -0,0 +1,121 @@
+package worklists
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/proencaj/orthanc-cli/internal/client"
+	"github.com/proencaj/orthanc-cli/internal/config"
+	"github.com/spf13/cobra"
+)
+
+// clientGetter is a function type that returns an Orthanc client
+var clientGetter func() (*client.Client, error)
+
+// SetClientGetter sets the function to get the Orthanc client
+func SetClientGetter(getter func() (*client.Client, error)) {
+	clientGetter = getter
+}
+
+// getClient returns the Orthanc client, failing early if the server
+// does not have the worklists plugin enabled
+func getClient() (*client.Client, error) {
+	orthanc, err := loadClient()
+	if err != nil {
+		return nil, err
+	}
+	if err := orthanc.RequireFeature(client.FeatureWorklists); err != nil {
+		return nil, err
+	}
+	return orthanc, nil
+}
+
+// loadClient returns the Orthanc client using the configured getter
+func loadClient() (*client.Client, error) {
+	if clientGetter != nil {
+		return clientGetter()
+	}
+	// Fallback: try to load config from default location
+	cfg, err := config.LoadConfig("")
+	if err != nil {
+		return nil, err
+	}
+	return client.NewClient(cfg)
+}
+
+// shouldUseJSON checks if JSON output is enabled in config
+func shouldUseJSON() bool {
+	cfg, err := config.LoadConfig("")
+	if err != nil {
+		return false
+	}
+	return cfg.Output.JSON
+}
+
+// NewWorklistsCommand creates the worklists command with all subcommands
+func NewWorklistsCommand() *cobra.Command {
+	worklistsCmd := &cobra.Command{
+		Use:   "worklists",
+		Short: "Manage modality worklists",
+		Long: `List, create and remove the modality worklist entries served by the
+worklists plugin of the Orthanc server. Modalities query them with a worklist
+C-FIND to get the procedures scheduled for them.`,
+	}
+
+	// Add subcommands
+	worklistsCmd.AddCommand(NewListCommand())
+	worklistsCmd.AddCommand(NewGetCommand())
+	worklistsCmd.AddCommand(NewCreateCommand())
+	worklistsCmd.AddCommand(NewRemoveCommand())
+
+	return worklistsCmd
+}
+
+// tagString returns a tag of a worklist as a string, empty if not set
+func tagString(tags map[string]interface{}, name string) string {
+	switch value := tags[name].(type) {
+	case nil:
+		return ""
+	case string:
+		return value
+	default:
+		return fmt.Sprint(value)
+	}
+}
+
+// stepTag returns a tag of the first scheduled procedure step of a worklist
+func stepTag(tags map[string]interface{}, name string) string {
+	steps, ok := tags[client.WorklistStepSequence].([]interface{})
+	if !ok || len(steps) == 0 {
+		return ""
+	}
+	step, ok := steps[0].(map[string]interface{})
+	if !ok {
+		return ""
+	}
+	return tagString(step, name)
+}
+
+// printTags prints tags sorted by name, with the items of sequences indented
+func printTags(tags map[string]interface{}, indent string) {
+	names := make([]string, 0, len(tags))
+	for name := range tags {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		items, ok := tags[name].([]interface{})
+		if !ok {
+			fmt.Printf("%s%s: %s\n", indent, name, tagString(tags, name))
+			continue
+		}
+		fmt.Printf("%s%s:\n", indent, name)
+		for i, item := range items {
+			fmt.Printf("%s  Item %d:\n", indent, i+1)
+			if fields, ok := item.(map[string]interface{}); ok {
+				printTags(fields, indent+"    ")
+			}
+		}
+	}
+}
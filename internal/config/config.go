@@ -4,7 +4,9 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"strconv"
 
+	"github.com/proencaj/orthanc-cli/internal/clierr"
 	"github.com/spf13/viper"
 )
 
@@ -14,6 +16,11 @@ type Config struct {
 	CurrentContext string                    `mapstructure:"current-context"`
 	Output         OutputConfig              `mapstructure:"output"`
 
+	// Debug holds HTTP tracing settings. It is never read from or written to
+	// the config file; it is populated from ORTHANC_DEBUG/ORTHANC_CURL and
+	// overridden by the --debug/--curl flags.
+	Debug DebugConfig `mapstructure:"-"`
+
 	// Legacy fields for backward compatibility (deprecated)
 	Orthanc *OrthancConfig `mapstructure:"orthanc,omitempty"`
 }
@@ -21,6 +28,12 @@ type Config struct {
 // ContextConfig holds configuration for a single context
 type ContextConfig struct {
 	Orthanc OrthancConfig `mapstructure:"orthanc"`
+	// Trash keeps a local copy of every resource removed through this context
+	Trash bool `mapstructure:"trash"`
+	// ReadOnly rejects every command that changes the server
+	ReadOnly bool `mapstructure:"read-only" yaml:"read-only"`
+	// Protected requires typing the context name before destructive commands
+	Protected bool `mapstructure:"protected"`
 }
 
 // OrthancConfig holds Orthanc server configuration
@@ -36,21 +49,26 @@ type OutputConfig struct {
 	JSON bool `mapstructure:"json"`
 }
 
+// DebugConfig holds HTTP request tracing configuration
+type DebugConfig struct {
+	// Mode is the tracing mode: "" (off), "true" or "body"
+	Mode string
+	// Curl prints the equivalent curl command for each request
+	Curl bool
+}
+
 // GetCurrentContext returns the configuration for the current context
 // with environment variable overrides applied
 func (c *Config) GetCurrentContext() (*OrthancConfig, error) {
 	if c.CurrentContext == "" {
-		return nil, fmt.Errorf("no context selected")
+		return nil, clierr.Validation("no context selected")
 	}
 
-	ctx, exists := c.Contexts[c.CurrentContext]
-	if !exists {
-		return nil, fmt.Errorf("context %q not found", c.CurrentContext)
+	config, err := c.GetContext(c.CurrentContext)
+	if err != nil {
+		return nil, err
 	}
 
-	// Make a copy to avoid modifying the original
-	config := ctx.Orthanc
-
 	// Apply environment variable overrides
 	// Environment variables follow the pattern: ORTHANC_URL, ORTHANC_USERNAME, etc.
 	if url := viper.GetString("url"); url != "" {
@@ -66,9 +84,32 @@ func (c *Config) GetCurrentContext() (*OrthancConfig, error) {
 		config.Insecure = viper.GetBool("insecure")
 	}
 
+	return config, nil
+}
+
+// GetContext returns a copy of the configuration of a named context, without
+// environment variable overrides
+func (c *Config) GetContext(name string) (*OrthancConfig, error) {
+	ctx, exists := c.Contexts[name]
+	if !exists {
+		return nil, clierr.Validation("context %q not found", name)
+	}
+
+	// Make a copy to avoid modifying the original
+	config := ctx.Orthanc
 	return &config, nil
 }
 
+// TrashEnabled reports whether the current context keeps a local copy of
+// removed resources, with ORTHANC_TRASH taking precedence
+func (c *Config) TrashEnabled() bool {
+	if viper.IsSet("trash") {
+		return viper.GetBool("trash")
+	}
+	ctx, exists := c.Contexts[c.CurrentContext]
+	return exists && ctx.Trash
+}
+
 // LoadConfig reads configuration from file and environment variables
 func LoadConfig(cfgFile string) (*Config, error) {
 	if cfgFile != "" {
@@ -105,6 +146,10 @@ func LoadConfig(cfgFile string) (*Config, error) {
 		return nil, fmt.Errorf("unable to decode config: %w", err)
 	}
 
+	// Debug settings only come from the environment (ORTHANC_DEBUG, ORTHANC_CURL)
+	config.Debug.Mode = os.Getenv("ORTHANC_DEBUG")
+	config.Debug.Curl, _ = strconv.ParseBool(os.Getenv("ORTHANC_CURL"))
+
 	// Migrate legacy config format to multi-context format
 	if err := migrateConfig(&config); err != nil {
 		return nil, fmt.Errorf("error migrating config: %w", err)
@@ -197,6 +242,9 @@ contexts:
       username: "orthanc"
       password: "orthanc"
       insecure: false
+    trash: false      # Set to true to keep a local copy of removed resources (see 'orthanc trash')
+    read-only: false  # Set to true to reject every command that changes the server
+    protected: false  # Set to true to require typing the context name before destructive commands
 
 # The currently active context
 current-context: local
@@ -0,0 +1,200 @@
+// Package download saves streamed HTTP responses to disk safely: data is
+// written to a temporary file that is renamed into place only once the
+// download is complete and verified, interrupted transfers are retried
+// (resumed with a Range request when the server supports it), and a progress
+// bar is shown on terminals.
+package download
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/proencaj/orthanc-cli/internal/clierr"
+)
+
+// DefaultRetries is the default number of retries after a failed attempt
+const DefaultRetries = 3
+
+// Fetcher opens the response to download. When offset is positive the
+// download is being resumed and the fetcher should request the remaining
+// bytes (e.g. with a Range header); answering with the full content is fine.
+type Fetcher func(offset int64) (*http.Response, error)
+
+// Options configures a download
+type Options struct {
+	// Retries is the number of additional attempts after a transient failure
+	Retries int
+	// Label is shown next to the progress bar
+	Label string
+	// Verify, if set, checks the complete temporary file before it is renamed
+	Verify func(path string) error
+	// Progress receives the progress bar; nil means stderr when it is a terminal
+	Progress io.Writer
+}
+
+// Result describes a completed download
+type Result struct {
+	Path     string
+	Size     int64
+	Attempts int
+}
+
+// ToFile downloads to path through a temporary file in the same directory.
+// Nothing is left at path unless the download completes and passes Verify.
+func ToFile(path string, fetch Fetcher, opts Options) (*Result, error) {
+	if opts.Retries < 0 {
+		return nil, clierr.Validation("--retries must not be negative")
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(path), "."+filepath.Base(path)+".part-*")
+	if err != nil {
+		return nil, fmt.Errorf("failed to create temporary file: %w", err)
+	}
+	tmpPath := tmp.Name()
+	committed := false
+	defer func() {
+		if !committed {
+			tmp.Close()
+			os.Remove(tmpPath)
+		}
+	}()
+
+	progress := opts.Progress
+	if progress == nil && IsTerminal(os.Stderr) {
+		progress = os.Stderr
+	}
+	bar := newProgressBar(progress, opts.Label)
+
+	var written int64
+	attempts := 0
+	for {
+		attempts++
+		written, err = fetchInto(tmp, written, fetch, bar)
+		if err == nil {
+			break
+		}
+		bar.interrupt()
+		if attempts > opts.Retries || !isTransient(err) {
+			// A truncated transfer is a network failure
+			if errors.Is(err, io.ErrUnexpectedEOF) {
+				err = clierr.Wrap(clierr.KindNetwork, err)
+			}
+			return nil, err
+		}
+		fmt.Fprintf(os.Stderr, "Download interrupted (%v), retrying (%d/%d)...\n", err, attempts, opts.Retries)
+		time.Sleep(backoff(attempts))
+	}
+	bar.finish()
+
+	if err := tmp.Sync(); err != nil {
+		return nil, fmt.Errorf("failed to flush %s: %w", tmpPath, err)
+	}
+	// CreateTemp uses 0600, give the result the usual permissions of a new file
+	if err := tmp.Chmod(0644); err != nil {
+		return nil, fmt.Errorf("failed to set permissions on %s: %w", tmpPath, err)
+	}
+	if err := tmp.Close(); err != nil {
+		return nil, fmt.Errorf("failed to close %s: %w", tmpPath, err)
+	}
+
+	if opts.Verify != nil {
+		if err := opts.Verify(tmpPath); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := os.Rename(tmpPath, path); err != nil {
+		return nil, fmt.Errorf("failed to move download into place: %w", err)
+	}
+	committed = true
+
+	return &Result{Path: path, Size: written, Attempts: attempts}, nil
+}
+
+// fetchInto performs one attempt, resuming after the offset bytes already in
+// file when the server honours the range, and returns the new file size
+func fetchInto(file *os.File, offset int64, fetch Fetcher, bar *progressBar) (int64, error) {
+	resp, err := fetch(offset)
+	if err != nil {
+		return offset, err
+	}
+	defer resp.Body.Close()
+
+	total := resp.ContentLength
+	if offset > 0 && resp.StatusCode == http.StatusPartialContent && rangeStart(resp) == offset {
+		if total >= 0 {
+			total += offset
+		}
+	} else {
+		// Full content: start over
+		offset = 0
+		if err := file.Truncate(0); err != nil {
+			return 0, fmt.Errorf("failed to truncate %s: %w", file.Name(), err)
+		}
+	}
+	if _, err := file.Seek(offset, io.SeekStart); err != nil {
+		return 0, fmt.Errorf("failed to seek in %s: %w", file.Name(), err)
+	}
+
+	bar.start(offset, total)
+	n, err := io.Copy(file, io.TeeReader(resp.Body, bar))
+	offset += n
+	if err != nil {
+		return offset, fmt.Errorf("download interrupted after %d bytes: %w", offset, err)
+	}
+	if total >= 0 && offset < total {
+		return offset, fmt.Errorf("download interrupted after %d of %d bytes: %w", offset, total, io.ErrUnexpectedEOF)
+	}
+	return offset, nil
+}
+
+// rangeStart returns the first byte of a Content-Range header, or -1
+func rangeStart(resp *http.Response) int64 {
+	value := strings.TrimPrefix(resp.Header.Get("Content-Range"), "bytes ")
+	first, _, ok := strings.Cut(value, "-")
+	if !ok {
+		return -1
+	}
+	start, err := strconv.ParseInt(first, 10, 64)
+	if err != nil {
+		return -1
+	}
+	return start
+}
+
+// isTransient reports whether a failed attempt is worth retrying
+func isTransient(err error) bool {
+	if errors.Is(err, io.ErrUnexpectedEOF) {
+		return true
+	}
+	switch clierr.KindOf(err) {
+	case clierr.KindNetwork, clierr.KindTimeout, clierr.KindServer:
+		return true
+	}
+	return false
+}
+
+// backoff returns the delay before the next attempt
+func backoff(attempt int) time.Duration {
+	delay := time.Duration(attempt) * time.Second
+	if delay > 10*time.Second {
+		delay = 10 * time.Second
+	}
+	return delay
+}
+
+// IsTerminal reports whether f is an interactive terminal
+func IsTerminal(f *os.File) bool {
+	info, err := f.Stat()
+	if err != nil {
+		return false
+	}
+	return info.Mode()&os.ModeCharDevice != 0
+}
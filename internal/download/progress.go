@@ -0,0 +1,102 @@
+package download
+
+import (
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/proencaj/orthanc-cli/internal/helpers"
+)
+
+// progressWidth is the number of characters of the bar itself
+const progressWidth = 30
+
+// progressInterval limits how often the bar is redrawn
+const progressInterval = 100 * time.Millisecond
+
+// progressBar draws a single-line progress bar. A nil output disables it.
+type progressBar struct {
+	out     io.Writer
+	label   string
+	current int64
+	total   int64
+	started time.Time
+	base    int64
+	drawn   time.Time
+	active  bool
+}
+
+// newProgressBar creates a progress bar writing to out
+func newProgressBar(out io.Writer, label string) *progressBar {
+	return &progressBar{out: out, label: label}
+}
+
+// start begins an attempt at offset; total is -1 when the size is unknown
+func (p *progressBar) start(offset, total int64) {
+	p.current = offset
+	p.base = offset
+	p.total = total
+	p.started = time.Now()
+	p.drawn = time.Time{}
+}
+
+// Write implements io.Writer, counting the bytes that pass through
+func (p *progressBar) Write(data []byte) (int, error) {
+	p.current += int64(len(data))
+	if p.out != nil && time.Since(p.drawn) >= progressInterval {
+		p.draw()
+	}
+	return len(data), nil
+}
+
+// interrupt ends the current line after a failed attempt
+func (p *progressBar) interrupt() {
+	if p.out != nil && p.active {
+		fmt.Fprintln(p.out)
+		p.active = false
+	}
+}
+
+// finish draws the final state and ends the line
+func (p *progressBar) finish() {
+	if p.out == nil {
+		return
+	}
+	p.draw()
+	fmt.Fprintln(p.out)
+	p.active = false
+}
+
+// draw renders the bar
+func (p *progressBar) draw() {
+	p.drawn = time.Now()
+	p.active = true
+
+	rate := ""
+	if elapsed := time.Since(p.started).Seconds(); elapsed > 0.5 {
+		rate = fmt.Sprintf("  %s/s", helpers.FormatBytes(int64(float64(p.current-p.base)/elapsed)))
+	}
+
+	label := ""
+	if p.label != "" {
+		label = p.label + " "
+	}
+
+	if p.total <= 0 {
+		fmt.Fprintf(p.out, "\r%s%s%s\033[K", label, helpers.FormatBytes(p.current), rate)
+		return
+	}
+
+	fraction := float64(p.current) / float64(p.total)
+	if fraction > 1 {
+		fraction = 1
+	}
+	filled := int(fraction * progressWidth)
+	bar := strings.Repeat("=", filled)
+	if filled < progressWidth {
+		bar += ">" + strings.Repeat(" ", progressWidth-filled-1)
+	}
+	fmt.Fprintf(p.out, "\r%s[%s] %3.0f%%  %s / %s%s\033[K",
+		label, bar, fraction*100, helpers.FormatBytes(p.current), helpers.FormatBytes(p.total), rate)
+}
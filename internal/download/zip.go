@@ -0,0 +1,53 @@
+package download
+
+import (
+	"archive/zip"
+	"fmt"
+	"path"
+	"strings"
+)
+
+// ZipInfo summarizes the content of a ZIP archive
+type ZipInfo struct {
+	// Entries is the number of files, excluding directories
+	Entries int
+	// DicomFiles is the number of files that are not DICOMDIR indexes
+	DicomFiles int
+}
+
+// InspectZip reads the central directory of a ZIP archive, failing if the
+// archive is truncated or corrupt
+func InspectZip(file string) (*ZipInfo, error) {
+	reader, err := zip.OpenReader(file)
+	if err != nil {
+		return nil, fmt.Errorf("downloaded archive is not a valid ZIP file: %w", err)
+	}
+	defer reader.Close()
+
+	info := &ZipInfo{}
+	for _, entry := range reader.File {
+		if entry.FileInfo().IsDir() || strings.HasSuffix(entry.Name, "/") {
+			continue
+		}
+		info.Entries++
+		if !strings.EqualFold(path.Base(entry.Name), "DICOMDIR") {
+			info.DicomFiles++
+		}
+	}
+	return info, nil
+}
+
+// VerifyZip returns a Verify function that checks the ZIP central directory
+// and, when expectedDicomFiles is positive, the number of DICOM files
+func VerifyZip(expectedDicomFiles int) func(string) error {
+	return func(file string) error {
+		info, err := InspectZip(file)
+		if err != nil {
+			return err
+		}
+		if expectedDicomFiles > 0 && info.DicomFiles != expectedDicomFiles {
+			return fmt.Errorf("downloaded archive contains %d DICOM file(s), expected %d", info.DicomFiles, expectedDicomFiles)
+		}
+		return nil
+	}
+}
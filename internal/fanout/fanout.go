@@ -0,0 +1,161 @@
+// Package fanout runs a query against the servers of several contexts at once,
+// for the commands accepting --contexts and --all-contexts.
+package fanout
+
+import (
+	"fmt"
+	"os"
+	"sort"
+
+	"github.com/proencaj/orthanc-cli/internal/client"
+	"github.com/proencaj/orthanc-cli/internal/clierr"
+	"github.com/proencaj/orthanc-cli/internal/config"
+	"github.com/proencaj/orthanc-cli/internal/parallel"
+	"github.com/spf13/cobra"
+)
+
+// configGetter is a function type that returns the loaded configuration
+var configGetter func() *config.Config
+
+// clientGetter is a function type that returns an Orthanc client for a named context
+var clientGetter func(name string) (*client.Client, error)
+
+// SetConfigGetter sets the function to get the loaded configuration
+func SetConfigGetter(getter func() *config.Config) {
+	configGetter = getter
+}
+
+// SetClientGetter sets the function to get the Orthanc client of a named context
+func SetClientGetter(getter func(name string) (*client.Client, error)) {
+	clientGetter = getter
+}
+
+// getConfig returns the configuration using the configured getter
+func getConfig() (*config.Config, error) {
+	if configGetter != nil {
+		if cfg := configGetter(); cfg != nil {
+			return cfg, nil
+		}
+	}
+	// Fallback: try to load config from default location
+	return config.LoadConfig("")
+}
+
+// getClient returns the Orthanc client of a named context using the configured getter
+func getClient(cfg *config.Config, name string) (*client.Client, error) {
+	if clientGetter != nil {
+		return clientGetter(name)
+	}
+	return client.NewClientForContext(cfg, name)
+}
+
+// Flags holds the --contexts and --all-contexts flags of a command
+type Flags struct {
+	contexts    []string
+	allContexts bool
+}
+
+// AddFlags adds --contexts and --all-contexts to a command
+func AddFlags(command *cobra.Command, flags *Flags) {
+	command.Flags().StringSliceVar(&flags.contexts, "contexts", nil, "Query these contexts concurrently (comma-separated) and merge the results")
+	command.Flags().BoolVar(&flags.allContexts, "all-contexts", false, "Query every configured context concurrently and merge the results")
+	command.MarkFlagsMutuallyExclusive("contexts", "all-contexts")
+}
+
+// Enabled reports whether the command should query several contexts
+func (f *Flags) Enabled() bool {
+	return f.allContexts || len(f.contexts) > 0
+}
+
+// Result is the outcome of a query against one context
+type Result[T any] struct {
+	Context string
+	Value   T
+	Err     error
+}
+
+// Run resolves the contexts named by flags and runs query against each of
+// them concurrently. Failures are kept in the results, in the order of the
+// contexts, so that one unreachable server does not hide the others.
+func Run[T any](flags *Flags, query func(c *client.Client) (T, error)) ([]Result[T], error) {
+	cfg, err := getConfig()
+	if err != nil {
+		return nil, err
+	}
+
+	names, err := contextNames(cfg, flags)
+	if err != nil {
+		return nil, err
+	}
+
+	results := make([]Result[T], len(names))
+	parallel.ForEach(len(names), len(names), func(i int) error {
+		results[i].Context = names[i]
+		c, err := getClient(cfg, names[i])
+		if err != nil {
+			results[i].Err = fmt.Errorf("failed to create client: %w", err)
+			return results[i].Err
+		}
+		results[i].Value, results[i].Err = query(c)
+		return results[i].Err
+	})
+	return results, nil
+}
+
+// contextNames returns the contexts to query: every configured context in
+// name order, or the ones listed, which must exist
+func contextNames(cfg *config.Config, flags *Flags) ([]string, error) {
+	if flags.allContexts {
+		names := make([]string, 0, len(cfg.Contexts))
+		for name := range cfg.Contexts {
+			names = append(names, name)
+		}
+		if len(names) == 0 {
+			return nil, clierr.Validation("no contexts configured")
+		}
+		sort.Strings(names)
+		return names, nil
+	}
+
+	var names []string
+	seen := map[string]bool{}
+	for _, name := range flags.contexts {
+		if name == "" || seen[name] {
+			continue
+		}
+		if _, exists := cfg.Contexts[name]; !exists {
+			return nil, clierr.NotFound("context %q not found", name)
+		}
+		seen[name] = true
+		names = append(names, name)
+	}
+	if len(names) == 0 {
+		return nil, clierr.Validation("--contexts requires at least one context name")
+	}
+	return names, nil
+}
+
+// Report prints the failed contexts to stderr and returns nil if none failed,
+// the first error if all failed, or a partial failure error
+func Report[T any](results []Result[T]) error {
+	failed := 0
+	var firstErr error
+	for _, result := range results {
+		if result.Err == nil {
+			continue
+		}
+		failed++
+		if firstErr == nil {
+			firstErr = fmt.Errorf("context '%s': %w", result.Context, result.Err)
+		}
+		fmt.Fprintf(os.Stderr, "Error: context '%s': %v\n", result.Context, result.Err)
+	}
+
+	switch failed {
+	case 0:
+		return nil
+	case len(results):
+		return firstErr
+	}
+	return clierr.Partial(failed, len(results))
+}
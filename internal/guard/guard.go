@@ -0,0 +1,198 @@
+// Package guard enforces the read-only and protected settings of contexts.
+//
+// Commands declare what they do to the server with the Annotation cobra
+// annotation, and Check is run for every command before it executes, so the
+// settings cannot be bypassed by a command forgetting to check them.
+// Destructive commands name the flag skipping their prompt with ConfirmFlag.
+package guard
+
+import (
+	"bufio"
+	"fmt"
+	"net/http"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/proencaj/orthanc-cli/internal/clierr"
+	"github.com/proencaj/orthanc-cli/internal/config"
+	"github.com/spf13/cobra"
+)
+
+// Annotation is the cobra annotation holding the effect of a command
+const Annotation = "orthanc-cli/effect"
+
+// ConfirmFlag is the cobra annotation naming the flag, if any, that skips the
+// confirmation prompt of a destructive command
+const ConfirmFlag = "orthanc-cli/confirm-flag"
+
+// Effects of a command on the server. Commands without the annotation only read.
+const (
+	// Mutating commands create or change resources or settings
+	Mutating = "mutating"
+	// Destructive commands delete data or stop the server
+	Destructive = "destructive"
+	// ByMethod commands take their effect from the HTTP method given as first argument
+	ByMethod = "by-method"
+	// CrossContext commands change contexts named in their flags rather than
+	// the current one, and check them with CheckContext
+	CrossContext = "cross-context"
+)
+
+// AllowDestructiveEnv is the environment variable allowing --force on protected contexts
+const AllowDestructiveEnv = "ORTHANC_ALLOW_DESTRUCTIVE"
+
+// confirmed records that the user typed a context name in this process
+var confirmed bool
+
+// Confirmed reports whether the user confirmed a destructive command by
+// typing the context name, so that commands skip their own prompt
+func Confirmed() bool {
+	return confirmed
+}
+
+// Effect returns the effect of running command with args: "", Mutating,
+// Destructive or CrossContext
+func Effect(command *cobra.Command, args []string) string {
+	// Dry runs only show what would be done
+	if flagValue(command, "dry-run") == "true" {
+		return ""
+	}
+
+	effect := command.Annotations[Annotation]
+	if effect == ByMethod {
+		effect = methodEffect(args)
+	}
+
+	// Anonymizing without keeping the source deletes the source
+	if effect == Mutating && flagValue(command, "keep-source") == "false" {
+		return Destructive
+	}
+	return effect
+}
+
+// methodEffect returns the effect of a raw HTTP request
+func methodEffect(args []string) string {
+	if len(args) == 0 {
+		return ""
+	}
+	switch strings.ToUpper(args[0]) {
+	case http.MethodGet, http.MethodHead:
+		return ""
+	case http.MethodDelete:
+		return Destructive
+	}
+	return Mutating
+}
+
+// Check enforces the settings of the current context for command: read-only
+// contexts reject mutating commands, and protected contexts require typing
+// the context name before destructive ones, refusing --force unless
+// ORTHANC_ALLOW_DESTRUCTIVE is set.
+func Check(command *cobra.Command, args []string, cfg *config.Config) error {
+	effect := Effect(command, args)
+	if effect == CrossContext || cfg == nil {
+		return nil
+	}
+	return CheckContext(command, cfg, cfg.CurrentContext, effect)
+}
+
+// CheckContext enforces the settings of a named context for a command with
+// the given effect on it, like Check does for the current context
+func CheckContext(command *cobra.Command, cfg *config.Config, name, effect string) error {
+	if effect == "" {
+		return nil
+	}
+	ctx, exists := cfg.Contexts[name]
+	if !exists {
+		return nil
+	}
+
+	if ctx.ReadOnly {
+		return clierr.New(clierr.KindForbidden, "context %q is read-only, '%s' is not allowed (orthanc config set read-only false)", name, command.CommandPath())
+	}
+	if !ctx.Protected || effect != Destructive {
+		return nil
+	}
+
+	allowed, _ := strconv.ParseBool(os.Getenv(AllowDestructiveEnv))
+	if forced(command) {
+		if allowed {
+			return nil
+		}
+		return clierr.New(clierr.KindForbidden, "context %q is protected, confirmation cannot be skipped unless %s=1 is set", name, AllowDestructiveEnv)
+	}
+
+	// The prompt cannot share stdin with the command's input
+	if readsStdin(command) {
+		if allowed {
+			return nil
+		}
+		return clierr.Validation("context %q is protected and '%s' reads its input from stdin, so the context name cannot be asked; set %s=1 to run it without confirmation", name, command.CommandPath(), AllowDestructiveEnv)
+	}
+
+	tty, err := os.OpenFile("/dev/tty", os.O_RDWR, 0)
+	if err != nil {
+		if allowed {
+			return nil
+		}
+		return clierr.Validation("context %q is protected and no terminal is available to type the context name; set %s=1 to run '%s' without confirmation", name, AllowDestructiveEnv, command.CommandPath())
+	}
+	defer tty.Close()
+
+	ok, err := confirmContext(tty, name, command.CommandPath())
+	if err != nil {
+		return fmt.Errorf("failed to get confirmation: %w", err)
+	}
+	if !ok {
+		return clierr.New(clierr.KindGeneric, "operation cancelled, the context name did not match")
+	}
+	confirmed = true
+	return nil
+}
+
+// readsStdin reports whether the command reads its arguments or input from
+// stdin, given as "-"
+func readsStdin(command *cobra.Command) bool {
+	for _, arg := range command.Flags().Args() {
+		if arg == "-" {
+			return true
+		}
+	}
+	return flagValue(command, "input") == "-"
+}
+
+// forced reports whether the command was told to skip its confirmation
+// prompt with the flag named by its ConfirmFlag annotation
+func forced(command *cobra.Command) bool {
+	name := command.Annotations[ConfirmFlag]
+	if name == "" {
+		return false
+	}
+	flag := command.Flags().Lookup(name)
+	if flag == nil || !flag.Changed {
+		return false
+	}
+	return flag.Value.Type() != "bool" || flag.Value.String() == "true"
+}
+
+// flagValue returns the value of a flag of command, or "" if it has no such flag
+func flagValue(command *cobra.Command, name string) string {
+	flag := command.Flags().Lookup(name)
+	if flag == nil {
+		return ""
+	}
+	return flag.Value.String()
+}
+
+// confirmContext asks the user to type the context name on the terminal
+func confirmContext(tty *os.File, contextName, commandPath string) (bool, error) {
+	fmt.Fprintf(tty, "\n⚠️  Context '%s' is protected: '%s' deletes data or stops the server.\n", contextName, commandPath)
+	fmt.Fprintf(tty, "Type the context name (%s) to continue: ", contextName)
+
+	response, err := bufio.NewReader(tty).ReadString('\n')
+	if err != nil && response == "" {
+		return false, err
+	}
+	return strings.TrimSpace(response) == contextName, nil
+}
@@ -1,8 +1,95 @@
 package helpers
 
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/proencaj/orthanc-cli/internal/clierr"
+	"go.yaml.in/yaml/v3"
+)
+
 // BoolPtr returns a pointer to the given bool value.
 // This is a helper function for creating pointers to bool literals,
 // which is commonly needed when working with API request structs.
 func BoolPtr(b bool) *bool {
 	return &b
 }
+
+// ParseAge parses an age such as 30d, 12h or 90m, as accepted by --since and
+// --older-than flags: a Go duration, or a whole number of days.
+func ParseAge(value string) (time.Duration, error) {
+	if days, ok := strings.CutSuffix(value, "d"); ok {
+		n, err := strconv.Atoi(days)
+		if err != nil || n < 0 {
+			return 0, clierr.Validation("invalid age %q (use e.g. 30d, 12h or 90m)", value)
+		}
+		return time.Duration(n) * 24 * time.Hour, nil
+	}
+	age, err := time.ParseDuration(value)
+	if err != nil || age < 0 {
+		return 0, clierr.Validation("invalid age %q (use e.g. 30d, 12h or 90m)", value)
+	}
+	return age, nil
+}
+
+// ParseYAML parses a YAML or JSON document into lists, mappings and the
+// literal strings of its scalars. DICOM values must be kept as written:
+// native decoding would read PatientID 00123 as an octal number, drop the
+// trailing zero of 1.50 and turn dates into timestamps.
+func ParseYAML(data []byte) (interface{}, error) {
+	var root yaml.Node
+	if err := yaml.Unmarshal(data, &root); err != nil {
+		return nil, err
+	}
+	return nodeValue(&root), nil
+}
+
+// nodeValue converts a YAML node to lists, mappings and the literal strings
+// of its scalars
+func nodeValue(node *yaml.Node) interface{} {
+	switch node.Kind {
+	case yaml.DocumentNode:
+		if len(node.Content) == 0 {
+			return nil
+		}
+		return nodeValue(node.Content[0])
+	case yaml.AliasNode:
+		return nodeValue(node.Alias)
+	case yaml.SequenceNode:
+		items := make([]interface{}, len(node.Content))
+		for i, item := range node.Content {
+			items[i] = nodeValue(item)
+		}
+		return items
+	case yaml.MappingNode:
+		fields := make(map[string]interface{}, len(node.Content)/2)
+		for i := 0; i+1 < len(node.Content); i += 2 {
+			fields[node.Content[i].Value] = nodeValue(node.Content[i+1])
+		}
+		return fields
+	case yaml.ScalarNode:
+		if node.ShortTag() == "!!null" {
+			return nil
+		}
+		return node.Value
+	}
+	return nil
+}
+
+// FormatBytes renders a byte count with a binary unit
+func FormatBytes(n int64) string {
+	switch {
+	case n < 1024:
+		return fmt.Sprintf("%d B", n)
+	case n < 1024*1024:
+		return fmt.Sprintf("%.1f KB", float64(n)/1024)
+	case n < 1024*1024*1024:
+		return fmt.Sprintf("%.1f MB", float64(n)/(1024*1024))
+	case n < 1024*1024*1024*1024:
+		return fmt.Sprintf("%.2f GB", float64(n)/(1024*1024*1024))
+	default:
+		return fmt.Sprintf("%.2f TB", float64(n)/(1024*1024*1024*1024))
+	}
+}
@@ -0,0 +1,51 @@
+// Package parallel runs the items of batch operations with bounded concurrency.
+package parallel
+
+import (
+	"sync"
+)
+
+// DefaultWorkers is the default number of concurrent operations
+const DefaultWorkers = 4
+
+// ForEach calls fn for every index in [0, n) using at most workers goroutines
+// and returns the error of each call, indexed like the items
+func ForEach(n, workers int, fn func(i int) error) []error {
+	errs := make([]error, n)
+	if workers < 1 {
+		workers = 1
+	}
+	if workers > n {
+		workers = n
+	}
+
+	indexes := make(chan int)
+	var wg sync.WaitGroup
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range indexes {
+				errs[i] = fn(i)
+			}
+		}()
+	}
+	for i := 0; i < n; i++ {
+		indexes <- i
+	}
+	close(indexes)
+	wg.Wait()
+
+	return errs
+}
+
+// Failed returns the number of non-nil errors
+func Failed(errs []error) int {
+	failed := 0
+	for _, err := range errs {
+		if err != nil {
+			failed++
+		}
+	}
+	return failed
+}
@@ -0,0 +1,359 @@
+// Package trash keeps a local copy of resources before they are deleted from
+// an Orthanc server, so that an accidental removal can be undone by uploading
+// the copy again.
+//
+// Each entry is a directory holding the resource archive (a single DICOM file
+// for instances) and an entry.json file describing where it came from.
+package trash
+
+import (
+	"archive/zip"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/proencaj/gorthanc/types"
+	"github.com/proencaj/orthanc-cli/internal/client"
+	"github.com/proencaj/orthanc-cli/internal/clierr"
+	"github.com/proencaj/orthanc-cli/internal/download"
+	"github.com/proencaj/orthanc-cli/internal/parallel"
+)
+
+// metadataFile is the name of the file describing an entry in its directory
+const metadataFile = "entry.json"
+
+// Entry describes a resource kept in the trash
+type Entry struct {
+	ID         string            `json:"ID"`
+	Context    string            `json:"Context"`
+	URL        string            `json:"URL"`
+	Level      string            `json:"Level"`
+	ResourceID string            `json:"ResourceID"`
+	Tags       map[string]string `json:"MainDicomTags"`
+	Instances  int               `json:"CountInstances"`
+	File       string            `json:"File"`
+	Size       int64             `json:"Size"`
+	DeletedAt  time.Time         `json:"DeletedAt"`
+
+	// Dir is the entry directory, set when the entry is loaded
+	Dir string `json:"-"`
+}
+
+// Path returns the path of the archive or DICOM file of the entry
+func (e *Entry) Path() string {
+	return filepath.Join(e.Dir, e.File)
+}
+
+// RestoreResult summarizes the upload of an entry
+type RestoreResult struct {
+	Uploaded      int `json:"Uploaded"`
+	AlreadyStored int `json:"AlreadyStored"`
+}
+
+// Dir returns the trash directory: ORTHANC_TRASH_DIR, or ~/.orthanc-cli/trash
+func Dir() (string, error) {
+	if dir := os.Getenv("ORTHANC_TRASH_DIR"); dir != "" {
+		return dir, nil
+	}
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", fmt.Errorf("failed to get home directory: %w", err)
+	}
+	return filepath.Join(home, ".orthanc-cli", "trash"), nil
+}
+
+// Save downloads a copy of a resource into a new trash entry. The archive is
+// checked to contain every instance of the resource, so that the resource is
+// only deleted once a complete copy is on disk.
+func Save(c *client.Client, level types.ResourceLevel, id string) (*Entry, error) {
+	root, err := Dir()
+	if err != nil {
+		return nil, err
+	}
+	// The trash holds patient data, keep it private
+	if err := os.MkdirAll(root, 0700); err != nil {
+		return nil, fmt.Errorf("failed to create trash directory: %w", err)
+	}
+
+	// Record the identifying tags, for trash list
+	var resource struct {
+		MainDicomTags        map[string]string `json:"MainDicomTags"`
+		PatientMainDicomTags map[string]string `json:"PatientMainDicomTags"`
+	}
+	if err := c.GetJSON(client.ResourcePath(level, id), &resource); err != nil {
+		return nil, err
+	}
+	tags := resource.MainDicomTags
+	if tags == nil {
+		tags = make(map[string]string)
+	}
+	for name, value := range resource.PatientMainDicomTags {
+		tags[name] = value
+	}
+
+	statistics, err := c.GetResourceStatistics(level, id)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now().UTC()
+	entry := &Entry{
+		Context:    c.ContextName(),
+		URL:        c.URL(),
+		Level:      string(level),
+		ResourceID: id,
+		Tags:       tags,
+		Instances:  statistics.CountInstances,
+		DeletedAt:  now,
+	}
+	entry.ID, entry.Dir, err = createEntryDir(root, level, id, now)
+	if err != nil {
+		return nil, err
+	}
+
+	saved := false
+	defer func() {
+		if !saved {
+			os.RemoveAll(entry.Dir)
+		}
+	}()
+
+	// Instances are kept as a DICOM file, other levels as a ZIP archive
+	resourcePath := client.ResourcePath(level, id) + "/archive"
+	entry.File = strings.ToLower(string(level)) + ".zip"
+	verify := download.VerifyZip(statistics.CountInstances)
+	if level == types.ResourceLevelInstance {
+		resourcePath = client.ResourcePath(level, id) + "/file"
+		entry.File = "instance.dcm"
+		verify = nil
+	}
+
+	result, err := download.ToFile(entry.Path(), func(offset int64) (*http.Response, error) {
+		return c.Download(resourcePath, offset)
+	}, download.Options{
+		Retries:  download.DefaultRetries,
+		Verify:   verify,
+		Progress: io.Discard,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to download %s archive: %w", strings.ToLower(string(level)), err)
+	}
+	entry.Size = result.Size
+	if err := os.Chmod(entry.Path(), 0600); err != nil {
+		return nil, fmt.Errorf("failed to restrict access to trash entry: %w", err)
+	}
+
+	if err := writeEntry(entry); err != nil {
+		return nil, err
+	}
+	saved = true
+	return entry, nil
+}
+
+// createEntryDir creates the directory of a new entry, named after the
+// deletion time, level and the start of the Orthanc ID
+func createEntryDir(root string, level types.ResourceLevel, id string, now time.Time) (string, string, error) {
+	short := id
+	if len(short) > 8 {
+		short = short[:8]
+	}
+	base := fmt.Sprintf("%s-%s-%s", now.Format("20060102-150405"), strings.ToLower(string(level)), short)
+
+	for n := 1; ; n++ {
+		entryID := base
+		if n > 1 {
+			entryID = fmt.Sprintf("%s-%d", base, n)
+		}
+		dir := filepath.Join(root, entryID)
+		err := os.Mkdir(dir, 0700)
+		if err == nil {
+			return entryID, dir, nil
+		}
+		if !errors.Is(err, os.ErrExist) {
+			return "", "", fmt.Errorf("failed to create trash entry: %w", err)
+		}
+	}
+}
+
+// writeEntry writes the metadata file of an entry
+func writeEntry(entry *Entry) error {
+	data, err := json.MarshalIndent(entry, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal trash entry: %w", err)
+	}
+	if err := os.WriteFile(filepath.Join(entry.Dir, metadataFile), data, 0600); err != nil {
+		return fmt.Errorf("failed to write trash entry: %w", err)
+	}
+	return nil
+}
+
+// List returns the entries in the trash, most recently deleted first.
+// Directories without metadata, such as interrupted saves, are skipped.
+func List() ([]*Entry, error) {
+	root, err := Dir()
+	if err != nil {
+		return nil, err
+	}
+
+	dirs, err := os.ReadDir(root)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to read trash directory: %w", err)
+	}
+
+	var entries []*Entry
+	for _, dir := range dirs {
+		if !dir.IsDir() {
+			continue
+		}
+		data, err := os.ReadFile(filepath.Join(root, dir.Name(), metadataFile))
+		if err != nil {
+			continue
+		}
+		var entry Entry
+		if err := json.Unmarshal(data, &entry); err != nil {
+			return nil, fmt.Errorf("invalid trash entry %s: %w", dir.Name(), err)
+		}
+		entry.ID = dir.Name()
+		entry.Dir = filepath.Join(root, dir.Name())
+		entries = append(entries, &entry)
+	}
+
+	sort.SliceStable(entries, func(i, j int) bool {
+		return entries[i].DeletedAt.After(entries[j].DeletedAt)
+	})
+	return entries, nil
+}
+
+// Find returns the entry matching ref: an entry ID, a unique prefix of one,
+// or the Orthanc ID of the deleted resource (the most recent entry wins)
+func Find(ref string) (*Entry, error) {
+	entries, err := List()
+	if err != nil {
+		return nil, err
+	}
+
+	var matches []*Entry
+	for _, entry := range entries {
+		if entry.ID == ref {
+			return entry, nil
+		}
+		if strings.HasPrefix(entry.ID, ref) {
+			matches = append(matches, entry)
+		}
+	}
+	for _, entry := range entries {
+		if entry.ResourceID == ref {
+			return entry, nil
+		}
+	}
+
+	switch len(matches) {
+	case 0:
+		return nil, clierr.NotFound("trash entry %q not found", ref)
+	case 1:
+		return matches[0], nil
+	}
+	return nil, clierr.Validation("%q matches %d trash entries, use a longer prefix", ref, len(matches))
+}
+
+// Remove deletes an entry from the trash
+func Remove(entry *Entry) error {
+	if err := os.RemoveAll(entry.Dir); err != nil {
+		return fmt.Errorf("failed to remove trash entry %s: %w", entry.ID, err)
+	}
+	return nil
+}
+
+// Restore uploads the DICOM files of an entry, with at most workers
+// concurrent uploads. Orthanc derives its IDs from the DICOM UIDs, so the
+// restored resource gets its original Orthanc ID back.
+func Restore(c *client.Client, entry *Entry, workers int) (*RestoreResult, error) {
+	if workers < 1 {
+		return nil, clierr.Validation("--parallel must be at least 1")
+	}
+
+	if !strings.HasSuffix(entry.File, ".zip") {
+		file, err := os.Open(entry.Path())
+		if err != nil {
+			return nil, fmt.Errorf("failed to open trash entry: %w", err)
+		}
+		defer file.Close()
+
+		status, err := upload(c, file)
+		if err != nil {
+			return nil, err
+		}
+		return countStatuses([]string{status}), nil
+	}
+
+	reader, err := zip.OpenReader(entry.Path())
+	if err != nil {
+		return nil, fmt.Errorf("trash entry archive is not a valid ZIP file: %w", err)
+	}
+	defer reader.Close()
+
+	var files []*zip.File
+	for _, file := range reader.File {
+		if file.FileInfo().IsDir() || strings.EqualFold(path.Base(file.Name), "DICOMDIR") {
+			continue
+		}
+		files = append(files, file)
+	}
+
+	statuses := make([]string, len(files))
+	errs := parallel.ForEach(len(files), workers, func(i int) error {
+		file, err := files[i].Open()
+		if err != nil {
+			return fmt.Errorf("failed to read %s: %w", files[i].Name, err)
+		}
+		defer file.Close()
+
+		statuses[i], err = upload(c, file)
+		return err
+	})
+
+	result := countStatuses(statuses)
+	if failed := parallel.Failed(errs); failed > 0 {
+		for _, err := range errs {
+			if err != nil {
+				return result, fmt.Errorf("failed to upload %d of %d files: %w", failed, len(files), err)
+			}
+		}
+	}
+	return result, nil
+}
+
+// upload stores one DICOM file and returns its status, e.g. Success or AlreadyStored
+func upload(c *client.Client, file io.Reader) (string, error) {
+	response, err := c.UploadDicomFile(file)
+	if err != nil {
+		return "", fmt.Errorf("failed to upload DICOM file: %w", err)
+	}
+	return response.Status, nil
+}
+
+// countStatuses summarizes upload statuses, ignoring failed uploads
+func countStatuses(statuses []string) *RestoreResult {
+	result := &RestoreResult{}
+	for _, status := range statuses {
+		switch status {
+		case "":
+		case "AlreadyStored":
+			result.AlreadyStored++
+		default:
+			result.Uploaded++
+		}
+	}
+	return result
+}